@@ -0,0 +1,133 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The loopvarbisect command finds the loop whose per-iteration
+// variables, as made by the compiler's -d=loopvar flag, change the
+// outcome of a command, such as a test.
+//
+// Usage:
+//
+//	go run loopvarbisect.go [-v] command [args...]
+//
+// The command must succeed when built as usual and fail when built
+// with -gcflags=all=-d=loopvar=1. It is run repeatedly with GOFLAGS
+// set to make only the loops whose position hash ends in a given
+// sequence of binary digits per-iteration, lengthening the sequence
+// until it selects a single loop, which is printed. For example:
+//
+//	go run loopvarbisect.go go test ./mypkg
+//
+// If the failure needs several loops together, loopvarbisect prints
+// the smallest set of loops it found to cause it.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var verbose = flag.Bool("v", false, "print the output of each run")
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: loopvarbisect [-v] command [args...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("loopvarbisect: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+	}
+
+	if _, ok := run(""); !ok {
+		log.Fatal("command fails without -d=loopvar")
+	}
+	if _, ok := run("-d=loopvar=1"); ok {
+		log.Fatal("command succeeds with -d=loopvar=1; nothing to find")
+	}
+
+	suffix := ""
+	var loops []string
+	for {
+		next := ""
+		for _, bit := range []string{"0", "1"} {
+			triggered, ok := run("-d=loopvar=1,loopvarhash=" + bit + suffix)
+			if !ok && len(triggered) > 0 {
+				next, loops = bit+suffix, triggered
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		suffix = next
+		if len(loops) == 1 {
+			break
+		}
+	}
+
+	if suffix == "" {
+		log.Fatal("failure needs loops with both hash suffixes 0 and 1")
+	}
+	if len(loops) > 1 {
+		fmt.Printf("failure needs all of these loops (loopvarhash=%s):\n", suffix)
+	} else {
+		fmt.Printf("failure caused by this loop (loopvarhash=%s):\n", suffix)
+	}
+	for _, loop := range loops {
+		fmt.Printf("\t%s\n", loop)
+	}
+}
+
+// triggeredRE matches the lines printed by the compiler
+// for the loops selected by -d=loopvarhash.
+var triggeredRE = regexp.MustCompile(`(?m)^(.*): loopvarhash=[01]+ triggered$`)
+
+// run runs the command with the given compiler flags, and reports
+// the positions of the loops the compiler selected and whether the
+// command succeeded.
+func run(gcflags string) (triggered []string, ok bool) {
+	args := flag.Args()
+	cmd := exec.Command(args[0], args[1:]...)
+	goflags := os.Getenv("GOFLAGS")
+	if gcflags != "" {
+		// Rebuild everything, so that the compiler reports
+		// the selected loops even for cached packages.
+		goflags = strings.TrimSpace(goflags + " -a -gcflags=all=" + gcflags)
+	}
+	cmd.Env = append(os.Environ(), "GOFLAGS="+goflags)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if *verbose {
+		fmt.Fprintf(os.Stderr, "GOFLAGS=%s %s\n%s", goflags, strings.Join(args, " "), out.Bytes())
+	}
+	if err != nil {
+		if _, isExit := err.(*exec.ExitError); !isExit {
+			log.Fatal(err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range triggeredRE.FindAllStringSubmatch(out.String(), -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			triggered = append(triggered, m[1])
+		}
+	}
+	sort.Strings(triggered)
+	return triggered, err == nil
+}
@@ -0,0 +1,369 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"cmd/compile/internal/syntax"
+	"cmd/internal/src"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+)
+
+// Per-iteration loop variables.
+//
+// Starting with go1.14, the variables declared by for statements are
+// per-iteration: each iteration of the loop has its own copy of each
+// variable, so that a closure or pointer that outlives an iteration
+// refers to the value of that iteration, not to a variable shared by
+// all of them. This applies only to packages compiled with -lang=go1.14
+// or later, which the go command passes for modules whose go.mod says
+// go 1.14 or later, so that the loops of older modules keep their
+// meaning. A package compiled without -lang is compiled for the
+// current release, go1.13, and keeps the old semantics.
+//
+// The -d=loopvar flag makes the variables per-iteration regardless of
+// the language version, to test existing code before moving it to
+// go1.14.
+//
+// The noder rewrites the loops concerned before converting them, so
+// that the rest of the compiler sees ordinary code. A range loop
+//
+//	for k, v := range x {
+//		body
+//	}
+//
+// becomes
+//
+//	for .k, .v := range x {
+//		k, v := .k, .v
+//		{
+//			body
+//		}
+//	}
+//
+// and a three-clause loop
+//
+//	for i := 0; cond; post {
+//		body
+//	}
+//
+// becomes
+//
+//	for .i := 0; ; {
+//		i := .i
+//		if !(cond) {
+//			break
+//		}
+//		{
+//			body	// with continue replaced by goto .continueN
+//		}
+//	.continueN:
+//		{
+//			i := i
+//			post
+//			.i = i
+//		}
+//	}
+//
+// so that the post statement of each iteration updates a new copy of
+// the variables, which becomes the copy of the next iteration.
+// The names starting with a dot cannot clash with names in the source.
+//
+// Only loops whose variables may outlive an iteration are rewritten:
+// those whose variables are mentioned in a function literal, or whose
+// address may be taken, by &, a method call or a slice expression.
+//
+// With -d=loopvar=2, the compiler reports each variable it makes
+// per-iteration, and whether it is heap-allocated. The -d=loopvarhash
+// flag limits the rewriting to the loops whose position hashes to
+// a given suffix of binary digits, in the manner of GOSSAHASH,
+// so that the loop responsible for a change in behavior can be found
+// by bisection; see $GOROOT/misc/loopvarbisect.
+
+// Debug_loopvar and Debug_loopvarhash are set by -d=loopvar and -d=loopvarhash.
+var (
+	Debug_loopvar     int
+	Debug_loopvarhash string
+)
+
+// loopvars lists the per-iteration copies of loop variables,
+// for -d=loopvar=2.
+var loopvars []*Node
+
+// loopvarLabels counts the labels created for rewritten loops.
+var loopvarLabels int
+
+// loopvarEnabled reports whether loop variables are per-iteration
+// in the package being compiled.
+func loopvarEnabled() bool {
+	if Debug_loopvar != 0 {
+		return true
+	}
+	// langSupported is true for any version without -lang,
+	// but the current release still shares loop variables.
+	return flag_lang != "" && langSupported(1, 14)
+}
+
+// loopvar rewrites stmt so that its variables are per-iteration,
+// if it declares variables that may outlive an iteration.
+// It reports whether it rewrote stmt.
+func (p *noder) loopvar(stmt *syntax.ForStmt) bool {
+	var lhs syntax.Expr
+	switch init := stmt.Init.(type) {
+	case *syntax.RangeClause:
+		if !init.Def {
+			return false
+		}
+		lhs = init.Lhs
+	case *syntax.AssignStmt:
+		if init.Op != syntax.Def {
+			return false
+		}
+		lhs = init.Lhs
+	default:
+		return false
+	}
+
+	elems := []syntax.Expr{lhs}
+	list, _ := lhs.(*syntax.ListExpr)
+	if list != nil {
+		elems = list.ElemList
+	}
+	var names []*syntax.Name
+	for _, x := range elems {
+		if name, ok := x.(*syntax.Name); ok && name.Value != "_" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 || !loopvarEscapes(stmt, names) || !loopvarHashMatch(p.pos(stmt)) {
+		return false
+	}
+
+	// Replace the variables declared by the loop with hidden ones.
+	var hidden []*syntax.Name
+	for i, x := range elems {
+		if name, ok := x.(*syntax.Name); ok && name.Value != "_" {
+			h := syntax.NewName(name.Pos(), "."+name.Value)
+			hidden = append(hidden, h)
+			elems[i] = h
+		}
+	}
+	if list == nil {
+		switch init := stmt.Init.(type) {
+		case *syntax.RangeClause:
+			init.Lhs = elems[0]
+		case *syntax.AssignStmt:
+			init.Lhs = elems[0]
+		}
+	}
+
+	// The new blocks are placed at the braces of the loop body,
+	// so that the scopes of the function remain in source order.
+	pos, lbrace, rbrace := stmt.Pos(), stmt.Body.Pos(), stmt.Body.Rbrace
+	body := []syntax.Stmt{define(pos, names, hidden)}
+	if stmt.Cond != nil {
+		paren := &syntax.ParenExpr{X: stmt.Cond}
+		not := &syntax.Operation{Op: syntax.Not, X: paren}
+		brk := &syntax.BranchStmt{Tok: syntax.Break}
+		then := &syntax.BlockStmt{List: []syntax.Stmt{brk}, Rbrace: lbrace}
+		cond := &syntax.IfStmt{Cond: not, Then: then}
+		setPos(lbrace, paren, not, brk, then, cond)
+		body = append(body, cond)
+		stmt.Cond = nil
+	}
+	body = append(body, stmt.Body)
+
+	if _, ok := stmt.Init.(*syntax.AssignStmt); ok {
+		// Copy the variables back at the end of each iteration,
+		// and make continue statements jump there.
+		next := []syntax.Stmt{assign(pos, hidden, names)}
+		if stmt.Post != nil {
+			next = []syntax.Stmt{define(pos, names, names), stmt.Post, next[0]}
+			stmt.Post = nil
+		}
+		block := &syntax.BlockStmt{List: next, Rbrace: rbrace}
+		block.SetPos(rbrace)
+		var label *syntax.Name
+		syntax.Inspect(stmt.Body, func(n syntax.Node) bool {
+			switch n := n.(type) {
+			case *syntax.FuncLit:
+				return false
+			case *syntax.BranchStmt:
+				if n.Tok == syntax.Continue && n.Target == stmt {
+					if label == nil {
+						loopvarLabels++
+						label = syntax.NewName(pos, fmt.Sprintf(".continue%d", loopvarLabels))
+					}
+					n.Tok, n.Label, n.Target = syntax.Goto, label, nil
+				}
+			}
+			return true
+		})
+		if label != nil {
+			labeled := &syntax.LabeledStmt{Label: label, Stmt: block}
+			labeled.SetPos(rbrace)
+			body = append(body, labeled)
+		} else {
+			body = append(body, block)
+		}
+	}
+
+	newBody := &syntax.BlockStmt{List: body, Rbrace: rbrace}
+	newBody.SetPos(lbrace)
+	stmt.Body = newBody
+	return true
+}
+
+// define returns the statement lhs := rhs for lists of names.
+func define(pos syntax.Pos, lhs, rhs []*syntax.Name) syntax.Stmt {
+	s := assign(pos, lhs, rhs).(*syntax.AssignStmt)
+	s.Op = syntax.Def
+	return s
+}
+
+// assign returns the statement lhs = rhs for lists of names.
+// The names are copied, so that each syntax node appears only once.
+func assign(pos syntax.Pos, lhs, rhs []*syntax.Name) syntax.Stmt {
+	list := func(names []*syntax.Name) syntax.Expr {
+		elems := make([]syntax.Expr, len(names))
+		for i, n := range names {
+			elems[i] = syntax.NewName(n.Pos(), n.Value)
+		}
+		if len(elems) == 1 {
+			return elems[0]
+		}
+		l := &syntax.ListExpr{ElemList: elems}
+		l.SetPos(pos)
+		return l
+	}
+	s := &syntax.AssignStmt{Lhs: list(lhs), Rhs: list(rhs)}
+	s.SetPos(pos)
+	return s
+}
+
+// setPos sets the position of each of nodes to pos.
+func setPos(pos syntax.Pos, nodes ...interface{ SetPos(syntax.Pos) }) {
+	for _, n := range nodes {
+		n.SetPos(pos)
+	}
+}
+
+// loopvarEscapes reports whether any of the named variables of stmt
+// may outlive an iteration of the loop.
+func loopvarEscapes(stmt *syntax.ForStmt, names []*syntax.Name) bool {
+	vars := make(map[string]bool)
+	for _, n := range names {
+		vars[n.Value] = true
+	}
+
+	// base returns the variable whose address is taken when
+	// the address of x is, or nil.
+	base := func(x syntax.Expr) *syntax.Name {
+		for {
+			switch y := x.(type) {
+			case *syntax.Name:
+				return y
+			case *syntax.ParenExpr:
+				x = y.X
+			case *syntax.SelectorExpr:
+				x = y.X
+			case *syntax.IndexExpr:
+				x = y.X
+			case *syntax.SliceExpr:
+				x = y.X
+			default:
+				return nil
+			}
+		}
+	}
+	mentioned := func(x syntax.Expr) bool {
+		n := base(x)
+		return n != nil && vars[n.Value]
+	}
+
+	escapes := false
+	inspect := func(n syntax.Node) bool {
+		if escapes {
+			return false
+		}
+		switch n := n.(type) {
+		case *syntax.FuncLit:
+			syntax.Inspect(n.Body, func(n syntax.Node) bool {
+				if n, ok := n.(*syntax.Name); ok && vars[n.Value] {
+					escapes = true
+				}
+				return !escapes
+			})
+			return false
+		case *syntax.Operation:
+			if n.Op == syntax.And && n.Y == nil && mentioned(n.X) {
+				escapes = true
+			}
+		case *syntax.SelectorExpr:
+			escapes = mentioned(n.X)
+		case *syntax.SliceExpr:
+			escapes = mentioned(n.X)
+		}
+		return !escapes
+	}
+	if stmt.Cond != nil {
+		syntax.Inspect(stmt.Cond, inspect)
+	}
+	if stmt.Post != nil {
+		syntax.Inspect(stmt.Post, inspect)
+	}
+	syntax.Inspect(stmt.Body, inspect)
+	return escapes
+}
+
+// loopvarHashMatch reports whether the loop at pos is selected by
+// -d=loopvarhash: whether the setting is a suffix of the binary digits
+// of the SHA-1 hash of the loop's position. With -d=loopvarhash set,
+// each selected loop is reported, so that bisection can identify it.
+func loopvarHashMatch(pos src.XPos) bool {
+	if Debug_loopvarhash == "" {
+		return true
+	}
+	var bits strings.Builder
+	for _, b := range sha1.Sum([]byte(linestr(pos))) {
+		fmt.Fprintf(&bits, "%08b", b)
+	}
+	if !strings.HasSuffix(bits.String(), Debug_loopvarhash) {
+		return false
+	}
+	Warnl(pos, "loopvarhash=%s triggered", Debug_loopvarhash)
+	return true
+}
+
+// loopvarDefined records the per-iteration variables defined by the
+// first statement of the rewritten body of a loop, for reporting.
+func loopvarDefined(body []*Node) {
+	if Debug_loopvar < 2 || len(body) == 0 {
+		return
+	}
+	n := body[0]
+	if n.Op == OAS {
+		loopvars = append(loopvars, n.Left)
+	} else {
+		loopvars = append(loopvars, n.List.Slice()...)
+	}
+}
+
+// loopvarReport reports the per-iteration loop variables and
+// whether escape analysis placed them on the heap.
+func loopvarReport() {
+	for _, n := range loopvars {
+		if n.Op != ONAME {
+			continue
+		}
+		where := "stack-allocated"
+		if n.Class() == PAUTOHEAP {
+			where = "heap-allocated"
+		}
+		Warnl(n.Pos, "loop variable %v now per-iteration, %s", n.Sym, where)
+	}
+	loopvars = nil
+}
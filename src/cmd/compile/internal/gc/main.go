@@ -81,6 +81,8 @@ var debugtab = []struct {
 	{"typecheckinl", "eager typechecking of inline function bodies", &Debug_typecheckinl},
 	{"dwarfinl", "print information about DWARF inlined function creation", &Debug_gendwarfinl},
 	{"softfloat", "force compiler to emit soft-float code", &Debug_softfloat},
+	{"loopvar", "make loop variables per-iteration before go1.14 (1), and report them (2)", &Debug_loopvar},
+	{"loopvarhash", "make only loops with matching position hash per-iteration", &Debug_loopvarhash},
 }
 
 const debugHelpHeader = `usage: -d arg[,arg]* and arg is <key>[=<value>]
@@ -635,6 +637,9 @@ func Main(archInit func(*Arch)) {
 	// because large values may contain pointers, it must happen early.
 	timings.Start("fe", "escapes")
 	escapes(xtop)
+	if Debug_loopvar >= 2 {
+		loopvarReport()
+	}
 
 	// Collect information for go:nowritebarrierrec
 	// checking. This must happen before transformclosure.
@@ -1437,6 +1442,13 @@ func currentLang() string {
 	return fmt.Sprintf("go1.%d", goversion.Version)
 }
 
+// nextLang returns the language version of the next release.
+// The -lang flag accepts it, so that language changes planned for
+// that release can be enabled and tested ahead of it.
+func nextLang() string {
+	return fmt.Sprintf("go1.%d", goversion.Version+1)
+}
+
 // goVersionRE is a regular expression that matches the valid
 // arguments to the -lang flag.
 var goVersionRE = regexp.MustCompile(`^go([1-9][0-9]*)\.(0|[1-9][0-9]*)$`)
@@ -1472,7 +1484,7 @@ func checkLang() {
 		log.Fatalf("invalid value %q for -lang: %v", flag_lang, err)
 	}
 
-	if def := currentLang(); flag_lang != def {
+	if def := nextLang(); flag_lang != def {
 		defVers, err := parseLang(def)
 		if err != nil {
 			log.Fatalf("internal error parsing default lang %q: %v", def, err)
@@ -1114,6 +1114,7 @@ func (p *noder) ifStmt(stmt *syntax.IfStmt) *Node {
 }
 
 func (p *noder) forStmt(stmt *syntax.ForStmt) *Node {
+	perIteration := loopvarEnabled() && p.loopvar(stmt)
 	p.openScope(stmt.Pos())
 	var n *Node
 	if r, ok := stmt.Init.(*syntax.RangeClause); ok {
@@ -1138,6 +1139,9 @@ func (p *noder) forStmt(stmt *syntax.ForStmt) *Node {
 		}
 	}
 	n.Nbody.Set(p.blockStmt(stmt.Body))
+	if perIteration {
+		loopvarDefined(n.Nbody.Slice())
+	}
 	p.closeAnotherScope()
 	return n
 }
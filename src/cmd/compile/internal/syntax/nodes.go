@@ -26,8 +26,9 @@ type node struct {
 	pos Pos
 }
 
-func (n *node) Pos() Pos { return n.pos }
-func (*node) aNode()     {}
+func (n *node) Pos() Pos       { return n.pos }
+func (n *node) SetPos(pos Pos) { n.pos = pos }
+func (*node) aNode()           {}
 
 // ----------------------------------------------------------------------------
 // Files
@@ -298,6 +299,14 @@ type expr struct{ node }
 
 func (*expr) aExpr() {}
 
+// NewName returns a new name with the given position and value.
+func NewName(pos Pos, value string) *Name {
+	n := new(Name)
+	n.pos = pos
+	n.Value = value
+	return n
+}
+
 type ChanDir uint
 
 const (
@@ -0,0 +1,213 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements syntax tree walking.
+
+package syntax
+
+// Inspect traverses a syntax tree in depth-first order: it starts by
+// calling f(root); root must not be nil. If f returns true, Inspect
+// invokes f recursively for each of the non-nil children of root.
+func Inspect(root Node, f func(Node) bool) {
+	if !f(root) {
+		return
+	}
+	switch n := root.(type) {
+	// files and declarations
+	case *File:
+		inspectName(n.PkgName, f)
+		for _, d := range n.DeclList {
+			Inspect(d, f)
+		}
+	case *ImportDecl:
+		inspectName(n.LocalPkgName, f)
+		Inspect(n.Path, f)
+	case *ConstDecl:
+		inspectNames(n.NameList, f)
+		inspectOpt(n.Type, f)
+		inspectOpt(n.Values, f)
+	case *TypeDecl:
+		Inspect(n.Name, f)
+		Inspect(n.Type, f)
+	case *VarDecl:
+		inspectNames(n.NameList, f)
+		inspectOpt(n.Type, f)
+		inspectOpt(n.Values, f)
+	case *FuncDecl:
+		if n.Recv != nil {
+			Inspect(n.Recv, f)
+		}
+		Inspect(n.Name, f)
+		Inspect(n.Type, f)
+		if n.Body != nil {
+			Inspect(n.Body, f)
+		}
+
+	// expressions
+	case *BadExpr, *Name, *BasicLit:
+		// no children
+	case *CompositeLit:
+		inspectOpt(n.Type, f)
+		inspectExprs(n.ElemList, f)
+	case *KeyValueExpr:
+		Inspect(n.Key, f)
+		Inspect(n.Value, f)
+	case *FuncLit:
+		Inspect(n.Type, f)
+		Inspect(n.Body, f)
+	case *ParenExpr:
+		Inspect(n.X, f)
+	case *SelectorExpr:
+		Inspect(n.X, f)
+		Inspect(n.Sel, f)
+	case *IndexExpr:
+		Inspect(n.X, f)
+		Inspect(n.Index, f)
+	case *SliceExpr:
+		Inspect(n.X, f)
+		for _, x := range n.Index {
+			inspectOpt(x, f)
+		}
+	case *AssertExpr:
+		Inspect(n.X, f)
+		Inspect(n.Type, f)
+	case *TypeSwitchGuard:
+		inspectName(n.Lhs, f)
+		Inspect(n.X, f)
+	case *Operation:
+		Inspect(n.X, f)
+		inspectOpt(n.Y, f)
+	case *CallExpr:
+		Inspect(n.Fun, f)
+		inspectExprs(n.ArgList, f)
+	case *ListExpr:
+		inspectExprs(n.ElemList, f)
+
+	// types
+	case *ArrayType:
+		inspectOpt(n.Len, f)
+		Inspect(n.Elem, f)
+	case *SliceType:
+		Inspect(n.Elem, f)
+	case *DotsType:
+		Inspect(n.Elem, f)
+	case *StructType:
+		inspectFields(n.FieldList, f)
+		for _, t := range n.TagList {
+			if t != nil {
+				Inspect(t, f)
+			}
+		}
+	case *Field:
+		inspectName(n.Name, f)
+		Inspect(n.Type, f)
+	case *InterfaceType:
+		inspectFields(n.MethodList, f)
+	case *FuncType:
+		inspectFields(n.ParamList, f)
+		inspectFields(n.ResultList, f)
+	case *MapType:
+		Inspect(n.Key, f)
+		Inspect(n.Value, f)
+	case *ChanType:
+		Inspect(n.Elem, f)
+
+	// statements
+	case *EmptyStmt:
+		// no children
+	case *LabeledStmt:
+		Inspect(n.Label, f)
+		inspectOpt(n.Stmt, f)
+	case *BlockStmt:
+		inspectStmts(n.List, f)
+	case *ExprStmt:
+		Inspect(n.X, f)
+	case *SendStmt:
+		Inspect(n.Chan, f)
+		Inspect(n.Value, f)
+	case *DeclStmt:
+		for _, d := range n.DeclList {
+			Inspect(d, f)
+		}
+	case *AssignStmt:
+		Inspect(n.Lhs, f)
+		inspectOpt(n.Rhs, f)
+	case *BranchStmt:
+		inspectName(n.Label, f)
+	case *CallStmt:
+		Inspect(n.Call, f)
+	case *ReturnStmt:
+		inspectOpt(n.Results, f)
+	case *IfStmt:
+		inspectOpt(n.Init, f)
+		Inspect(n.Cond, f)
+		Inspect(n.Then, f)
+		inspectOpt(n.Else, f)
+	case *ForStmt:
+		inspectOpt(n.Init, f)
+		inspectOpt(n.Cond, f)
+		inspectOpt(n.Post, f)
+		Inspect(n.Body, f)
+	case *SwitchStmt:
+		inspectOpt(n.Init, f)
+		inspectOpt(n.Tag, f)
+		for _, c := range n.Body {
+			Inspect(c, f)
+		}
+	case *SelectStmt:
+		for _, c := range n.Body {
+			Inspect(c, f)
+		}
+	case *RangeClause:
+		inspectOpt(n.Lhs, f)
+		Inspect(n.X, f)
+	case *CaseClause:
+		inspectOpt(n.Cases, f)
+		inspectStmts(n.Body, f)
+	case *CommClause:
+		inspectOpt(n.Comm, f)
+		inspectStmts(n.Body, f)
+
+	default:
+		panic("unhandled node type")
+	}
+}
+
+// inspectOpt calls Inspect(n, f) if n is not nil.
+func inspectOpt(n Node, f func(Node) bool) {
+	if n != nil {
+		Inspect(n, f)
+	}
+}
+
+// inspectName calls Inspect(n, f) if n is not nil.
+func inspectName(n *Name, f func(Node) bool) {
+	if n != nil {
+		Inspect(n, f)
+	}
+}
+
+func inspectNames(list []*Name, f func(Node) bool) {
+	for _, n := range list {
+		Inspect(n, f)
+	}
+}
+
+func inspectExprs(list []Expr, f func(Node) bool) {
+	for _, x := range list {
+		Inspect(x, f)
+	}
+}
+
+func inspectFields(list []*Field, f func(Node) bool) {
+	for _, x := range list {
+		Inspect(x, f)
+	}
+}
+
+func inspectStmts(list []Stmt, f func(Node) bool) {
+	for _, s := range list {
+		Inspect(s, f)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	const src = `package p
+
+func f(x []int) (sum int) {
+	for i, v := range x {
+		if v > 0 {
+			sum += v * i
+		}
+	}
+	go func() { sum-- }()
+	return
+}
+`
+	ast, err := Parse(nil, strings.NewReader(src), nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Collect the names in the order visited,
+	// skipping the bodies of function literals.
+	var names []string
+	Inspect(ast, func(n Node) bool {
+		switch n := n.(type) {
+		case *Name:
+			names = append(names, n.Value)
+		case *FuncLit:
+			return false
+		}
+		return true
+	})
+	const want = "p f x int sum int i v x v sum v i"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("got names %q, want %q", got, want)
+	}
+}
@@ -51,6 +51,7 @@
 // 	module-auth module authentication using go.sum
 // 	testflag    testing flags
 // 	testfunc    testing functions
+// 	toolchain   Go toolchain selection
 //
 // Use "go help <topic>" for more information about that topic.
 //
@@ -865,6 +866,7 @@
 //         Replace   *Module      // replaced by this module
 //         Time      *time.Time   // time version was created
 //         Update    *Module      // available update, if any (with -u)
+//         Retracted []string     // retraction rationale, if retracted (with -u)
 //         Main      bool         // is this the main module?
 //         Indirect  bool         // is this module only an indirect dependency of main module?
 //         Dir       string       // directory holding files for this module, if any
@@ -907,6 +909,13 @@
 //     golang.org/x/text v0.3.0 [v0.4.0] => /tmp/text
 //     rsc.io/pdf v0.1.1 [v0.1.2]
 //
+// If the current version of a module has been retracted by its author,
+// list -u sets the Module's Retracted field to the rationale given for
+// the retraction, and the String method appends "(retracted)" to the
+// version:
+//
+//     rsc.io/quote v1.5.1 [v1.5.2] (retracted)
+//
 // (For tools, 'go list -m -u -json all' may be more convenient to parse.)
 //
 // The -versions flag causes list to set the Module's Versions field
@@ -963,6 +972,7 @@
 // 	tidy        add missing and remove unused modules
 // 	vendor      make vendored copy of dependencies
 // 	verify      verify dependencies have expected content
+// 	vuln        report known vulnerabilities in dependencies
 // 	why         explain why packages or modules are needed
 //
 // Use "go help mod <command>" for more information about a command.
@@ -1156,6 +1166,44 @@
 // non-zero status.
 //
 //
+// Report known vulnerabilities in dependencies
+//
+// Usage:
+//
+// 	go mod vuln [-v] [packages]
+//
+// Vuln checks the modules providing the named packages and their
+// dependencies against a database of known vulnerabilities, and reports
+// the vulnerabilities whose affected functions are reachable from the
+// named packages. If no packages are named, vuln checks the packages of
+// the main module ("./...").
+//
+// The database is read from the location set by GOVULNDB, which must be
+// a file:// URL or an absolute path of a local directory, so that it can
+// be used without network access. The directory holds JSON files each
+// containing a vulnerability report in the OSV format
+// (https://ossf.github.io/osv-schema/), or an array of them, as in the
+// offline exports of OSV databases. Reports apply to the build list when
+// the ecosystem of an affected package is "Go" and its name is a module
+// path; the module versions affected are given by SEMVER ranges, and the
+// affected packages and symbols by the ecosystem_specific "imports" list.
+// A symbol is a function name or a method name qualified by its receiver
+// type, such as "Reader.Read".
+//
+// To decide whether an affected symbol is reachable, vuln type-checks
+// the packages and follows the calls and references to functions from
+// all the functions declared in the named packages and from the package
+// initialization of all their dependencies. A call of an interface method
+// is taken to reach the method of every type implementing the interface.
+// Calls made from within the standard library, such as fmt calling the
+// String method of a value, are not followed.
+//
+// For each reachable vulnerability, vuln prints the report ID, the module
+// version, the summary, and a chain of calls reaching the affected
+// symbol, and exits with a non-zero status. The -v flag also lists the
+// vulnerabilities of the modules in the build list that are not reached.
+//
+//
 // Explain why packages or modules are needed
 //
 // Usage:
@@ -1295,8 +1343,9 @@
 // The rule for a match in the cache is that the run involves the same
 // test binary and the flags on the command line come entirely from a
 // restricted set of 'cacheable' test flags, defined as -cpu, -list,
-// -parallel, -run, -short, and -v. If a run of go test has any test
-// or non-test flags outside this set, the result is not cached. To
+// -parallel, -run, -short, -shuffle with a seed or off, and -v.
+// If a run of go test has any test or non-test flags outside this set,
+// or uses -shuffle=on, the result is not cached. To
 // disable test caching, use any test flag or argument other than the
 // cacheable flags. The idiomatic way to disable test caching explicitly
 // is to use -count=1. Tests that open files within the package's source
@@ -1571,6 +1620,13 @@
 // 	GOTMPDIR
 // 		The directory where the go command will write
 // 		temporary source files, packages, and binaries.
+// 	GOTOOLCHAIN
+// 		Controls which Go toolchain runs go commands: auto, local,
+// 		or a Go release such as go1.14. See 'go help toolchain'.
+// 	GOVULNDB
+// 		The location of the vulnerability database used by
+// 		'go mod vuln': a file:// URL or an absolute directory path.
+// 		See 'go help mod vuln'.
 //
 // Environment variables for use with cgo:
 //
@@ -1721,16 +1777,45 @@
 // 	require new/thing/v2 v2.3.4
 // 	exclude old/thing v1.2.3
 // 	replace bad/thing v1.4.5 => good/thing v1.4.5
+// 	retract v1.5.6
 //
 // The verbs are
 // 	module, to define the module path;
 // 	go, to set the expected language version;
 // 	require, to require a particular module at a given version or later;
-// 	exclude, to exclude a particular module version from use; and
-// 	replace, to replace a module version with a different module version.
+// 	exclude, to exclude a particular module version from use;
+// 	replace, to replace a module version with a different module version; and
+// 	retract, to indicate a previously released version should not be used.
 // Exclude and replace apply only in the main module's go.mod and are ignored
 // in dependencies.  See https://research.swtch.com/vgo-mvs for details.
 //
+// Retract marks a version of the module defined by the go.mod file, or a
+// closed interval of versions such as [v1.0.0, v1.0.5], as broken or
+// published by mistake. A comment before or after the directive gives
+// the rationale for the retraction:
+//
+// 	// Published before the API was finalized.
+// 	retract [v1.0.0, v1.0.5]
+//
+// Retractions are read from the go.mod file of the latest version of a
+// module, so an author retracts a version by publishing a new one.
+// Version queries such as "latest" or "v1.2" skip retracted versions
+// unless no other version matches, and 'go get -u' does not upgrade a
+// module to a retracted version. Only 'go get', which warns when a module
+// it resolves is at a retracted version, and 'go list -m -u', which
+// reports retracted versions, check for retractions: other commands,
+// such as 'go build', do not, as that would require reading the latest
+// go.mod file of every module in the build.
+//
+// When the main module's go.mod declares go 1.14 or later, the module
+// graph is pruned: the go.mod file of a go 1.14 module is expected to
+// require every module providing packages to it, so the go command
+// reads the requirements of the modules it lists but not their own
+// go.mod files. 'go mod tidy' maintains that expanded list of
+// requirements, marking the dependencies that are not imported
+// directly as // indirect. Modules declaring an earlier go version
+// contribute all their transitive requirements.
+//
 // The leading verb can be factored out of adjacent lines to create a block,
 // like in Go imports:
 //
@@ -2494,7 +2579,9 @@
 //
 // Module versions disallowed by exclude statements in the
 // main module's go.mod are considered unavailable and cannot
-// be returned by queries.
+// be returned by queries. Queries other than a specific version
+// or revision prefer versions that have not been retracted by the
+// module author (see 'go help go.mod').
 //
 // For example, these commands are all valid:
 //
@@ -2889,6 +2976,13 @@
 // 	    the Go tree can run a sanity check but not spend time running
 // 	    exhaustive tests.
 //
+// 	-shuffle off,on,N
+// 	    Randomize the execution order of tests and benchmarks.
+// 	    It is off by default. If -shuffle is set to on, then it will seed
+// 	    the randomizer using the system clock. If -shuffle is set to an
+// 	    integer N, then N will be used as the seed value. In both cases,
+// 	    the seed will be reported for reproducibility.
+//
 // 	-timeout d
 // 	    If a test binary runs longer than duration d, panic.
 // 	    If d is 0, the timeout is disabled.
@@ -3081,4 +3175,40 @@
 // See the documentation of the testing package for more information.
 //
 //
+// Go toolchain selection
+//
+// The go line in the main module's go.mod file states the minimum version
+// of Go needed to build the module. When the go command finds that the
+// go line requires a newer version of Go than its own, it can download
+// that version of the Go toolchain and run it instead, so that the module
+// is always built by a toolchain that supports it.
+//
+// The GOTOOLCHAIN environment variable controls this selection:
+//
+// 	GOTOOLCHAIN=auto
+// 		Use the local toolchain unless the go line requires a newer one,
+// 		in which case download and run the required version.
+// 		This is the default.
+// 	GOTOOLCHAIN=local
+// 		Always use the local toolchain, and fail if the go line
+// 		requires a newer one.
+// 	GOTOOLCHAIN=go1.N or go1.N.P
+// 		Always use the named Go release, downloading it if it is
+// 		not the local toolchain.
+//
+// Toolchains are downloaded as versions of the module golang.org/toolchain
+// named for the release and the host system, such as
+// golang.org/toolchain@v0.0.1-go1.14.linux-amd64, through the module proxy
+// set by GOPROXY. They are stored in the module cache like other modules.
+// A downloaded toolchain is always verified against the checksum database
+// set by GOSUMDB, so the go command refuses to download one when GOSUMDB
+// is off or when GONOSUMDB matches golang.org/toolchain.
+//
+// The go command runs the selected toolchain with GOTOOLCHAIN=local,
+// so that it does not select a toolchain again.
+//
+// The 'go env' and 'go mod edit' commands always use the local toolchain,
+// so that GOTOOLCHAIN and the go line can be changed with them.
+//
+//
 package main
@@ -274,7 +274,9 @@ var knownEnv = `
 	GOROOT
 	GOSUMDB
 	GOTMPDIR
+	GOTOOLCHAIN
 	GOTOOLDIR
+	GOVULNDB
 	GOWASM
 	GO_EXTLINK_ENABLED
 	PKG_CONFIG
@@ -82,7 +82,9 @@ func MkEnv() []cfg.EnvVar {
 		{Name: "GOROOT", Value: cfg.GOROOT},
 		{Name: "GOSUMDB", Value: cfg.GOSUMDB},
 		{Name: "GOTMPDIR", Value: cfg.Getenv("GOTMPDIR")},
+		{Name: "GOTOOLCHAIN", Value: envOr("GOTOOLCHAIN", "auto")},
 		{Name: "GOTOOLDIR", Value: base.ToolDir},
+		{Name: "GOVULNDB", Value: cfg.Getenv("GOVULNDB")},
 	}
 
 	if work.GccgoBin != "" {
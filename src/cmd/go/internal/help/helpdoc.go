@@ -515,6 +515,13 @@ General-purpose environment variables:
 	GOTMPDIR
 		The directory where the go command will write
 		temporary source files, packages, and binaries.
+	GOTOOLCHAIN
+		Controls which Go toolchain runs go commands: auto, local,
+		or a Go release such as go1.14. See 'go help toolchain'.
+	GOVULNDB
+		The location of the vulnerability database used by
+		'go mod vuln': a file:// URL or an absolute directory path.
+		See 'go help mod vuln'.
 
 Environment variables for use with cgo:
 
@@ -208,6 +208,7 @@ applied to a Go struct, but now a Module struct:
         Replace   *Module      // replaced by this module
         Time      *time.Time   // time version was created
         Update    *Module      // available update, if any (with -u)
+        Retracted []string     // retraction rationale, if retracted (with -u)
         Main      bool         // is this the main module?
         Indirect  bool         // is this module only an indirect dependency of main module?
         Dir       string       // directory holding files for this module, if any
@@ -250,6 +251,13 @@ For example, 'go list -m -u all' might print:
     golang.org/x/text v0.3.0 [v0.4.0] => /tmp/text
     rsc.io/pdf v0.1.1 [v0.1.2]
 
+If the current version of a module has been retracted by its author,
+list -u sets the Module's Retracted field to the rationale given for
+the retraction, and the String method appends "(retracted)" to the
+version:
+
+    rsc.io/quote v1.5.1 [v1.5.2] (retracted)
+
 (For tools, 'go list -m -u -json all' may be more convenient to parse.)
 
 The -versions flag causes list to set the Module's Versions field
@@ -26,6 +26,7 @@ See 'go help modules' for an overview of module functionality.
 		cmdTidy,
 		cmdVendor,
 		cmdVerify,
+		cmdVuln,
 		cmdWhy,
 	},
 }
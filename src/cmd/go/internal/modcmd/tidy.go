@@ -77,8 +77,9 @@ func modTidyGoSum() {
 	reqs := modload.Reqs()
 	keep := make(map[module.Version]bool)
 	replaced := make(map[module.Version]bool)
-	var walk func(module.Version)
-	walk = func(m module.Version) {
+	expanded := make(map[module.Version]bool)
+	var walk func(m module.Version, expand bool)
+	walk = func(m module.Version, expand bool) {
 		// If we build using a replacement module, keep the sum for the replacement,
 		// since that's the code we'll actually use during a build.
 		//
@@ -91,13 +92,18 @@ func modTidyGoSum() {
 			keep[r] = true
 			replaced[m] = true
 		}
+		// In a pruned module graph, the requirements of the modules
+		// listed by a pruned go.mod file are not part of the graph.
+		if !expand || expanded[m] {
+			return
+		}
+		expanded[m] = true
 		list, _ := reqs.Required(m)
+		expand = !modload.Pruned(reqs, m)
 		for _, r := range list {
-			if !keep[r] && !replaced[r] {
-				walk(r)
-			}
+			walk(r, expand)
 		}
 	}
-	walk(modload.Target)
+	walk(modload.Target, true)
 	modfetch.TrimGoSum(keep)
 }
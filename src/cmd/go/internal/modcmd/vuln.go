@@ -0,0 +1,373 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modcmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/cfg"
+	"cmd/go/internal/load"
+	"cmd/go/internal/modload"
+)
+
+var cmdVuln = &base.Command{
+	UsageLine: "go mod vuln [-v] [packages]",
+	Short:     "report known vulnerabilities in dependencies",
+	Long: `
+Vuln checks the modules providing the named packages and their
+dependencies against a database of known vulnerabilities, and reports
+the vulnerabilities whose affected functions are reachable from the
+named packages. If no packages are named, vuln checks the packages of
+the main module ("./...").
+
+The database is read from the location set by GOVULNDB, which must be
+a file:// URL or an absolute path of a local directory, so that it can
+be used without network access. The directory holds JSON files each
+containing a vulnerability report in the OSV format
+(https://ossf.github.io/osv-schema/), or an array of them, as in the
+offline exports of OSV databases. Reports apply to the build list when
+the ecosystem of an affected package is "Go" and its name is a module
+path; the module versions affected are given by SEMVER ranges, and the
+affected packages and symbols by the ecosystem_specific "imports" list.
+A symbol is a function name or a method name qualified by its receiver
+type, such as "Reader.Read".
+
+To decide whether an affected symbol is reachable, vuln type-checks
+the packages and follows the calls and references to functions from
+all the functions declared in the named packages and from the package
+initialization of all their dependencies. A call of an interface method
+is taken to reach the method of every type implementing the interface.
+Calls made from within the standard library, such as fmt calling the
+String method of a value, are not followed.
+
+For each reachable vulnerability, vuln prints the report ID, the module
+version, the summary, and a chain of calls reaching the affected
+symbol, and exits with a non-zero status. The -v flag also lists the
+vulnerabilities of the modules in the build list that are not reached.
+	`,
+}
+
+var vulnV = cmdVuln.Flag.Bool("v", false, "")
+
+func init() {
+	cmdVuln.Run = runVuln // break init cycle
+}
+
+func runVuln(cmd *base.Command, args []string) {
+	db := cfg.Getenv("GOVULNDB")
+	if db == "" {
+		base.Fatalf("go mod vuln: GOVULNDB not set; see 'go help mod vuln'")
+	}
+	vulns, err := readVulnDB(db)
+	if err != nil {
+		base.Fatalf("go mod vuln: %v", err)
+	}
+
+	if !modload.Enabled() {
+		base.Fatalf("go mod vuln: cannot check modules outside a module")
+	}
+	if len(args) == 0 {
+		args = []string{"./..."}
+	}
+	roots := load.Packages(args)
+	base.ExitIfErrors()
+	pkgs := load.PackageList(roots)
+
+	g := newVulnGraph()
+	for _, p := range pkgs {
+		g.check(p)
+	}
+	for _, p := range roots {
+		for _, fn := range g.funcs[p.ImportPath] {
+			g.reach(fn, nil)
+		}
+	}
+	for _, p := range pkgs {
+		g.reach(vulnFunc{p.ImportPath, "init"}, nil)
+	}
+	g.walk()
+
+	// Check the vulnerabilities of each module providing packages.
+	type modVersion struct{ path, version string }
+	var mods []modVersion
+	seen := make(map[modVersion]bool)
+	pkgsByMod := make(map[modVersion][]*load.Package)
+	for _, p := range pkgs {
+		if p.Module == nil || p.Module.Main || p.Module.Version == "" {
+			continue
+		}
+		m := modVersion{p.Module.Path, p.Module.Version}
+		if !seen[m] {
+			seen[m] = true
+			mods = append(mods, m)
+		}
+		pkgsByMod[m] = append(pkgsByMod[m], p)
+	}
+	sort.Slice(mods, func(i, j int) bool { return mods[i].path < mods[j].path })
+
+	found := false
+	var unreached []string
+	for _, m := range mods {
+		for _, e := range vulns[m.path] {
+			imports, fixed, ok := e.affects(m.path, m.version)
+			if !ok {
+				continue
+			}
+			fix := "no fixed version"
+			if fixed != "" {
+				fix = "fixed in " + fixed
+			}
+			header := fmt.Sprintf("%s: %s@%s: %s (%s)", e.ID, m.path, m.version, strings.TrimSpace(e.Summary), fix)
+			stack := g.reached(pkgsByMod[m], imports)
+			if stack == nil {
+				unreached = append(unreached, header)
+				continue
+			}
+			found = true
+			fmt.Println(header)
+			for _, fn := range stack {
+				fmt.Printf("\t%s\n", fn)
+			}
+		}
+	}
+	if *vulnV && len(unreached) > 0 {
+		if found {
+			fmt.Println()
+		}
+		fmt.Println("# not reached")
+		for _, h := range unreached {
+			fmt.Println(h)
+		}
+	}
+	if found {
+		base.SetExitStatus(1)
+	}
+}
+
+// A vulnFunc is a node of the call graph: a function or method, whose
+// name is qualified by its receiver type, as in OSV reports. The name
+// "init" stands for the initialization of the package.
+type vulnFunc struct {
+	pkg  string
+	name string
+}
+
+func (f vulnFunc) String() string {
+	return f.pkg + "." + f.name
+}
+
+// A vulnGraph is the call graph of the type-checked packages.
+type vulnGraph struct {
+	fset    *token.FileSet
+	types   map[string]*types.Package
+	funcs   map[string][]vulnFunc      // functions declared in each package
+	uses    map[vulnFunc][]*types.Func // functions referenced by each function
+	methods map[string][]*types.Func   // concrete methods by name
+	parent  map[vulnFunc]*vulnFunc     // reached functions, and the caller reaching them
+	queue   []vulnFunc
+}
+
+func newVulnGraph() *vulnGraph {
+	return &vulnGraph{
+		fset:    token.NewFileSet(),
+		types:   make(map[string]*types.Package),
+		funcs:   make(map[string][]vulnFunc),
+		uses:    make(map[vulnFunc][]*types.Func),
+		methods: make(map[string][]*types.Func),
+		parent:  make(map[vulnFunc]*vulnFunc),
+	}
+}
+
+// check type-checks p, whose dependencies have already been checked, and
+// records the functions it declares and their references to functions.
+// The bodies of functions in the standard library are not checked.
+func (g *vulnGraph) check(p *load.Package) {
+	var files []*ast.File
+	for _, name := range append(append([]string(nil), p.GoFiles...), p.CgoFiles...) {
+		f, err := parser.ParseFile(g.fset, filepath.Join(p.Dir, name), nil, 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go mod vuln: %v\n", err)
+			continue
+		}
+		files = append(files, f)
+	}
+	conf := &types.Config{
+		Importer:         vulnImporter{g, p},
+		FakeImportC:      true,
+		IgnoreFuncBodies: p.Standard,
+		Error:            func(error) {}, // check as much as possible
+	}
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	pkg, _ := conf.Check(p.ImportPath, g.fset, files, info)
+	g.types[p.ImportPath] = pkg
+	if p.Standard {
+		return
+	}
+
+	initFunc := vulnFunc{p.ImportPath, "init"}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				fn, _ := info.Defs[decl.Name].(*types.Func)
+				if fn == nil {
+					continue
+				}
+				node := initFunc
+				if decl.Recv != nil || decl.Name.Name != "init" {
+					node = funcNode(fn)
+					g.funcs[p.ImportPath] = append(g.funcs[p.ImportPath], node)
+					if decl.Recv != nil {
+						g.methods[fn.Name()] = append(g.methods[fn.Name()], fn)
+					}
+				}
+				g.addUses(node, decl.Body, info)
+			case *ast.GenDecl:
+				// Package-level variable initializers run at initialization.
+				g.addUses(initFunc, decl, info)
+			}
+		}
+	}
+}
+
+// addUses records the functions referenced in n as used by node.
+func (g *vulnGraph) addUses(node vulnFunc, n ast.Node, info *types.Info) {
+	if n == nil {
+		return
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if fn, ok := info.Uses[id].(*types.Func); ok {
+				g.uses[node] = append(g.uses[node], fn)
+			}
+		}
+		return true
+	})
+}
+
+// funcNode returns the call graph node for fn.
+func funcNode(fn *types.Func) vulnFunc {
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	pkg := ""
+	if fn.Pkg() != nil {
+		pkg = fn.Pkg().Path()
+	}
+	return vulnFunc{pkg, name}
+}
+
+// reach marks fn as reached from caller, if it was not reached before.
+func (g *vulnGraph) reach(fn vulnFunc, caller *vulnFunc) {
+	if _, ok := g.parent[fn]; ok {
+		return
+	}
+	g.parent[fn] = caller
+	g.queue = append(g.queue, fn)
+}
+
+// walk marks the functions reachable from those reached so far.
+func (g *vulnGraph) walk() {
+	for len(g.queue) > 0 {
+		fn := g.queue[0]
+		g.queue = g.queue[1:]
+		caller := fn
+		for _, use := range g.uses[fn] {
+			recv := use.Type().(*types.Signature).Recv()
+			if recv == nil || !types.IsInterface(recv.Type()) {
+				g.reach(funcNode(use), &caller)
+				continue
+			}
+			// A call of an interface method reaches the methods of
+			// that name of all the types implementing the interface.
+			iface := recv.Type().Underlying().(*types.Interface)
+			for _, m := range g.methods[use.Name()] {
+				t := m.Type().(*types.Signature).Recv().Type()
+				if types.Implements(t, iface) || !types.IsInterface(t) && types.Implements(types.NewPointer(t), iface) {
+					g.reach(funcNode(m), &caller)
+				}
+			}
+		}
+	}
+}
+
+// reached returns a chain of calls from a root function to a function
+// affected by a vulnerability in the imports of the given packages, or
+// nil if there is none.
+func (g *vulnGraph) reached(pkgs []*load.Package, imports []osvImport) []vulnFunc {
+	var affected []vulnFunc
+	for _, p := range pkgs {
+		if len(imports) == 0 {
+			// The report doesn't list the affected packages:
+			// any function in the module may be affected.
+			affected = append(affected, g.funcs[p.ImportPath]...)
+			affected = append(affected, vulnFunc{p.ImportPath, "init"})
+		}
+		for _, imp := range imports {
+			if imp.Path != p.ImportPath {
+				continue
+			}
+			if len(imp.Symbols) == 0 {
+				// The whole package is affected.
+				return []vulnFunc{{p.ImportPath, "init"}}
+			}
+			for _, sym := range imp.Symbols {
+				affected = append(affected, vulnFunc{p.ImportPath, sym})
+			}
+		}
+	}
+	for _, fn := range affected {
+		if _, ok := g.parent[fn]; !ok {
+			continue
+		}
+		var stack []vulnFunc
+		for f := &fn; f != nil; f = g.parent[*f] {
+			stack = append(stack, *f)
+		}
+		for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+			stack[i], stack[j] = stack[j], stack[i]
+		}
+		return stack
+	}
+	return nil
+}
+
+// A vulnImporter imports the type-checked dependencies of a package.
+type vulnImporter struct {
+	g *vulnGraph
+	p *load.Package
+}
+
+func (imp vulnImporter) Import(path string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if p, ok := imp.p.ImportMap[path]; ok {
+		path = p
+	}
+	if pkg := imp.g.types[path]; pkg != nil {
+		return pkg, nil
+	}
+	return nil, fmt.Errorf("package %s not loaded", path)
+}
@@ -0,0 +1,199 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cmd/go/internal/semver"
+)
+
+// An osvEntry is a vulnerability report in the OSV format
+// (https://ossf.github.io/osv-schema/). Only the fields used by
+// 'go mod vuln' are decoded.
+type osvEntry struct {
+	ID       string        `json:"id"`
+	Summary  string        `json:"summary"`
+	Details  string        `json:"details"`
+	Aliases  []string      `json:"aliases"`
+	Affected []osvAffected `json:"affected"`
+}
+
+// An osvAffected lists the affected versions of a package, which for the
+// Go ecosystem is a module, and the affected packages and symbols in it.
+type osvAffected struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Ranges            []osvRange `json:"ranges"`
+	EcosystemSpecific struct {
+		Imports []osvImport `json:"imports"`
+	} `json:"ecosystem_specific"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced string `json:"introduced,omitempty"`
+	Fixed      string `json:"fixed,omitempty"`
+}
+
+// An osvImport is an affected package. If Symbols is empty, the whole
+// package is affected.
+type osvImport struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols"`
+}
+
+// readVulnDB reads the entries of the vulnerability database db, which is
+// either a file:// URL or a local directory. The directory holds JSON
+// files each containing an OSV entry or an array of entries, as in the
+// offline exports of OSV databases. The entries are returned indexed by
+// the path of each affected Go module.
+func readVulnDB(db string) (map[string][]*osvEntry, error) {
+	dir := db
+	if strings.Contains(db, "://") {
+		u, err := url.Parse(db)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GOVULNDB URL %q: %v", db, err)
+		}
+		if u.Scheme != "file" {
+			return nil, fmt.Errorf("unsupported GOVULNDB URL %q: must be a file:// URL or a local directory", db)
+		}
+		dir = filepath.FromSlash(u.Path)
+	}
+	if !filepath.IsAbs(dir) {
+		return nil, fmt.Errorf("GOVULNDB directory %q must be an absolute path", dir)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		if _, err := ioutil.ReadDir(dir); err != nil {
+			return nil, fmt.Errorf("reading vulnerability database: %v", err)
+		}
+	}
+
+	byModule := make(map[string][]*osvEntry)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading vulnerability database: %v", err)
+		}
+		var entries []*osvEntry
+		if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+			err = json.Unmarshal(data, &entries)
+		} else {
+			e := new(osvEntry)
+			err = json.Unmarshal(data, e)
+			entries = []*osvEntry{e}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", file, err)
+		}
+		for _, e := range entries {
+			seen := make(map[string]bool)
+			for _, a := range e.Affected {
+				if a.Package.Ecosystem != "Go" || seen[a.Package.Name] {
+					continue
+				}
+				seen[a.Package.Name] = true
+				byModule[a.Package.Name] = append(byModule[a.Package.Name], e)
+			}
+		}
+	}
+	for _, entries := range byModule {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	}
+	return byModule, nil
+}
+
+// osvVersion returns the canonical semantic version for a version in an
+// OSV entry, which for the Go ecosystem omits the leading "v".
+func osvVersion(v string) string {
+	if v == "0" {
+		return "v0.0.0"
+	}
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return semver.Canonical(v)
+}
+
+// affects reports whether the entry affects version vers of the module
+// path, and if so, returns the affected packages and the earliest later
+// version in which the vulnerability is fixed, if any.
+func (e *osvEntry) affects(path, vers string) (imports []osvImport, fixed string, ok bool) {
+	for _, a := range e.Affected {
+		if a.Package.Ecosystem != "Go" || a.Package.Name != path {
+			continue
+		}
+		affected, fix := affectsVersion(a.Ranges, vers)
+		if !affected {
+			continue
+		}
+		if fix != "" && (fixed == "" || semver.Compare(fix, fixed) < 0) {
+			fixed = fix
+		}
+		imports = append(imports, a.EcosystemSpecific.Imports...)
+		ok = true
+	}
+	return imports, fixed, ok
+}
+
+// affectsVersion reports whether vers is in one of the SEMVER ranges, and
+// if so, returns the version fixing it, if any. An empty list of ranges
+// affects all versions.
+func affectsVersion(ranges []osvRange, vers string) (affected bool, fixed string) {
+	if len(ranges) == 0 {
+		return true, ""
+	}
+	for _, r := range ranges {
+		if r.Type != "SEMVER" {
+			continue
+		}
+		events := append([]osvEvent(nil), r.Events...)
+		version := func(e osvEvent) string {
+			if e.Introduced != "" {
+				return osvVersion(e.Introduced)
+			}
+			return osvVersion(e.Fixed)
+		}
+		sort.SliceStable(events, func(i, j int) bool {
+			return semver.Compare(version(events[i]), version(events[j])) < 0
+		})
+
+		// The events between them mark the intervals in which the
+		// versions are affected: vers is affected if the last event
+		// at or before it introduced the vulnerability.
+		in := false
+		fixed = ""
+		for _, e := range events {
+			if semver.Compare(version(e), vers) > 0 {
+				if in && e.Fixed != "" && fixed == "" {
+					fixed = version(e)
+				}
+				continue
+			}
+			in = e.Introduced != ""
+		}
+		if in {
+			return true, fixed
+		}
+	}
+	return false, ""
+}
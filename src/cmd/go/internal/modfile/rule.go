@@ -26,6 +26,7 @@ type File struct {
 	Require []*Require
 	Exclude []*Exclude
 	Replace []*Replace
+	Retract []*Retract
 
 	Syntax *FileSyntax
 }
@@ -62,6 +63,21 @@ type Replace struct {
 	Syntax *Line
 }
 
+// A VersionInterval represents a range of versions with upper and lower bounds.
+// Intervals are closed: both bounds are included. When Low is equal to High,
+// the interval may refer to a single version ('v1.2.3') or an interval
+// ('[v1.2.3, v1.2.3]'); both have the same representation.
+type VersionInterval struct {
+	Low, High string
+}
+
+// A Retract is a single retract statement.
+type Retract struct {
+	VersionInterval
+	Rationale string // from the comments before or after the statement
+	Syntax    *Line
+}
+
 func (f *File) AddModuleStmt(path string) error {
 	if f.Syntax == nil {
 		f.Syntax = new(FileSyntax)
@@ -140,7 +156,7 @@ func parseToFile(file string, data []byte, fix VersionFixer, strict bool) (*File
 					fmt.Fprintf(&errs, "%s:%d: unknown block type: %s\n", file, x.Start.Line, strings.Join(x.Token, " "))
 				}
 				continue
-			case "module", "require", "exclude", "replace":
+			case "module", "require", "exclude", "replace", "retract":
 				for _, l := range x.Line {
 					f.add(&errs, l, x.Token[0], l.Token, fix, strict)
 				}
@@ -163,9 +179,11 @@ func (f *File) add(errs *bytes.Buffer, line *Line, verb string, args []string, f
 	// forward compatibility if we can depend on modules that have unknown
 	// statements (presumed relevant only when acting as the main module)
 	// and simply ignore those statements.
+	// Retractions are read from dependencies: a module author declares
+	// them in the go.mod file of the module's latest version.
 	if !strict {
 		switch verb {
-		case "module", "require", "go":
+		case "module", "require", "go", "retract":
 			// want these even for dependency go.mods
 		default:
 			return
@@ -312,7 +330,88 @@ func (f *File) add(errs *bytes.Buffer, line *Line, verb string, args []string, f
 			New:    module.Version{Path: ns, Version: nv},
 			Syntax: line,
 		})
+	case "retract":
+		vi, err := parseVersionInterval(args)
+		if err != nil {
+			if strict {
+				fmt.Fprintf(errs, "%s:%d: %v\n", f.Syntax.Name, line.Start.Line, err)
+			}
+			return
+		}
+		f.Retract = append(f.Retract, &Retract{
+			VersionInterval: vi,
+			Rationale:       parseRetractRationale(line),
+			Syntax:          line,
+		})
+	}
+}
+
+// parseVersionInterval parses the arguments of a retract statement:
+// a single version, or an interval of the form "[v1.2.3, v1.2.5]".
+// The lexer splits an interval into tokens such as "[v1.2.3," and
+// "v1.2.5]", so the arguments are joined before parsing.
+func parseVersionInterval(args []string) (VersionInterval, error) {
+	const usage = "usage: retract v1.2.3 or retract [v1.2.3, v1.2.5]"
+	arg := strings.Join(args, " ")
+	if arg == "" {
+		return VersionInterval{}, errors.New(usage)
+	}
+	if !strings.HasPrefix(arg, "[") {
+		if len(args) != 1 {
+			return VersionInterval{}, errors.New(usage)
+		}
+		v, err := parseRetractVersion(args[0])
+		if err != nil {
+			return VersionInterval{}, err
+		}
+		return VersionInterval{Low: v, High: v}, nil
+	}
+	if !strings.HasSuffix(arg, "]") {
+		return VersionInterval{}, errors.New(usage)
+	}
+	bounds := strings.Split(arg[1:len(arg)-1], ",")
+	if len(bounds) != 2 {
+		return VersionInterval{}, errors.New(usage)
+	}
+	low, err := parseRetractVersion(strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return VersionInterval{}, err
+	}
+	high, err := parseRetractVersion(strings.TrimSpace(bounds[1]))
+	if err != nil {
+		return VersionInterval{}, err
+	}
+	if semver.Compare(low, high) > 0 {
+		return VersionInterval{}, fmt.Errorf("version interval lower bound %s must not be greater than upper bound %s", low, high)
+	}
+	return VersionInterval{Low: low, High: high}, nil
+}
+
+// parseRetractVersion parses a version in a retract statement, which
+// must be a canonical semantic version; retracting a version query
+// such as a branch name would not be meaningful.
+func parseRetractVersion(v string) (string, error) {
+	if v != semver.Canonical(v) {
+		return "", fmt.Errorf("invalid retracted version %q: must be of the form v1.2.3", v)
 	}
+	return v, nil
+}
+
+// parseRetractRationale extracts the rationale for a retraction from
+// the comments on the line before the retract statement, or failing
+// that, at the end of it.
+func parseRetractRationale(line *Line) string {
+	comments := line.Comment()
+	var text []string
+	for _, c := range comments.Before {
+		text = append(text, strings.TrimSpace(strings.TrimPrefix(c.Token, "//")))
+	}
+	if len(text) == 0 {
+		for _, c := range comments.Suffix {
+			text = append(text, strings.TrimSpace(strings.TrimPrefix(c.Token, "//")))
+		}
+	}
+	return strings.TrimSpace(strings.Join(text, "\n"))
 }
 
 // isIndirect reports whether line has a "// indirect" comment,
@@ -474,6 +573,15 @@ func (f *File) Cleanup() {
 	}
 	f.Replace = f.Replace[:w]
 
+	w = 0
+	for _, r := range f.Retract {
+		if r.Low != "" || r.High != "" {
+			f.Retract[w] = r
+			w++
+		}
+	}
+	f.Retract = f.Retract[:w]
+
 	f.Syntax.Cleanup()
 }
 
@@ -658,6 +766,46 @@ func (f *File) DropReplace(oldPath, oldVers string) error {
 	return nil
 }
 
+// AddRetract adds a retract statement for the interval vi, with the
+// given rationale as a comment before it, unless the interval is
+// already retracted.
+func (f *File) AddRetract(vi VersionInterval, rationale string) error {
+	for _, r := range f.Retract {
+		if r.VersionInterval == vi {
+			return nil
+		}
+	}
+	var tokens []string
+	if vi.Low == vi.High {
+		tokens = []string{"retract", AutoQuote(vi.Low)}
+	} else {
+		tokens = []string{"retract", "[" + AutoQuote(vi.Low) + ",", AutoQuote(vi.High) + "]"}
+	}
+	r := &Retract{
+		VersionInterval: vi,
+		Rationale:       rationale,
+		Syntax:          f.Syntax.addLine(nil, tokens...),
+	}
+	if rationale != "" {
+		for _, line := range strings.Split(rationale, "\n") {
+			r.Syntax.Before = append(r.Syntax.Before, Comment{Token: "// " + line})
+		}
+	}
+	f.Retract = append(f.Retract, r)
+	return nil
+}
+
+// DropRetract removes the retract statements for the interval vi.
+func (f *File) DropRetract(vi VersionInterval) error {
+	for _, r := range f.Retract {
+		if r.VersionInterval == vi {
+			f.Syntax.removeLine(r.Syntax)
+			*r = Retract{}
+		}
+	}
+	return nil
+}
+
 func (f *File) SortBlocks() {
 	f.removeDups() // otherwise sorting is unsafe
 
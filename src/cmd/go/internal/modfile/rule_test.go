@@ -7,6 +7,7 @@ package modfile
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -88,3 +89,130 @@ func TestAddRequire(t *testing.T) {
 		})
 	}
 }
+
+var retractTests = []struct {
+	in   string
+	want []Retract
+	err  string
+}{
+	{
+		`
+		module m
+		retract v1.2.3
+		`,
+		[]Retract{{VersionInterval: VersionInterval{"v1.2.3", "v1.2.3"}}},
+		"",
+	},
+	{
+		`
+		module m
+		// Published too early.
+		retract [v1.0.0, v1.0.5]
+		`,
+		[]Retract{{VersionInterval: VersionInterval{"v1.0.0", "v1.0.5"}, Rationale: "Published too early."}},
+		"",
+	},
+	{
+		`
+		module m
+		retract (
+			v1.1.0 // Contains a data race.
+			[v1.2.0,v1.2.1]
+		)
+		`,
+		[]Retract{
+			{VersionInterval: VersionInterval{"v1.1.0", "v1.1.0"}, Rationale: "Contains a data race."},
+			{VersionInterval: VersionInterval{"v1.2.0", "v1.2.1"}},
+		},
+		"",
+	},
+	{
+		`
+		module m
+		retract [v1.2.0, v1.1.0]
+		`,
+		nil,
+		"lower bound",
+	},
+	{
+		`
+		module m
+		retract master
+		`,
+		nil,
+		"invalid retracted version",
+	},
+	{
+		`
+		module m
+		retract v1.0.0 v1.1.0
+		`,
+		nil,
+		"usage",
+	},
+}
+
+func TestParseRetract(t *testing.T) {
+	for i, tt := range retractTests {
+		t.Run(fmt.Sprintf("#%d", i), func(t *testing.T) {
+			f, err := Parse("in", []byte(tt.in), nil)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Parse: err = %v; want error containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(f.Retract) != len(tt.want) {
+				t.Fatalf("got %d retractions; want %d", len(f.Retract), len(tt.want))
+			}
+			for j, r := range f.Retract {
+				if r.VersionInterval != tt.want[j].VersionInterval || r.Rationale != tt.want[j].Rationale {
+					t.Errorf("retraction %d = %v %q; want %v %q", j, r.VersionInterval, r.Rationale, tt.want[j].VersionInterval, tt.want[j].Rationale)
+				}
+			}
+
+			// Retractions are kept in lax mode, for dependencies.
+			f, err = ParseLax("in", []byte(tt.in), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(f.Retract) != len(tt.want) {
+				t.Errorf("ParseLax: got %d retractions; want %d", len(f.Retract), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestAddDropRetract(t *testing.T) {
+	f, err := Parse("in", []byte("module m\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.AddRetract(VersionInterval{"v1.0.0", "v1.0.0"}, "")
+	f.AddRetract(VersionInterval{"v1.1.0", "v1.1.5"}, "Broken build.")
+	f.AddRetract(VersionInterval{"v1.0.0", "v1.0.0"}, "")
+	out, err := f.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "module m\n\nretract (\n\tv1.0.0\n\t// Broken build.\n\t[v1.1.0, v1.1.5]\n)\n"
+	if string(out) != want {
+		t.Errorf("after AddRetract:\n%s\nwant:\n%s", out, want)
+	}
+	g, err := Parse("out", out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Retract) != 2 || g.Retract[1].Rationale != "Broken build." {
+		t.Errorf("reparsed retractions = %v", g.Retract)
+	}
+
+	f.DropRetract(VersionInterval{"v1.0.0", "v1.0.0"})
+	f.Cleanup()
+	if len(f.Retract) != 1 || f.Retract[0].Low != "v1.1.0" {
+		t.Errorf("after DropRetract, retractions = %v", f.Retract)
+	}
+}
@@ -620,6 +620,26 @@ func runGet(cmd *base.Command, args []string) {
 		base.Fatalf("%v", buf.String())
 	}
 
+	// Warn about retracted versions of the modules we resolved.
+	// The retractions of other modules in the build list are reported
+	// by 'go list -m -u all'.
+	var retracted []module.Version
+	for _, m := range modload.BuildList() {
+		if q := byPath[m.Path]; q != nil && q.m == m {
+			retracted = append(retracted, m)
+		}
+	}
+	var retractWork par.Work
+	for _, m := range retracted {
+		retractWork.Add(m)
+	}
+	retractWork.Do(10, func(item interface{}) {
+		m := item.(module.Version)
+		if rationale, ok := modload.Retracted(m); ok {
+			fmt.Fprintf(os.Stderr, "go: warning: %s@%s is retracted: %s\n", m.Path, m.Version, modload.ShortRetractionRationale(rationale))
+		}
+	})
+
 	// Everything succeeded. Update go.mod.
 	modload.AllowWriteGoMod()
 	modload.WriteGoMod()
@@ -876,6 +896,14 @@ func (u *upgrader) Upgrade(m module.Version) (module.Version, error) {
 		return m, nil
 	}
 
+	// Query chooses a retracted version only if no other version matches.
+	// Don't upgrade to one.
+	if info.Version != m.Version {
+		if _, retracted := modload.Retracted(module.Version{Path: m.Path, Version: info.Version}); retracted {
+			return m, nil
+		}
+	}
+
 	// If we're on a later prerelease, keep using it,
 	// even though normally an Upgrade will ignore prereleases.
 	if semver.Compare(info.Version, m.Version) < 0 {
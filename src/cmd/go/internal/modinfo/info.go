@@ -16,6 +16,7 @@ type ModulePublic struct {
 	Replace   *ModulePublic `json:",omitempty"` // replaced by this module
 	Time      *time.Time    `json:",omitempty"` // time version was created
 	Update    *ModulePublic `json:",omitempty"` // available update (with -u)
+	Retracted []string      `json:",omitempty"` // retraction rationale, if retracted (with -u)
 	Main      bool          `json:",omitempty"` // is this the main module?
 	Indirect  bool          `json:",omitempty"` // module is only indirectly needed by main module
 	Dir       string        `json:",omitempty"` // directory holding local copy of files, if any
@@ -35,6 +36,9 @@ func (m *ModulePublic) String() string {
 		if m.Update != nil {
 			s += " [" + m.Update.Version + "]"
 		}
+		if len(m.Retracted) > 0 {
+			s += " (retracted)"
+		}
 	}
 	if m.Replace != nil {
 		s += " => " + m.Replace.Path
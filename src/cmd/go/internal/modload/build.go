@@ -88,6 +88,17 @@ func addUpdate(m *modinfo.ModulePublic) {
 	}
 }
 
+// addRetraction fills in m.Retracted if the module version was retracted
+// by its author.
+func addRetraction(m *modinfo.ModulePublic) {
+	if m.Version == "" {
+		return
+	}
+	if rationale, retracted := Retracted(module.Version{Path: m.Path, Version: m.Version}); retracted {
+		m.Retracted = rationale
+	}
+}
+
 // addVersions fills in m.Versions with the list of known versions.
 func addVersions(m *modinfo.ModulePublic) {
 	m.Versions, _ = versions(m.Path)
@@ -242,7 +242,9 @@ to the comparison target.
 
 Module versions disallowed by exclude statements in the
 main module's go.mod are considered unavailable and cannot
-be returned by queries.
+be returned by queries. Queries other than a specific version
+or revision prefer versions that have not been retracted by the
+module author (see 'go help go.mod').
 
 For example, these commands are all valid:
 
@@ -379,16 +381,45 @@ verb followed by arguments. For example:
 	require new/thing/v2 v2.3.4
 	exclude old/thing v1.2.3
 	replace bad/thing v1.4.5 => good/thing v1.4.5
+	retract v1.5.6
 
 The verbs are
 	module, to define the module path;
 	go, to set the expected language version;
 	require, to require a particular module at a given version or later;
-	exclude, to exclude a particular module version from use; and
-	replace, to replace a module version with a different module version.
+	exclude, to exclude a particular module version from use;
+	replace, to replace a module version with a different module version; and
+	retract, to indicate a previously released version should not be used.
 Exclude and replace apply only in the main module's go.mod and are ignored
 in dependencies.  See https://research.swtch.com/vgo-mvs for details.
 
+Retract marks a version of the module defined by the go.mod file, or a
+closed interval of versions such as [v1.0.0, v1.0.5], as broken or
+published by mistake. A comment before or after the directive gives
+the rationale for the retraction:
+
+	// Published before the API was finalized.
+	retract [v1.0.0, v1.0.5]
+
+Retractions are read from the go.mod file of the latest version of a
+module, so an author retracts a version by publishing a new one.
+Version queries such as "latest" or "v1.2" skip retracted versions
+unless no other version matches, and 'go get -u' does not upgrade a
+module to a retracted version. Only 'go get', which warns when a module
+it resolves is at a retracted version, and 'go list -m -u', which
+reports retracted versions, check for retractions: other commands,
+such as 'go build', do not, as that would require reading the latest
+go.mod file of every module in the build.
+
+When the main module's go.mod declares go 1.14 or later, the module
+graph is pruned: the go.mod file of a go 1.14 module is expected to
+require every module providing packages to it, so the go command
+reads the requirements of the modules it lists but not their own
+go.mod files. 'go mod tidy' maintains that expanded list of
+requirements, marking the dependencies that are not imported
+directly as // indirect. Modules declaring an earlier go version
+contribute all their transitive requirements.
+
 The leading verb can be factored out of adjacent lines to create a block,
 like in Go imports:
 
@@ -287,6 +287,33 @@ func HasModRoot() bool {
 	return modRoot != ""
 }
 
+// FindGoMod returns the path of the go.mod file of the main module
+// that Init would find, or "" if there is none or modules are disabled.
+// Unlike Init, it has no side effects, so it can be used before
+// the command to run is set up.
+func FindGoMod() string {
+	env := cfg.Getenv("GO111MODULE")
+	if env == "off" {
+		return ""
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if env == "auto" {
+		for _, gopath := range filepath.SplitList(cfg.BuildContext.GOPATH) {
+			if gopath != "" && search.InDir(dir, filepath.Join(gopath, "src")) != "" {
+				return ""
+			}
+		}
+	}
+	root := findModuleRoot(dir)
+	if root == "" || search.InDir(root, os.TempDir()) == "." {
+		return ""
+	}
+	return filepath.Join(root, "go.mod")
+}
+
 // printStackInDie causes die to print a stack trace.
 //
 // It is enabled by the testgo tag, and helps to diagnose paths that
@@ -615,6 +642,11 @@ func AllowWriteGoMod() {
 
 // MinReqs returns a Reqs with minimal dependencies of Target,
 // as will be written to go.mod.
+//
+// If the module graph is pruned, the requirements also include every
+// module providing loaded packages, and any module already required
+// by go.mod that is still in the build list; only 'go mod tidy', which
+// removes unused modules from the build list, drops requirements.
 func MinReqs() mvs.Reqs {
 	var direct []string
 	for _, m := range buildList[1:] {
@@ -622,6 +654,22 @@ func MinReqs() mvs.Reqs {
 			direct = append(direct, m.Path)
 		}
 	}
+	if graphPruning() {
+		keep := direct
+		for _, pkg := range loaded.pkgs {
+			if pkg.mod.Path != "" && pkg.mod != Target {
+				keep = append(keep, pkg.mod.Path)
+			}
+		}
+		for _, r := range modFile.Require {
+			keep = append(keep, r.Mod.Path)
+		}
+		min, err := prunedMinReqs(keep)
+		if err != nil {
+			base.Fatalf("go: %v", err)
+		}
+		return &mvsReqs{buildList: append([]module.Version{Target}, min...)}
+	}
 	min, err := mvs.Req(Target, buildList, direct, Reqs())
 	if err != nil {
 		base.Fatalf("go: %v", err)
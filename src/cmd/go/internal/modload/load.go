@@ -622,7 +622,7 @@ var errMissing = errors.New("cannot find package")
 func (ld *loader) load(roots func() []string) {
 	var err error
 	reqs := Reqs()
-	buildList, err = mvs.BuildList(Target, reqs)
+	buildList, err = computeBuildList(reqs)
 	if err != nil {
 		base.Fatalf("go: %v", err)
 	}
@@ -672,7 +672,7 @@ func (ld *loader) load(roots func() []string) {
 
 		// Recompute buildList with all our additions.
 		reqs = Reqs()
-		buildList, err = mvs.BuildList(Target, reqs)
+		buildList, err = computeBuildList(reqs)
 		if err != nil {
 			// If an error was found in a newly added module, report the package
 			// import stack instead of the module requirement stack. Packages
@@ -700,9 +700,19 @@ func (ld *loader) load(roots func() []string) {
 	}
 
 	// Add Go versions, computed during walk.
+	// A pruned module graph doesn't include the go.mod files of all the
+	// modules in the build list; read those of modules providing packages.
 	ld.goVersion = make(map[string]string)
+	providesPkg := make(map[module.Version]bool)
+	for _, pkg := range ld.pkgs {
+		providesPkg[pkg.mod] = true
+	}
 	for _, m := range buildList {
-		v, _ := reqs.(*mvsReqs).versions.Load(m)
+		v, ok := reqs.(*mvsReqs).versions.Load(m)
+		if !ok && providesPkg[m] && graphPruning() {
+			reqs.Required(m)
+			v, _ = reqs.(*mvsReqs).versions.Load(m)
+		}
 		ld.goVersion[m.Path], _ = v.(string)
 	}
 
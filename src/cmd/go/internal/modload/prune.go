@@ -0,0 +1,210 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modload
+
+import (
+	"sort"
+	"sync"
+
+	"cmd/go/internal/cfg"
+	"cmd/go/internal/module"
+	"cmd/go/internal/mvs"
+	"cmd/go/internal/par"
+	"cmd/go/internal/semver"
+)
+
+// Module graph pruning.
+//
+// A go.mod file declaring go 1.14 or later lists every module that
+// provides packages to the module, including indirect dependencies,
+// and 'go mod tidy' maintains that expanded list. The requirements of
+// such a module's dependencies are then implied by its own go.mod file,
+// so when the main module declares go 1.14 or later the module graph
+// includes only the direct requirements of the other go 1.14 modules,
+// and the go command doesn't need to read the go.mod files of the
+// transitive dependencies beyond them. Modules declaring an earlier go
+// version contribute all their transitive requirements, as before.
+
+// pruningGoVersion is the first go version whose go.mod files are
+// pruned from the module graph.
+const pruningGoVersion = "v1.14"
+
+// goVersionPrunes reports whether a go.mod file declaring goVersion
+// lists all the modules providing packages to its module.
+func goVersionPrunes(goVersion string) bool {
+	return goVersion != "" && semver.Compare("v"+goVersion, pruningGoVersion) >= 0
+}
+
+// graphPruning reports whether the module graph of the main module is
+// pruned.
+func graphPruning() bool {
+	return cfg.BuildMod != "vendor" && modFile != nil && modFile.Go != nil && goVersionPrunes(modFile.Go.Version)
+}
+
+// Pruned reports whether the module graph stops at the requirements of
+// m: that is, whether the graph of the main module is pruned and the
+// go.mod file of m, as loaded by reqs.Required(m), declares go 1.14 or
+// later.
+func Pruned(reqs mvs.Reqs, m module.Version) bool {
+	if !graphPruning() || m == Target {
+		return false
+	}
+	r, ok := reqs.(*mvsReqs)
+	if !ok {
+		return false
+	}
+	v, _ := r.versions.Load(m)
+	goVersion, _ := v.(string)
+	return goVersionPrunes(goVersion)
+}
+
+// computeBuildList returns the build list of the main module for the
+// module graph reqs, pruned if the main module's go.mod enables it.
+func computeBuildList(reqs mvs.Reqs) ([]module.Version, error) {
+	if !graphPruning() {
+		return mvs.BuildList(Target, reqs)
+	}
+	// The requirements of Target in reqs are the current build list,
+	// whose modules can't all be roots of a pruned graph: that would
+	// read the go.mod files of every module listed by a pruned one.
+	// Start instead from the requirements in go.mod, plus the modules
+	// needed to reach the versions in the build list.
+	var keep []string
+	for _, r := range modFile.Require {
+		keep = append(keep, r.Mod.Path)
+	}
+	_, list, err := prunedRoots(keep, reqs)
+	return list, err
+}
+
+// prunedBuildList returns the build list of the main module requiring
+// roots, reading the requirements of roots and of the modules they
+// reach through go.mod files declaring a go version before 1.14, but
+// not the requirements of the modules listed by go 1.14 go.mod files.
+func prunedBuildList(roots []module.Version, reqs mvs.Reqs) ([]module.Version, error) {
+	type node struct {
+		m      module.Version
+		expand bool // whether to load the requirements of m
+	}
+	var (
+		mu       sync.Mutex
+		required = make(map[module.Version][]module.Version)
+		min      = make(map[string]string) // module path -> minimum required version
+		errMod   module.Version
+		firstErr error
+	)
+
+	var work par.Work
+	for _, m := range roots {
+		work.Add(node{m, true})
+	}
+	work.Do(10, func(item interface{}) {
+		n := item.(node)
+		mu.Lock()
+		if v, ok := min[n.m.Path]; !ok || reqs.Max(v, n.m.Version) != v {
+			min[n.m.Path] = n.m.Version
+		}
+		mu.Unlock()
+		if !n.expand {
+			return
+		}
+
+		list, err := reqs.Required(n.m)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			// Report the error for the first module path,
+			// so that the error doesn't depend on scheduling.
+			if firstErr == nil || n.m.Path < errMod.Path {
+				errMod, firstErr = n.m, err
+			}
+			return
+		}
+		required[n.m] = list
+		expand := !Pruned(reqs, n.m)
+		for _, r := range list {
+			work.Add(node{r, expand})
+		}
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	// Construct the list from the selected versions of the modules
+	// reachable from the roots, as mvs.BuildList does.
+	list := []module.Version{Target}
+	listed := map[string]bool{Target.Path: true}
+	add := func(ms []module.Version) {
+		for _, r := range ms {
+			if !listed[r.Path] {
+				list = append(list, module.Version{Path: r.Path, Version: min[r.Path]})
+				listed[r.Path] = true
+			}
+		}
+	}
+	add(roots)
+	for i := 1; i < len(list); i++ {
+		add(required[list[i]])
+	}
+
+	tail := list[1:]
+	sort.Slice(tail, func(i, j int) bool {
+		return tail[i].Path < tail[j].Path
+	})
+	return list, nil
+}
+
+// prunedMinReqs returns the requirements of the main module to write to
+// a go.mod file enabling graph pruning: the modules in keep, plus any
+// others needed for the pruned module graph to reproduce the build list.
+func prunedMinReqs(keep []string) ([]module.Version, error) {
+	roots, _, err := prunedRoots(keep, Reqs())
+	return roots, err
+}
+
+// prunedRoots returns the roots of a pruned module graph reproducing the
+// current build list, and the build list of that graph. The roots are
+// the modules of the build list whose paths are in keep, and any others
+// whose version the graph would not otherwise reach.
+func prunedRoots(keep []string, reqs mvs.Reqs) (roots, list []module.Version, err error) {
+	selected := make(map[string]string)
+	for _, m := range buildList[1:] {
+		selected[m.Path] = m.Version
+	}
+	inRoots := make(map[string]bool)
+	for _, path := range keep {
+		if v, ok := selected[path]; ok && !inRoots[path] {
+			roots = append(roots, module.Version{Path: path, Version: v})
+			inRoots[path] = true
+		}
+	}
+
+	for {
+		list, err = prunedBuildList(roots, reqs)
+		if err != nil {
+			return nil, nil, err
+		}
+		have := make(map[string]string)
+		for _, m := range list[1:] {
+			have[m.Path] = m.Version
+		}
+		added := false
+		for _, m := range buildList[1:] {
+			if have[m.Path] != m.Version && !inRoots[m.Path] {
+				roots = append(roots, m)
+				inRoots[m.Path] = true
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].Path < roots[j].Path
+	})
+	return roots, list, nil
+}
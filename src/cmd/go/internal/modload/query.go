@@ -33,6 +33,11 @@ import (
 //	   with non-prereleases preferred over prereleases.
 //	- a repository commit identifier, denoting that commit.
 //
+// Queries other than a specific version or commit prefer versions that
+// the module author has not retracted, as listed by the retract
+// directives in the go.mod file of the module's latest version.
+// Retracted versions are chosen only when no other version matches.
+//
 // If the allowed function is non-nil, Query excludes any versions for which allowed returns false.
 //
 // If path is the path of the main module and the query is "latest",
@@ -149,28 +154,43 @@ func Query(path, query string, allowed func(module.Version) bool) (*modfetch.Rev
 		return nil, err
 	}
 
-	if preferOlder {
-		for _, v := range versions {
-			if semver.Prerelease(v) == "" && ok(module.Version{Path: path, Version: v}) {
-				return repo.Stat(v)
+	// Prefer versions that have not been retracted by the module author,
+	// falling back to retracted versions only if no other version matches.
+	for _, allowRetracted := range []bool{false, true} {
+		match := func(v string) bool {
+			m := module.Version{Path: path, Version: v}
+			if !ok(m) {
+				return false
 			}
-		}
-		for _, v := range versions {
-			if semver.Prerelease(v) != "" && ok(module.Version{Path: path, Version: v}) {
-				return repo.Stat(v)
+			if allowRetracted {
+				return true
 			}
+			_, retracted := Retracted(m)
+			return !retracted
 		}
-	} else {
-		for i := len(versions) - 1; i >= 0; i-- {
-			v := versions[i]
-			if semver.Prerelease(v) == "" && ok(module.Version{Path: path, Version: v}) {
-				return repo.Stat(v)
+		if preferOlder {
+			for _, v := range versions {
+				if semver.Prerelease(v) == "" && match(v) {
+					return repo.Stat(v)
+				}
 			}
-		}
-		for i := len(versions) - 1; i >= 0; i-- {
-			v := versions[i]
-			if semver.Prerelease(v) != "" && ok(module.Version{Path: path, Version: v}) {
-				return repo.Stat(v)
+			for _, v := range versions {
+				if semver.Prerelease(v) != "" && match(v) {
+					return repo.Stat(v)
+				}
+			}
+		} else {
+			for i := len(versions) - 1; i >= 0; i-- {
+				v := versions[i]
+				if semver.Prerelease(v) == "" && match(v) {
+					return repo.Stat(v)
+				}
+			}
+			for i := len(versions) - 1; i >= 0; i-- {
+				v := versions[i]
+				if semver.Prerelease(v) != "" && match(v) {
+					return repo.Stat(v)
+				}
 			}
 		}
 	}
@@ -0,0 +1,117 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modload
+
+import (
+	"fmt"
+	"strings"
+
+	"cmd/go/internal/modfetch"
+	"cmd/go/internal/modfile"
+	"cmd/go/internal/module"
+	"cmd/go/internal/par"
+	"cmd/go/internal/semver"
+)
+
+// A module author retracts versions of the module with retract
+// directives in the go.mod file of the module's latest version.
+// The retractions of a module are therefore only known after
+// consulting that version, which queries do on demand.
+
+var retractionCache par.Cache // module path -> *retractionEntry
+
+type retractionEntry struct {
+	retract []*modfile.Retract
+	err     error
+}
+
+// queryRetractions returns the retractions declared in the go.mod file
+// of the latest version of the module path: the latest release version
+// if there is one, otherwise the latest prerelease version, otherwise
+// the latest commit in the repository.
+func queryRetractions(path string) ([]*modfile.Retract, error) {
+	e := retractionCache.Do(path, func() interface{} {
+		retract, err := loadRetractions(path)
+		return &retractionEntry{retract, err}
+	}).(*retractionEntry)
+	return e.retract, e.err
+}
+
+func loadRetractions(path string) ([]*modfile.Retract, error) {
+	repo, err := modfetch.Lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := repo.Versions("")
+	if err != nil {
+		return nil, err
+	}
+	var latest string
+	for i := len(versions) - 1; i >= 0; i-- {
+		if semver.Prerelease(versions[i]) == "" {
+			latest = versions[i]
+			break
+		}
+	}
+	if latest == "" && len(versions) > 0 {
+		latest = versions[len(versions)-1]
+	}
+	if latest == "" {
+		info, err := repo.Latest()
+		if err != nil {
+			return nil, err
+		}
+		latest = info.Version
+	}
+	data, err := modfetch.GoMod(path, latest)
+	if err != nil {
+		return nil, err
+	}
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s@%s go.mod: %v", path, latest, err)
+	}
+	return f.Retract, nil
+}
+
+// Retracted reports whether the module version m has been retracted by
+// its author, and if so, returns the rationales given for the
+// retraction. A version whose retractions cannot be loaded, such as a
+// replaced module or one whose repository is unreachable, is reported
+// as not retracted.
+func Retracted(m module.Version) (rationale []string, retracted bool) {
+	if m == Target || !semver.IsValid(m.Version) || Replacement(m).Path != "" {
+		return nil, false
+	}
+	retract, err := queryRetractions(m.Path)
+	if err != nil {
+		return nil, false
+	}
+	for _, r := range retract {
+		if semver.Compare(r.Low, m.Version) <= 0 && semver.Compare(m.Version, r.High) <= 0 {
+			retracted = true
+			if r.Rationale != "" {
+				rationale = append(rationale, r.Rationale)
+			}
+		}
+	}
+	if retracted && len(rationale) == 0 {
+		rationale = []string{"retracted by module author"}
+	}
+	return rationale, retracted
+}
+
+// ShortRetractionRationale returns the first line of the rationale for
+// a retraction, for use in a one-line warning.
+func ShortRetractionRationale(rationale []string) string {
+	if len(rationale) == 0 {
+		return "retracted by module author"
+	}
+	s := rationale[0]
+	if i := strings.Index(s, "\n"); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
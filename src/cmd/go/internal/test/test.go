@@ -114,8 +114,9 @@ elapsed time in the summary line.
 The rule for a match in the cache is that the run involves the same
 test binary and the flags on the command line come entirely from a
 restricted set of 'cacheable' test flags, defined as -cpu, -list,
--parallel, -run, -short, and -v. If a run of go test has any test
-or non-test flags outside this set, the result is not cached. To
+-parallel, -run, -short, -shuffle with a seed or off, and -v.
+If a run of go test has any test or non-test flags outside this set,
+or uses -shuffle=on, the result is not cached. To
 disable test caching, use any test flag or argument other than the
 cacheable flags. The idiomatic way to disable test caching explicitly
 is to use -count=1. Tests that open files within the package's source
@@ -266,6 +267,13 @@ control the execution of any test:
 	    the Go tree can run a sanity check but not spend time running
 	    exhaustive tests.
 
+	-shuffle off,on,N
+	    Randomize the execution order of tests and benchmarks.
+	    It is off by default. If -shuffle is set to on, then it will seed
+	    the randomizer using the system clock. If -shuffle is set to an
+	    integer N, then N will be used as the seed value. In both cases,
+	    the seed will be reported for reproducibility.
+
 	-timeout d
 	    If a test binary runs longer than duration d, panic.
 	    If d is 0, the timeout is disabled.
@@ -1268,6 +1276,19 @@ func (c *runCache) tryCacheWithID(b *work.Builder, a *work.Action, id string) bo
 			// Special case: this is cacheable but ignored during the hash.
 			// Do not add to cacheArgs.
 
+		case "-test.shuffle":
+			// Special case: a shuffle with a given seed runs the tests
+			// in the same order every time, and is cacheable; a shuffle
+			// seeded by the clock is not.
+			if arg[i+1:] == "on" {
+				if cache.DebugTest {
+					fmt.Fprintf(os.Stderr, "testcache: caching disabled for test argument: %s\n", arg)
+				}
+				c.disableCache = true
+				return false
+			}
+			cacheArgs = append(cacheArgs, arg)
+
 		default:
 			// nothing else is cacheable
 			if cache.DebugTest {
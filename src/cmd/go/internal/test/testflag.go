@@ -56,6 +56,7 @@ var testFlagDefn = []*cmdflag.Defn{
 	{Name: "parallel", PassToTest: true},
 	{Name: "run", PassToTest: true},
 	{Name: "short", BoolVar: new(bool), PassToTest: true},
+	{Name: "shuffle", PassToTest: true},
 	{Name: "timeout", PassToTest: true},
 	{Name: "trace", PassToTest: true},
 	{Name: "v", BoolVar: &testV, PassToTest: true},
@@ -139,7 +140,7 @@ func testFlags(usage func(), args []string) (packageNames, passToTest []string)
 			case "c", "i", "v", "cover", "json":
 				cmdflag.SetBool(cmd, f.BoolVar, value)
 				if f.Name == "json" && testJSON {
-					passToTest = append(passToTest, "-test.v=true")
+					passToTest = append(passToTest, "-test.v=test2json")
 				}
 			case "o":
 				testO = value
@@ -190,6 +191,10 @@ func testFlags(usage func(), args []string) (packageNames, passToTest []string)
 			i++
 		}
 		if f.PassToTest {
+			if f.Name == "v" && testJSON {
+				// Keep reporting test events for -json.
+				value = "test2json"
+			}
 			passToTest = append(passToTest, "-test."+f.Name+"="+value)
 		}
 	}
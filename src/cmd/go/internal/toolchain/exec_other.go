@@ -0,0 +1,33 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package toolchain
+
+import (
+	"os"
+	"os/exec"
+
+	"cmd/go/internal/base"
+)
+
+// execGo runs the go command exe of another toolchain with the given
+// arguments and environment, and exits with its exit status.
+// Processes cannot be replaced on these systems.
+func execGo(exe string, args, env []string) {
+	cmd := exec.Command(exe, args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = env
+	base.StartSigHandlers()
+	if err := cmd.Run(); err != nil {
+		if e, ok := err.(*exec.ExitError); ok {
+			os.Exit(e.ExitCode())
+		}
+		base.Fatalf("go: running %s: %v", exe, err)
+	}
+	os.Exit(0)
+}
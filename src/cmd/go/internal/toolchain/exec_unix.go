@@ -0,0 +1,20 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package toolchain
+
+import (
+	"syscall"
+
+	"cmd/go/internal/base"
+)
+
+// execGo replaces the go command with the go command exe
+// of another toolchain, run with the given arguments and environment.
+func execGo(exe string, args, env []string) {
+	err := syscall.Exec(exe, args, env)
+	base.Fatalf("go: running %s: %v", exe, err)
+}
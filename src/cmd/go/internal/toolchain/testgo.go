@@ -0,0 +1,20 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains extra hooks for testing the go command.
+
+// +build testgo
+
+package toolchain
+
+import (
+	"os"
+	"strings"
+)
+
+func init() {
+	if v := os.Getenv("TESTGO_TOOLCHAIN_VERSION"); v != "" {
+		localVersion = func() string { return strings.TrimPrefix(v, "go") }
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package toolchain implements the selection of the Go toolchain
+// required by the main module, as configured by GOTOOLCHAIN.
+package toolchain
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/cfg"
+	"cmd/go/internal/modfetch"
+	"cmd/go/internal/modfile"
+	"cmd/go/internal/modload"
+	"cmd/go/internal/module"
+	"cmd/go/internal/semver"
+	"cmd/go/internal/str"
+)
+
+var HelpToolchain = &base.Command{
+	UsageLine: "toolchain",
+	Short:     "Go toolchain selection",
+	Long: `
+The go line in the main module's go.mod file states the minimum version
+of Go needed to build the module. When the go command finds that the
+go line requires a newer version of Go than its own, it can download
+that version of the Go toolchain and run it instead, so that the module
+is always built by a toolchain that supports it.
+
+The GOTOOLCHAIN environment variable controls this selection:
+
+	GOTOOLCHAIN=auto
+		Use the local toolchain unless the go line requires a newer one,
+		in which case download and run the required version.
+		This is the default.
+	GOTOOLCHAIN=local
+		Always use the local toolchain, and fail if the go line
+		requires a newer one.
+	GOTOOLCHAIN=go1.N or go1.N.P
+		Always use the named Go release, downloading it if it is
+		not the local toolchain.
+
+Toolchains are downloaded as versions of the module golang.org/toolchain
+named for the release and the host system, such as
+golang.org/toolchain@v0.0.1-go1.14.linux-amd64, through the module proxy
+set by GOPROXY. They are stored in the module cache like other modules.
+A downloaded toolchain is always verified against the checksum database
+set by GOSUMDB, so the go command refuses to download one when GOSUMDB
+is off or when GONOSUMDB matches golang.org/toolchain.
+
+The go command runs the selected toolchain with GOTOOLCHAIN=local,
+so that it does not select a toolchain again.
+
+The 'go env' and 'go mod edit' commands always use the local toolchain,
+so that GOTOOLCHAIN and the go line can be changed with them.
+	`,
+}
+
+// toolchainModule is the module path of downloadable Go toolchains.
+const toolchainModule = "golang.org/toolchain"
+
+// localVersion returns the Go language version of the local toolchain,
+// such as "1.13": the latest version it has a release tag for.
+var localVersion = func() string {
+	tags := cfg.BuildContext.ReleaseTags
+	return strings.TrimPrefix(tags[len(tags)-1], "go")
+}
+
+// Select runs the Go toolchain selected by GOTOOLCHAIN for the go
+// command with the given arguments, if it is not the local toolchain.
+// If it runs another toolchain, Select does not return.
+func Select(args []string) {
+	if len(args) > 0 && args[0] == "env" || len(args) > 1 && args[0] == "mod" && args[1] == "edit" {
+		return
+	}
+
+	gotoolchain := cfg.Getenv("GOTOOLCHAIN")
+	switch gotoolchain {
+	case "local":
+		if v := goLine(); v != "" && newer(v, localVersion()) {
+			base.Fatalf("go: go.mod requires go %s (running go %s; GOTOOLCHAIN=local)", v, localVersion())
+		}
+		return
+	case "", "auto":
+		v := goLine()
+		if v == "" || !newer(v, localVersion()) {
+			return
+		}
+		gotoolchain = "go" + v
+	default:
+		if !strings.HasPrefix(gotoolchain, "go") || !semver.IsValid("v"+gotoolchain[2:]) {
+			base.Fatalf("go: invalid GOTOOLCHAIN %q; see 'go help toolchain'", gotoolchain)
+		}
+		if gotoolchain == runtime.Version() {
+			return
+		}
+	}
+
+	dir, err := download(gotoolchain)
+	if err != nil {
+		base.Fatalf("go: downloading %s: %v", gotoolchain, err)
+	}
+	exe := filepath.Join(dir, "bin", "go")
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+
+	// Run the toolchain in the original environment, not the one
+	// computed for the local toolchain, which names its GOTOOLDIR.
+	var env []string
+	for _, kv := range cfg.OrigEnv {
+		if !strings.HasPrefix(kv, "GOTOOLCHAIN=") && !strings.HasPrefix(kv, "GOROOT=") {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, "GOTOOLCHAIN=local", "GOROOT="+dir)
+	execGo(exe, append([]string{"go"}, args...), env)
+}
+
+// goLine returns the version on the go line of the main module's
+// go.mod file, or "" if there is none. Errors reading or parsing
+// the file are left for the command itself to report.
+func goLine() string {
+	file := modload.FindGoMod()
+	if file == "" {
+		return ""
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	f, err := modfile.ParseLax(file, data, nil)
+	if err != nil || f.Go == nil {
+		return ""
+	}
+	return f.Go.Version
+}
+
+// newer reports whether the Go version v is newer than w.
+func newer(v, w string) bool {
+	return semver.Compare("v"+v, "v"+w) > 0
+}
+
+// download downloads the named Go toolchain for the host system
+// and returns the directory it was extracted to.
+func download(gotoolchain string) (string, error) {
+	mod := module.Version{
+		Path:    toolchainModule,
+		Version: "v0.0.1-" + gotoolchain + "." + runtime.GOOS + "-" + runtime.GOARCH,
+	}
+	if cfg.GOSUMDB == "off" {
+		return "", fmt.Errorf("cannot verify toolchain: checksum database disabled by GOSUMDB=off")
+	}
+	if str.GlobsMatchPath(cfg.GONOSUMDB, mod.Path) {
+		return "", fmt.Errorf("cannot verify toolchain: checksum database disabled for %s by GONOSUMDB", mod.Path)
+	}
+	dir, err := modfetch.Download(mod)
+	if err != nil {
+		return "", err
+	}
+
+	// Module zip files do not record file modes,
+	// so make the toolchain's programs executable.
+	for _, sub := range []string{"bin", "pkg/tool"} {
+		err := filepath.Walk(filepath.Join(dir, sub), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.Mode().IsRegular() && info.Mode()&0111 == 0 {
+				return os.Chmod(path, info.Mode()|0111)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"cmd/go/internal/base"
@@ -29,6 +30,19 @@ import (
 
 type gcToolchain struct{}
 
+// langVersion reports whether the compiler accepts -lang=go+v.
+// Besides the released versions, the compiler accepts the version of
+// the next release, for the language changes planned for it.
+func langVersion(v string) bool {
+	if allowedVersion(v) {
+		return true
+	}
+	tags := cfg.BuildContext.ReleaseTags
+	last := strings.TrimPrefix(tags[len(tags)-1], "go1.")
+	n, err := strconv.Atoi(last)
+	return err == nil && v == "1."+strconv.Itoa(n+1)
+}
+
 func (gcToolchain) compiler() string {
 	return base.Tool("compile")
 }
@@ -54,7 +68,7 @@ func (gcToolchain) gc(b *Builder, a *Action, archive string, importcfg []byte, s
 		pkgpath = "main"
 	}
 	gcargs := []string{"-p", pkgpath}
-	if p.Module != nil && p.Module.GoVersion != "" && allowedVersion(p.Module.GoVersion) {
+	if p.Module != nil && p.Module.GoVersion != "" && langVersion(p.Module.GoVersion) {
 		gcargs = append(gcargs, "-lang=go"+p.Module.GoVersion)
 	}
 	if p.Standard {
@@ -34,6 +34,7 @@ import (
 	"cmd/go/internal/run"
 	"cmd/go/internal/test"
 	"cmd/go/internal/tool"
+	"cmd/go/internal/toolchain"
 	"cmd/go/internal/version"
 	"cmd/go/internal/vet"
 	"cmd/go/internal/work"
@@ -75,6 +76,7 @@ func init() {
 		modfetch.HelpSum,
 		test.HelpTestflag,
 		test.HelpTestfunc,
+		toolchain.HelpToolchain,
 	}
 }
 
@@ -152,6 +154,9 @@ func main() {
 		}
 	}
 
+	// Run the toolchain required by the main module, if it is not this one.
+	toolchain.Select(args)
+
 BigCmdLoop:
 	for bigCmd := base.Go; ; {
 		for _, cmd := range bigCmd.Commands {
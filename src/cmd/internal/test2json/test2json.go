@@ -31,10 +31,12 @@ const (
 type event struct {
 	Time    *time.Time `json:",omitempty"`
 	Action  string
-	Package string     `json:",omitempty"`
-	Test    string     `json:",omitempty"`
-	Elapsed *float64   `json:",omitempty"`
-	Output  *textBytes `json:",omitempty"`
+	Package string             `json:",omitempty"`
+	Test    string             `json:",omitempty"`
+	Elapsed *float64           `json:",omitempty"`
+	Output  *textBytes         `json:",omitempty"`
+	Metrics map[string]float64 `json:",omitempty"`
+	Reason  string             `json:",omitempty"`
 }
 
 // textBytes is a hack to get JSON to emit a []byte as a string
@@ -45,6 +47,11 @@ type textBytes []byte
 
 func (b textBytes) MarshalText() ([]byte, error) { return b, nil }
 
+func (b *textBytes) UnmarshalText(text []byte) error {
+	*b = append((*b)[:0], text...)
+	return nil
+}
+
 // A converter holds the state of a test-to-JSON conversion.
 // It implements io.WriteCloser; the caller writes test output in,
 // and the converter writes JSON output to w.
@@ -56,6 +63,7 @@ type converter struct {
 	testName string     // name of current test, for output attribution
 	report   []*event   // pending test result reports (nested for subtests)
 	result   string     // overall test result if seen
+	framed   bool       // whether the test binary reports framed events
 	input    lineBuffer // input buffer
 	output   lineBuffer // output buffer
 }
@@ -70,6 +78,8 @@ type converter struct {
 //
 // If anyone reports a test directive line > 4k not working, it will
 // be defensible to suggest they restructure their test or test names.
+// The framed events reported by the testing package are read whole
+// even if longer, up to maxFrame bytes.
 //
 // The output buffer must be >= utf8.UTFMax, so that it can
 // accumulate any single UTF8 sequence. Lines that fit entirely
@@ -84,6 +94,9 @@ var (
 	outBuffer = 1024
 )
 
+// maxFrame is the maximum size of a framed event.
+const maxFrame = 64 << 10
+
 // NewConverter returns a "test to json" converter.
 // Writes on the returned writer are written as JSON to w,
 // with minimal delay.
@@ -108,9 +121,10 @@ func NewConverter(w io.Writer, pkg string, mode Mode) io.WriteCloser {
 		mode:  mode,
 		start: time.Now(),
 		input: lineBuffer{
-			b:    make([]byte, 0, inBuffer),
-			line: c.handleInputLine,
-			part: c.output.write,
+			b:      make([]byte, 0, inBuffer),
+			frames: true,
+			line:   c.handleInputLine,
+			part:   c.output.write,
 		},
 		output: lineBuffer{
 			b:    make([]byte, 0, outBuffer),
@@ -150,10 +164,25 @@ var (
 	skipLineSuffix = []byte("\t[no test files]\n")
 )
 
+// frameMarker is the byte starting each event reported directly by the
+// testing package, in a line of the form "^V{...}\n" holding the event
+// encoded in JSON, when the test binary runs with -test.v=test2json.
+const frameMarker = 0x16 // ^V
+
 // handleInputLine handles a single whole test output line.
 // It must write the line to c.output but may choose to do so
 // before or after emitting other events.
 func (c *converter) handleInputLine(line []byte) {
+	if i := bytes.IndexByte(line, frameMarker); i >= 0 {
+		if e := c.parseFrame(line[i:]); e != nil {
+			// The event may follow output not ending in a newline.
+			c.output.write(line[:i])
+			c.output.flush()
+			c.handleFrame(e)
+			return
+		}
+	}
+
 	// Final PASS or FAIL.
 	if bytes.Equal(line, bigPass) || bytes.Equal(line, bigFail) {
 		c.flushReport(0)
@@ -166,6 +195,13 @@ func (c *converter) handleInputLine(line []byte) {
 		return
 	}
 
+	// A test binary reporting framed events prints no other markers:
+	// lines that look like them are the test's own output.
+	if c.framed {
+		c.output.write(line)
+		return
+	}
+
 	// Special case for entirely skipped test binary: "?   \tpkgname\t[no test files]\n" is only line.
 	// Report it as plain output but remember to say skip in the final summary.
 	if bytes.HasPrefix(line, skipLinePrefix) && bytes.HasSuffix(line, skipLineSuffix) && len(c.report) == 0 {
@@ -268,6 +304,41 @@ func (c *converter) handleInputLine(line []byte) {
 	return
 }
 
+// parseFrame parses the framed event in line,
+// returning nil if line is not a valid framed event.
+func (c *converter) parseFrame(line []byte) *event {
+	if len(line) < 2 || line[0] != frameMarker || line[len(line)-1] != '\n' {
+		return nil
+	}
+	e := new(event)
+	if err := json.Unmarshal(line[1:], e); err != nil || e.Action == "" {
+		return nil
+	}
+	return e
+}
+
+// handleFrame writes the event e reported by the testing package.
+// The event already names its test, so that its output is attributed
+// exactly; the converter adds the package and time and keeps track of
+// the running test only for attributing output that is not framed.
+func (c *converter) handleFrame(e *event) {
+	c.framed = true
+	if len(c.report) > 0 {
+		c.flushReport(0)
+	}
+	e.Time = nil
+	if c.mode&Timestamp == 0 {
+		e.Elapsed = nil
+	}
+	switch e.Action {
+	case "run", "cont":
+		c.testName = e.Test
+	case "pause", "pass", "fail", "skip", "bench":
+		c.testName = ""
+	}
+	c.writeEvent(e)
+}
+
 // flushReport flushes all pending PASS/FAIL reports at levels >= depth.
 func (c *converter) flushReport(depth int) {
 	c.testName = ""
@@ -336,10 +407,11 @@ func (c *converter) writeEvent(e *event) {
 // calling part(x) for sections of the line. The line will be split at UTF8 boundaries,
 // and the final call to part for a long line includes the final newline.
 type lineBuffer struct {
-	b    []byte       // buffer
-	mid  bool         // whether we're in the middle of a long line
-	line func([]byte) // line callback
-	part func([]byte) // partial line callback
+	b      []byte       // buffer
+	mid    bool         // whether we're in the middle of a long line
+	frames bool         // whether to grow b to hold framed events whole
+	line   func([]byte) // line callback
+	part   func([]byte) // partial line callback
 }
 
 // write writes b to the buffer.
@@ -379,6 +451,14 @@ func (l *lineBuffer) write(b []byte) {
 		}
 
 		// Whatever's left in l.b is a line fragment.
+		if i == 0 && len(l.b) == cap(l.b) && l.frames && !l.mid && l.b[0] == frameMarker && cap(l.b) < maxFrame {
+			// The beginning of a long framed event.
+			// Grow the buffer to read the event whole.
+			nb := make([]byte, len(l.b), 2*cap(l.b))
+			copy(nb, l.b)
+			l.b = nb
+			continue
+		}
 		if i == 0 && len(l.b) == cap(l.b) {
 			// The whole buffer is a fragment.
 			// Emit it as the beginning (or continuation) of a partial line.
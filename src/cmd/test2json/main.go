@@ -6,7 +6,7 @@
 //
 // Usage:
 //
-//	go tool test2json [-p pkg] [-t] [./pkg.test -test.v]
+//	go tool test2json [-p pkg] [-t] [./pkg.test -test.v=test2json]
 //
 // Test2json runs the given test command and converts its output to JSON;
 // with no command specified, test2json expects test output on standard input.
@@ -14,6 +14,15 @@
 // There is no unnecessary input or output buffering, so that
 // the JSON stream can be read for “live updates” of test status.
 //
+// A test binary run with -test.v=test2json reports its progress directly
+// as a sequence of test events, each written on a line of its own as a
+// JSON object preceded by a ^V (0x16) byte. Test2json passes these
+// events on, adding the package and time stamp. Otherwise, as with
+// -test.v, test2json reconstructs the events by recognizing the lines
+// the test binary prints as tests start and finish. Only the events
+// reported directly attribute all output to the right test when parallel
+// tests run, and carry the metrics of benchmarks and the reasons for skips.
+//
 // The -p flag sets the package reported in each test event.
 //
 // The -t flag requests that time stamps be added to each test event.
@@ -34,6 +43,8 @@
 //		Test    string
 //		Elapsed float64 // seconds
 //		Output  string
+//		Metrics map[string]float64
+//		Reason  string
 //	}
 //
 // The Time field holds the time the event happened.
@@ -59,7 +70,7 @@
 // function that caused the event. Events for the overall package test
 // do not set Test.
 //
-// The Elapsed field is set for "pass" and "fail" events. It gives the time
+// The Elapsed field is set for "pass", "fail" and "skip" events. It gives the time
 // elapsed for the specific test or the overall package test that passed or failed.
 //
 // The Output field is set for Action == "output" and is a portion of the test's output
@@ -69,9 +80,15 @@
 // the concatenation of the Output fields of all output events is the exact
 // output of the test execution.
 //
+// The Reason field is set for "skip" events reported directly by the test
+// binary. It gives the message passed to Skip or Skipf by the test.
+//
 // When a benchmark runs, it typically produces a single line of output
 // giving timing results. That line is reported in an event with Action == "output"
-// and no Test field. If a benchmark logs output or reports a failure
+// and no Test field; when reported directly by the test binary, the event has
+// the Test field set to the benchmark name and the Metrics field set to the
+// results in the line, keyed by unit, such as "ns/op" or the units of metrics
+// reported by b.ReportMetric. If a benchmark logs output or reports a failure
 // (for example, by using b.Log or b.Error), that extra output is reported
 // as a sequence of events with Test set to the benchmark name, terminated
 // by a final event with Action == "bench" or "fail".
@@ -95,7 +112,7 @@ var (
 )
 
 func usage() {
-	fmt.Fprintf(os.Stderr, "usage: go tool test2json [-p pkg] [-t] [./pkg.test -test.v]\n")
+	fmt.Fprintf(os.Stderr, "usage: go tool test2json [-p pkg] [-t] [./pkg.test -test.v=test2json]\n")
 	os.Exit(2)
 }
 
@@ -0,0 +1,244 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdh
+
+import (
+	"crypto/elliptic"
+	"crypto/internal/nistec"
+	"errors"
+	"io"
+)
+
+// nistCurve implements ECDH over one of the NIST prime order curves.
+//
+// All the operations on secret values run in constant time. P-256 uses
+// crypto/elliptic, through its fixed-length byte scalar methods only, as its
+// implementations there (assembly on amd64 and arm64, 32-bit limbs
+// elsewhere) are constant time on all platforms. P-384 and P-521 use
+// crypto/internal/nistec instead, as crypto/elliptic implements them with
+// math/big, which is not constant time.
+type nistCurve struct {
+	name  string
+	curve elliptic.Curve
+	point nistPoint
+}
+
+// nistPoint is the group arithmetic of a nistCurve. Points are passed in
+// their uncompressed SEC 1 encoding.
+type nistPoint interface {
+	// scalarBaseMult returns scalar * generator.
+	scalarBaseMult(scalar []byte) ([]byte, error)
+	// checkPoint returns an error if point is not on the curve.
+	checkPoint(point []byte) error
+	// scalarMultX returns the x-coordinate of scalar * point.
+	scalarMultX(point, scalar []byte) ([]byte, error)
+}
+
+func (c *nistCurve) String() string {
+	return c.name
+}
+
+// scalarSize returns the length in bytes of a private key.
+func (c *nistCurve) scalarSize() int {
+	return (c.curve.Params().N.BitLen() + 7) / 8
+}
+
+func (c *nistCurve) GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	key := make([]byte, c.scalarSize())
+	// Mask off any excess bits of the most significant byte, as the order
+	// of P-521 is not a whole number of bytes.
+	mask := byte(0xff)
+	if excess := len(key)*8 - c.curve.Params().N.BitLen(); excess > 0 {
+		mask >>= uint(excess)
+	}
+	for {
+		if _, err := io.ReadFull(rand, key); err != nil {
+			return nil, err
+		}
+		key[0] &= mask
+		// As in elliptic.GenerateKey, so that a rand that returns all zeros,
+		// as in tests, doesn't yield the zero scalar and loop forever. It
+		// also keeps the keys generated from a fixed rand the same as
+		// before.
+		key[1] ^= 0x42
+		// Retry if the scalar is out of range. This happens with negligible
+		// probability for all three curves.
+		if k, err := c.NewPrivateKey(key); err == nil {
+			return k, nil
+		}
+	}
+}
+
+func (c *nistCurve) NewPrivateKey(key []byte) (*PrivateKey, error) {
+	if len(key) != c.scalarSize() {
+		return nil, errors.New("crypto/ecdh: invalid private key size")
+	}
+	if isZero(key) || !isLess(key, c.curve.Params().N.Bytes()) {
+		return nil, errors.New("crypto/ecdh: invalid private key")
+	}
+	publicKey, err := c.point.scalarBaseMult(key)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{
+		curve:      c,
+		privateKey: append([]byte(nil), key...),
+		publicKey:  &PublicKey{curve: c, publicKey: publicKey},
+	}, nil
+}
+
+func (c *nistCurve) NewPublicKey(key []byte) (*PublicKey, error) {
+	// Reject the point at infinity and compressed encodings.
+	if len(key) == 0 || key[0] != 4 {
+		return nil, errors.New("crypto/ecdh: invalid public key")
+	}
+	if err := c.point.checkPoint(key); err != nil {
+		return nil, errors.New("crypto/ecdh: invalid public key")
+	}
+	return &PublicKey{
+		curve:     c,
+		publicKey: append([]byte(nil), key...),
+	}, nil
+}
+
+func (c *nistCurve) ecdh(local *PrivateKey, remote *PublicKey) ([]byte, error) {
+	// This can't fail, as NewPublicKey rejects invalid points and the point
+	// at infinity, and NewPrivateKey rejects invalid scalars and the zero
+	// value. In a prime order group such as the NIST curves the product of
+	// the two can't be the point at infinity.
+	return c.point.scalarMultX(remote.publicKey, local.privateKey)
+}
+
+// ellipticPoint implements nistPoint with the constant time scalar methods
+// of a crypto/elliptic Curve.
+type ellipticPoint struct {
+	curve elliptic.Curve
+}
+
+func (p ellipticPoint) scalarBaseMult(scalar []byte) ([]byte, error) {
+	x, y := p.curve.ScalarBaseMult(scalar)
+	return elliptic.Marshal(p.curve, x, y), nil
+}
+
+func (p ellipticPoint) checkPoint(point []byte) error {
+	// Unmarshal checks that the point is on the curve.
+	if x, _ := elliptic.Unmarshal(p.curve, point); x == nil {
+		return errors.New("crypto/ecdh: invalid point")
+	}
+	return nil
+}
+
+func (p ellipticPoint) scalarMultX(point, scalar []byte) ([]byte, error) {
+	x, y := elliptic.Unmarshal(p.curve, point)
+	if x == nil {
+		return nil, errors.New("crypto/ecdh: invalid public key")
+	}
+	xShared, _ := p.curve.ScalarMult(x, y, scalar)
+	out := make([]byte, (p.curve.Params().BitSize+7)/8)
+	xBytes := xShared.Bytes()
+	copy(out[len(out)-len(xBytes):], xBytes)
+	return out, nil
+}
+
+type p384Point struct{}
+
+func (p384Point) scalarBaseMult(scalar []byte) ([]byte, error) {
+	p, err := nistec.NewP384Point().ScalarBaseMult(scalar)
+	if err != nil {
+		return nil, err
+	}
+	return p.Bytes(), nil
+}
+
+func (p384Point) checkPoint(point []byte) error {
+	_, err := nistec.NewP384Point().SetBytes(point)
+	return err
+}
+
+func (p384Point) scalarMultX(point, scalar []byte) ([]byte, error) {
+	p, err := nistec.NewP384Point().SetBytes(point)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.ScalarMult(p, scalar); err != nil {
+		return nil, err
+	}
+	return p.BytesX()
+}
+
+type p521Point struct{}
+
+func (p521Point) scalarBaseMult(scalar []byte) ([]byte, error) {
+	p, err := nistec.NewP521Point().ScalarBaseMult(scalar)
+	if err != nil {
+		return nil, err
+	}
+	return p.Bytes(), nil
+}
+
+func (p521Point) checkPoint(point []byte) error {
+	_, err := nistec.NewP521Point().SetBytes(point)
+	return err
+}
+
+func (p521Point) scalarMultX(point, scalar []byte) ([]byte, error) {
+	p, err := nistec.NewP521Point().SetBytes(point)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.ScalarMult(p, scalar); err != nil {
+		return nil, err
+	}
+	return p.BytesX()
+}
+
+// isLess reports whether a < b, where a and b are big-endian buffers of the
+// same length (b may be shorter, as it has no leading zeroes) and shorter
+// than 72 bytes. The comparison runs in time that depends only on len(a).
+func isLess(a, b []byte) bool {
+	if len(b) > len(a) {
+		return true
+	}
+	// Pad b to the length of a.
+	var buf [72]byte
+	bb := buf[len(buf)-len(a):]
+	copy(bb[len(bb)-len(b):], b)
+
+	// Compute a - b from the least significant byte, tracking the borrow.
+	var borrow uint32
+	for i := len(a) - 1; i >= 0; i-- {
+		d := uint32(a[i]) - uint32(bb[i]) - borrow
+		borrow = d >> 31
+	}
+	// If there is a borrow at the end of the operation, then a < b.
+	return borrow == 1
+}
+
+// P256 returns a Curve which implements NIST P-256 (FIPS 186-3, section D.2.3),
+// also known as secp256r1 or prime256v1.
+//
+// Multiple invocations of this function will return the same value, which can
+// be used for equality checks and switch statements.
+func P256() Curve { return p256 }
+
+var p256 = &nistCurve{name: "P-256", curve: elliptic.P256(), point: ellipticPoint{elliptic.P256()}}
+
+// P384 returns a Curve which implements NIST P-384 (FIPS 186-3, section D.2.4),
+// also known as secp384r1.
+//
+// Multiple invocations of this function will return the same value, which can
+// be used for equality checks and switch statements.
+func P384() Curve { return p384 }
+
+var p384 = &nistCurve{name: "P-384", curve: elliptic.P384(), point: p384Point{}}
+
+// P521 returns a Curve which implements NIST P-521 (FIPS 186-3, section D.2.5),
+// also known as secp521r1.
+//
+// Multiple invocations of this function will return the same value, which can
+// be used for equality checks and switch statements.
+func P521() Curve { return p521 }
+
+var p521 = &nistCurve{name: "P-521", curve: elliptic.P521(), point: p521Point{}}
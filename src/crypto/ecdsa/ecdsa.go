@@ -20,6 +20,7 @@ import (
 	"crypto"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/ecdh"
 	"crypto/elliptic"
 	"crypto/sha512"
 	"encoding/asn1"
@@ -66,6 +67,88 @@ func (priv *PrivateKey) Public() crypto.PublicKey {
 	return &priv.PublicKey
 }
 
+// ECDH returns k as a ecdh.PublicKey. It returns an error if the key is
+// invalid according to the definition of ecdh.Curve.NewPublicKey, or if the
+// Curve is not supported by crypto/ecdh.
+func (k *PublicKey) ECDH() (*ecdh.PublicKey, error) {
+	c := curveToECDH(k.Curve)
+	if c == nil {
+		return nil, errors.New("ecdsa: unsupported curve by crypto/ecdh")
+	}
+	if !k.Curve.IsOnCurve(k.X, k.Y) {
+		return nil, errors.New("ecdsa: invalid public key")
+	}
+	return c.NewPublicKey(elliptic.Marshal(k.Curve, k.X, k.Y))
+}
+
+// ECDH returns k as a ecdh.PrivateKey. It returns an error if the key is
+// invalid according to the definition of ecdh.Curve.NewPrivateKey, or if the
+// Curve is not supported by crypto/ecdh.
+func (k *PrivateKey) ECDH() (*ecdh.PrivateKey, error) {
+	c := curveToECDH(k.Curve)
+	if c == nil {
+		return nil, errors.New("ecdsa: unsupported curve by crypto/ecdh")
+	}
+	size := (k.Curve.Params().N.BitLen() + 7) / 8
+	if k.D.BitLen() > size*8 {
+		return nil, errors.New("ecdsa: invalid private key")
+	}
+	d := k.D.Bytes()
+	scalar := make([]byte, size)
+	copy(scalar[size-len(d):], d)
+	return c.NewPrivateKey(scalar)
+}
+
+// PublicKeyFromECDH returns k as an ECDSA public key. It returns an error if
+// k is not on one of the NIST curves, P-256, P-384 or P-521.
+func PublicKeyFromECDH(k *ecdh.PublicKey) (*PublicKey, error) {
+	c := curveFromECDH(k.Curve())
+	if c == nil {
+		return nil, errors.New("ecdsa: unsupported curve by crypto/ecdsa")
+	}
+	x, y := elliptic.Unmarshal(c, k.Bytes())
+	if x == nil {
+		return nil, errors.New("ecdsa: invalid public key")
+	}
+	return &PublicKey{Curve: c, X: x, Y: y}, nil
+}
+
+// PrivateKeyFromECDH returns k as an ECDSA private key. It returns an error
+// if k is not on one of the NIST curves, P-256, P-384 or P-521.
+func PrivateKeyFromECDH(k *ecdh.PrivateKey) (*PrivateKey, error) {
+	pub, err := PublicKeyFromECDH(k.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{PublicKey: *pub, D: new(big.Int).SetBytes(k.Bytes())}, nil
+}
+
+func curveToECDH(c elliptic.Curve) ecdh.Curve {
+	switch c {
+	case elliptic.P256():
+		return ecdh.P256()
+	case elliptic.P384():
+		return ecdh.P384()
+	case elliptic.P521():
+		return ecdh.P521()
+	default:
+		return nil
+	}
+}
+
+func curveFromECDH(c ecdh.Curve) elliptic.Curve {
+	switch c {
+	case ecdh.P256():
+		return elliptic.P256()
+	case ecdh.P384():
+		return elliptic.P384()
+	case ecdh.P521():
+		return elliptic.P521()
+	default:
+		return nil
+	}
+}
+
 // Sign signs digest with priv, reading randomness from rand. The opts argument
 // is not currently used but, in keeping with the crypto.Signer interface,
 // should be the hash function used to digest the message.
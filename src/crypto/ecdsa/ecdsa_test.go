@@ -42,6 +42,46 @@ func TestKeyGeneration(t *testing.T) {
 	testKeyGeneration(t, elliptic.P521(), "p521")
 }
 
+func testECDHConversion(t *testing.T, c elliptic.Curve, tag string) {
+	priv, err := GenerateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatalf("%s: error: %s", tag, err)
+	}
+	ek, err := priv.ECDH()
+	if err != nil {
+		t.Fatalf("%s: ECDH conversion failed: %s", tag, err)
+	}
+	epub, err := priv.PublicKey.ECDH()
+	if err != nil {
+		t.Fatalf("%s: ECDH public key conversion failed: %s", tag, err)
+	}
+	if !ek.PublicKey().Equal(epub) {
+		t.Errorf("%s: converted public keys do not match", tag)
+	}
+
+	back, err := PrivateKeyFromECDH(ek)
+	if err != nil {
+		t.Fatalf("%s: conversion from ECDH failed: %s", tag, err)
+	}
+	if back.Curve != c || back.D.Cmp(priv.D) != 0 || back.X.Cmp(priv.X) != 0 || back.Y.Cmp(priv.Y) != 0 {
+		t.Errorf("%s: round-tripped private key does not match", tag)
+	}
+}
+
+func TestECDHConversion(t *testing.T) {
+	testECDHConversion(t, elliptic.P256(), "p256")
+	testECDHConversion(t, elliptic.P384(), "p384")
+	testECDHConversion(t, elliptic.P521(), "p521")
+
+	priv, err := GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := priv.ECDH(); err == nil {
+		t.Error("P-224 key unexpectedly converted to ECDH")
+	}
+}
+
 func BenchmarkSignP256(b *testing.B) {
 	b.ResetTimer()
 	p256 := elliptic.P256()
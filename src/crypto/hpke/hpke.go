@@ -0,0 +1,388 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hpke implements Hybrid Public Key Encryption (HPKE) as defined in
+// RFC 9180.
+//
+// A sender uses the recipient's public key to establish an encryption
+// context, and sends the returned encapsulated key along with any ciphertexts
+// to the recipient, which uses it with its private key to establish the
+// matching decryption context. All four modes of RFC 9180 are supported:
+// base, PSK, Auth and AuthPSK.
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// A KDF is an HPKE key derivation function identifier, as registered in
+// RFC 9180, Section 7.2.
+type KDF uint16
+
+// The supported key derivation functions.
+const (
+	HKDF_SHA256 KDF = 0x0001
+	HKDF_SHA384 KDF = 0x0002
+	HKDF_SHA512 KDF = 0x0003
+)
+
+func (k KDF) hash() (func() hash.Hash, error) {
+	switch k {
+	case HKDF_SHA256:
+		return sha256.New, nil
+	case HKDF_SHA384:
+		return sha512.New384, nil
+	case HKDF_SHA512:
+		return sha512.New, nil
+	default:
+		return nil, errors.New("hpke: unsupported KDF")
+	}
+}
+
+// labeledExtract implements LabeledExtract from RFC 9180, Section 4.
+// k must be a supported KDF.
+func (k KDF) labeledExtract(suiteID, salt []byte, label string, ikm []byte) []byte {
+	h, _ := k.hash()
+	labeledIKM := make([]byte, 0, 7+len(suiteID)+len(label)+len(ikm))
+	labeledIKM = append(labeledIKM, "HPKE-v1"...)
+	labeledIKM = append(labeledIKM, suiteID...)
+	labeledIKM = append(labeledIKM, label...)
+	labeledIKM = append(labeledIKM, ikm...)
+	if salt == nil {
+		salt = []byte{}
+	}
+	return hkdf.Extract(h, labeledIKM, salt)
+}
+
+// labeledExpand implements LabeledExpand from RFC 9180, Section 4.
+// k must be a supported KDF, and length must be at most 255 times the
+// output size of its hash.
+func (k KDF) labeledExpand(suiteID, prk []byte, label string, info []byte, length int) []byte {
+	h, _ := k.hash()
+	labeledInfo := make([]byte, 2, 2+7+len(suiteID)+len(label)+len(info))
+	binary.BigEndian.PutUint16(labeledInfo, uint16(length))
+	labeledInfo = append(labeledInfo, "HPKE-v1"...)
+	labeledInfo = append(labeledInfo, suiteID...)
+	labeledInfo = append(labeledInfo, label...)
+	labeledInfo = append(labeledInfo, info...)
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(h, prk, labeledInfo), out); err != nil {
+		panic("hpke: internal error: HKDF-Expand failed: " + err.Error())
+	}
+	return out
+}
+
+// An AEAD is an HPKE authenticated encryption identifier, as registered in
+// RFC 9180, Section 7.3.
+type AEAD uint16
+
+// The supported AEADs. ExportOnly contexts can only be used to export
+// secrets, and their Seal and Open methods always return an error.
+const (
+	AES_128_GCM      AEAD = 0x0001
+	AES_256_GCM      AEAD = 0x0002
+	ChaCha20Poly1305 AEAD = 0x0003
+	ExportOnly       AEAD = 0xFFFF
+)
+
+// keySize returns Nk, and whether the AEAD is supported.
+func (a AEAD) keySize() (int, bool) {
+	switch a {
+	case AES_128_GCM:
+		return 16, true
+	case AES_256_GCM:
+		return 32, true
+	case ChaCha20Poly1305:
+		return chacha20poly1305.KeySize, true
+	case ExportOnly:
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+func (a AEAD) new(key []byte) (cipher.AEAD, error) {
+	switch a {
+	case AES_128_GCM, AES_256_GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	case ExportOnly:
+		return nil, nil
+	default:
+		return nil, errors.New("hpke: unsupported AEAD")
+	}
+}
+
+// A Suite is a combination of a KEM, a KDF and an AEAD.
+type Suite struct {
+	KEM  KEM
+	KDF  KDF
+	AEAD AEAD
+}
+
+func (s Suite) id() []byte {
+	id := []byte("HPKE\x00\x00\x00\x00\x00\x00")
+	binary.BigEndian.PutUint16(id[4:], uint16(s.KEM))
+	binary.BigEndian.PutUint16(id[6:], uint16(s.KDF))
+	binary.BigEndian.PutUint16(id[8:], uint16(s.AEAD))
+	return id
+}
+
+// The HPKE modes, from RFC 9180, Section 5.
+const (
+	modeBase    = 0x00
+	modePSK     = 0x01
+	modeAuth    = 0x02
+	modeAuthPSK = 0x03
+)
+
+// SetupSender establishes an encryption context to the holder of the private
+// key corresponding to pkR, in base mode. It returns the encapsulated key,
+// which must be sent to the recipient.
+func (s Suite) SetupSender(pkR *ecdh.PublicKey, info []byte) (enc []byte, sender *Sender, err error) {
+	return s.setupSender(modeBase, pkR, info, nil, nil, nil)
+}
+
+// SetupSenderPSK is like SetupSender, but additionally authenticates the
+// sender as a holder of the pre-shared key psk, identified by pskID.
+func (s Suite) SetupSenderPSK(pkR *ecdh.PublicKey, info, psk, pskID []byte) (enc []byte, sender *Sender, err error) {
+	return s.setupSender(modePSK, pkR, info, psk, pskID, nil)
+}
+
+// SetupSenderAuth is like SetupSender, but additionally authenticates the
+// sender as a holder of the private key skS.
+func (s Suite) SetupSenderAuth(pkR *ecdh.PublicKey, info []byte, skS *ecdh.PrivateKey) (enc []byte, sender *Sender, err error) {
+	if skS == nil {
+		return nil, nil, errors.New("hpke: missing sender private key")
+	}
+	return s.setupSender(modeAuth, pkR, info, nil, nil, skS)
+}
+
+// SetupSenderAuthPSK combines SetupSenderPSK and SetupSenderAuth.
+func (s Suite) SetupSenderAuthPSK(pkR *ecdh.PublicKey, info, psk, pskID []byte, skS *ecdh.PrivateKey) (enc []byte, sender *Sender, err error) {
+	if skS == nil {
+		return nil, nil, errors.New("hpke: missing sender private key")
+	}
+	return s.setupSender(modeAuthPSK, pkR, info, psk, pskID, skS)
+}
+
+func (s Suite) setupSender(mode byte, pkR *ecdh.PublicKey, info, psk, pskID []byte, skS *ecdh.PrivateKey) ([]byte, *Sender, error) {
+	kem, err := s.KEM.params()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.check(mode, psk, pskID); err != nil {
+		return nil, nil, err
+	}
+	sharedSecret, enc, err := kem.encap(s.KEM, pkR, skS)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, err := s.keySchedule(mode, sharedSecret, info, psk, pskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, &Sender{ctx}, nil
+}
+
+// SetupRecipient establishes the decryption context matching the encryption
+// context established by a sender with SetupSender, given the encapsulated
+// key enc and the recipient private key skR.
+func (s Suite) SetupRecipient(enc []byte, skR *ecdh.PrivateKey, info []byte) (*Recipient, error) {
+	return s.setupRecipient(modeBase, enc, skR, info, nil, nil, nil)
+}
+
+// SetupRecipientPSK is like SetupRecipient, for a sender that used
+// SetupSenderPSK.
+func (s Suite) SetupRecipientPSK(enc []byte, skR *ecdh.PrivateKey, info, psk, pskID []byte) (*Recipient, error) {
+	return s.setupRecipient(modePSK, enc, skR, info, psk, pskID, nil)
+}
+
+// SetupRecipientAuth is like SetupRecipient, for a sender that used
+// SetupSenderAuth with the private key corresponding to pkS.
+func (s Suite) SetupRecipientAuth(enc []byte, skR *ecdh.PrivateKey, info []byte, pkS *ecdh.PublicKey) (*Recipient, error) {
+	if pkS == nil {
+		return nil, errors.New("hpke: missing sender public key")
+	}
+	return s.setupRecipient(modeAuth, enc, skR, info, nil, nil, pkS)
+}
+
+// SetupRecipientAuthPSK combines SetupRecipientPSK and SetupRecipientAuth.
+func (s Suite) SetupRecipientAuthPSK(enc []byte, skR *ecdh.PrivateKey, info, psk, pskID []byte, pkS *ecdh.PublicKey) (*Recipient, error) {
+	if pkS == nil {
+		return nil, errors.New("hpke: missing sender public key")
+	}
+	return s.setupRecipient(modeAuthPSK, enc, skR, info, psk, pskID, pkS)
+}
+
+func (s Suite) setupRecipient(mode byte, enc []byte, skR *ecdh.PrivateKey, info, psk, pskID []byte, pkS *ecdh.PublicKey) (*Recipient, error) {
+	kem, err := s.KEM.params()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(mode, psk, pskID); err != nil {
+		return nil, err
+	}
+	sharedSecret, err := kem.decap(s.KEM, enc, skR, pkS)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := s.keySchedule(mode, sharedSecret, info, psk, pskID)
+	if err != nil {
+		return nil, err
+	}
+	return &Recipient{ctx}, nil
+}
+
+// check validates the suite and the PSK inputs, as in VerifyPSKInputs from
+// RFC 9180, Section 5.1.
+func (s Suite) check(mode byte, psk, pskID []byte) error {
+	if _, err := s.KDF.hash(); err != nil {
+		return err
+	}
+	if _, ok := s.AEAD.keySize(); !ok {
+		return errors.New("hpke: unsupported AEAD")
+	}
+	gotPSK, gotPSKID := len(psk) > 0, len(pskID) > 0
+	if gotPSK != gotPSKID {
+		return errors.New("hpke: inconsistent PSK inputs")
+	}
+	if (mode == modePSK || mode == modeAuthPSK) && !gotPSK {
+		return errors.New("hpke: missing required PSK input")
+	}
+	return nil
+}
+
+// keySchedule implements KeySchedule from RFC 9180, Section 5.1.
+func (s Suite) keySchedule(mode byte, sharedSecret, info, psk, pskID []byte) (*context, error) {
+	suiteID := s.id()
+	pskIDHash := s.KDF.labeledExtract(suiteID, nil, "psk_id_hash", pskID)
+	infoHash := s.KDF.labeledExtract(suiteID, nil, "info_hash", info)
+	ksContext := append([]byte{mode}, pskIDHash...)
+	ksContext = append(ksContext, infoHash...)
+
+	secret := s.KDF.labeledExtract(suiteID, sharedSecret, "secret", psk)
+
+	h, _ := s.KDF.hash()
+	ctx := &context{
+		suite:          s,
+		suiteID:        suiteID,
+		exporterSecret: s.KDF.labeledExpand(suiteID, secret, "exp", ksContext, h().Size()),
+	}
+	if s.AEAD == ExportOnly {
+		return ctx, nil
+	}
+	nk, _ := s.AEAD.keySize()
+	key := s.KDF.labeledExpand(suiteID, secret, "key", ksContext, nk)
+	aead, err := s.AEAD.new(key)
+	if err != nil {
+		return nil, err
+	}
+	ctx.aead = aead
+	ctx.baseNonce = s.KDF.labeledExpand(suiteID, secret, "base_nonce", ksContext, aead.NonceSize())
+	return ctx, nil
+}
+
+// context is the state shared by Sender and Recipient.
+type context struct {
+	suite          Suite
+	suiteID        []byte
+	exporterSecret []byte
+
+	aead      cipher.AEAD // nil for ExportOnly
+	baseNonce []byte
+	seq       uint64
+}
+
+// nextNonce implements ComputeNonce and IncrementSeq from RFC 9180,
+// Section 5.2.
+func (c *context) nextNonce() ([]byte, error) {
+	if c.aead == nil {
+		return nil, errors.New("hpke: encryption is not supported by export-only contexts")
+	}
+	if c.seq == ^uint64(0) {
+		return nil, errors.New("hpke: message limit reached")
+	}
+	nonce := append([]byte(nil), c.baseNonce...)
+	var seq [8]byte
+	binary.BigEndian.PutUint64(seq[:], c.seq)
+	for i := range seq {
+		nonce[len(nonce)-8+i] ^= seq[i]
+	}
+	c.seq++
+	return nonce, nil
+}
+
+func (c *context) export(exporterContext []byte, length int) ([]byte, error) {
+	h, _ := c.suite.KDF.hash()
+	if length < 0 || length > 255*h().Size() {
+		return nil, errors.New("hpke: invalid exporter output length")
+	}
+	return c.suite.KDF.labeledExpand(c.suiteID, c.exporterSecret, "sec", exporterContext, length), nil
+}
+
+// A Sender is an HPKE encryption context. It is not safe for concurrent use.
+type Sender struct {
+	ctx *context
+}
+
+// Seal encrypts and authenticates plaintext, authenticates aad, and returns
+// the ciphertext. Messages are sequenced, and must be opened by the
+// Recipient in the same order in which they were sealed.
+func (s *Sender) Seal(aad, plaintext []byte) ([]byte, error) {
+	nonce, err := s.ctx.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	return s.ctx.aead.Seal(nil, nonce, plaintext, aad), nil
+}
+
+// Export derives a secret of the given length from the context, bound to
+// exporterContext, as specified in RFC 9180, Section 5.3.
+func (s *Sender) Export(exporterContext []byte, length int) ([]byte, error) {
+	return s.ctx.export(exporterContext, length)
+}
+
+// A Recipient is an HPKE decryption context. It is not safe for concurrent
+// use.
+type Recipient struct {
+	ctx *context
+}
+
+// Open decrypts and authenticates ciphertext, authenticates aad, and returns
+// the plaintext. The sequence number only advances if decryption succeeds.
+func (r *Recipient) Open(aad, ciphertext []byte) ([]byte, error) {
+	nonce, err := r.ctx.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := r.ctx.aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		r.ctx.seq--
+		return nil, err
+	}
+	return plaintext, nil
+}
+
+// Export derives a secret of the given length from the context, bound to
+// exporterContext, as specified in RFC 9180, Section 5.3.
+func (r *Recipient) Export(exporterContext []byte, length int) ([]byte, error) {
+	return r.ctx.export(exporterContext, length)
+}
@@ -0,0 +1,350 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hpke
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestVectors checks the test vectors from RFC 9180, Appendix A, in all
+// four modes.
+func TestVectors(t *testing.T) {
+	vectorsJSON, err := ioutil.ReadFile("testdata/rfc9180.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []struct {
+		Mode        uint16 `json:"mode"`
+		KEM         uint16 `json:"kem_id"`
+		KDF         uint16 `json:"kdf_id"`
+		AEAD        uint16 `json:"aead_id"`
+		Info        string `json:"info"`
+		IkmE        string `json:"ikmE"`
+		IkmR        string `json:"ikmR"`
+		IkmS        string `json:"ikmS"`
+		SkEm        string `json:"skEm"`
+		SkRm        string `json:"skRm"`
+		SkSm        string `json:"skSm"`
+		Psk         string `json:"psk"`
+		PskID       string `json:"psk_id"`
+		PkEm        string `json:"pkEm"`
+		PkRm        string `json:"pkRm"`
+		PkSm        string `json:"pkSm"`
+		Enc         string `json:"enc"`
+		Encryptions []struct {
+			Seq int    `json:"seq"`
+			Aad string `json:"aad"`
+			Pt  string `json:"pt"`
+			Ct  string `json:"ct"`
+		} `json:"encryptions"`
+		Exports []struct {
+			Context string `json:"exporter_context"`
+			L       int    `json:"L"`
+			Value   string `json:"exported_value"`
+		} `json:"exports"`
+	}
+	if err := json.Unmarshal(vectorsJSON, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vector := range vectors {
+		name := fmt.Sprintf("mode %04x kem %04x kdf %04x aead %04x",
+			vector.Mode, vector.KEM, vector.KDF, vector.AEAD)
+		t.Run(name, func(t *testing.T) {
+			s := Suite{KEM(vector.KEM), KDF(vector.KDF), AEAD(vector.AEAD)}
+
+			skE, err := s.KEM.DeriveKeyPair(mustDecodeHex(t, vector.IkmE))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(skE.Bytes(), mustDecodeHex(t, vector.SkEm)) {
+				t.Errorf("unexpected ephemeral private key: got %x, want %s", skE.Bytes(), vector.SkEm)
+			}
+			if !bytes.Equal(skE.PublicKey().Bytes(), mustDecodeHex(t, vector.PkEm)) {
+				t.Errorf("unexpected ephemeral public key: got %x, want %s", skE.PublicKey().Bytes(), vector.PkEm)
+			}
+			skR, err := s.KEM.DeriveKeyPair(mustDecodeHex(t, vector.IkmR))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(skR.Bytes(), mustDecodeHex(t, vector.SkRm)) {
+				t.Errorf("unexpected recipient private key: got %x, want %s", skR.Bytes(), vector.SkRm)
+			}
+			if !bytes.Equal(skR.PublicKey().Bytes(), mustDecodeHex(t, vector.PkRm)) {
+				t.Errorf("unexpected recipient public key: got %x, want %s", skR.PublicKey().Bytes(), vector.PkRm)
+			}
+
+			var skS *ecdh.PrivateKey
+			if vector.IkmS != "" {
+				skS, err = s.KEM.DeriveKeyPair(mustDecodeHex(t, vector.IkmS))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(skS.Bytes(), mustDecodeHex(t, vector.SkSm)) {
+					t.Errorf("unexpected sender private key: got %x, want %s", skS.Bytes(), vector.SkSm)
+				}
+				if !bytes.Equal(skS.PublicKey().Bytes(), mustDecodeHex(t, vector.PkSm)) {
+					t.Errorf("unexpected sender public key: got %x, want %s", skS.PublicKey().Bytes(), vector.PkSm)
+				}
+			}
+
+			testingOnlyGenerateKey = func() *ecdh.PrivateKey { return skE }
+			defer func() { testingOnlyGenerateKey = nil }()
+
+			info := mustDecodeHex(t, vector.Info)
+			psk, pskID := mustDecodeHex(t, vector.Psk), mustDecodeHex(t, vector.PskID)
+			var (
+				enc       []byte
+				sender    *Sender
+				recipient *Recipient
+			)
+			switch vector.Mode {
+			case 0:
+				enc, sender, err = s.SetupSender(skR.PublicKey(), info)
+			case 1:
+				enc, sender, err = s.SetupSenderPSK(skR.PublicKey(), info, psk, pskID)
+			case 2:
+				enc, sender, err = s.SetupSenderAuth(skR.PublicKey(), info, skS)
+			case 3:
+				enc, sender, err = s.SetupSenderAuthPSK(skR.PublicKey(), info, psk, pskID, skS)
+			default:
+				t.Fatalf("unknown mode %d", vector.Mode)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(enc, mustDecodeHex(t, vector.Enc)) {
+				t.Errorf("unexpected encapsulated key: got %x, want %s", enc, vector.Enc)
+			}
+			switch vector.Mode {
+			case 0:
+				recipient, err = s.SetupRecipient(enc, skR, info)
+			case 1:
+				recipient, err = s.SetupRecipientPSK(enc, skR, info, psk, pskID)
+			case 2:
+				recipient, err = s.SetupRecipientAuth(enc, skR, info, skS.PublicKey())
+			case 3:
+				recipient, err = s.SetupRecipientAuthPSK(enc, skR, info, psk, pskID, skS.PublicKey())
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			seq := 0
+			for _, e := range vector.Encryptions {
+				aad, pt := mustDecodeHex(t, e.Aad), mustDecodeHex(t, e.Pt)
+				// Advance both contexts to the sequence number of the vector.
+				for ; seq < e.Seq; seq++ {
+					ct, err := sender.Seal(nil, nil)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := recipient.Open(nil, ct); err != nil {
+						t.Fatal(err)
+					}
+				}
+				ct, err := sender.Seal(aad, pt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(ct, mustDecodeHex(t, e.Ct)) {
+					t.Errorf("seq %d: unexpected ciphertext: got %x, want %s", e.Seq, ct, e.Ct)
+				}
+				got, err := recipient.Open(aad, ct)
+				if err != nil {
+					t.Fatalf("seq %d: %v", e.Seq, err)
+				}
+				if !bytes.Equal(got, pt) {
+					t.Errorf("seq %d: unexpected plaintext: got %x, want %x", e.Seq, got, pt)
+				}
+				seq++
+			}
+			if s.AEAD == ExportOnly {
+				if _, err := sender.Seal(nil, nil); err == nil {
+					t.Error("expected error from Seal with export-only AEAD")
+				}
+				if _, err := recipient.Open(nil, nil); err == nil {
+					t.Error("expected error from Open with export-only AEAD")
+				}
+			}
+
+			for _, e := range vector.Exports {
+				context := mustDecodeHex(t, e.Context)
+				value, err := sender.Export(context, e.L)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(value, mustDecodeHex(t, e.Value)) {
+					t.Errorf("unexpected exported value: got %x, want %s", value, e.Value)
+				}
+				got, err := recipient.Export(context, e.L)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, value) {
+					t.Errorf("recipient: unexpected exported value: got %x, want %x", got, value)
+				}
+			}
+		})
+	}
+}
+
+var allKEMs = []KEM{DHKEM_P256_HKDF_SHA256, DHKEM_P384_HKDF_SHA384, DHKEM_P521_HKDF_SHA512, DHKEM_X25519_HKDF_SHA256}
+
+func TestModes(t *testing.T) {
+	psk := []byte("0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82")
+	pskID := []byte("Ennyn Durin aran Moria")
+	info := []byte("Ode on a Grecian Urn")
+	for _, kem := range allKEMs {
+		s := Suite{kem, HKDF_SHA256, ChaCha20Poly1305}
+		skR, err := kem.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		skS, err := kem.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkR, pkS := skR.PublicKey(), skS.PublicKey()
+
+		modes := []struct {
+			name   string
+			sender func() ([]byte, *Sender, error)
+			recip  func(enc []byte) (*Recipient, error)
+		}{
+			{"base", func() ([]byte, *Sender, error) { return s.SetupSender(pkR, info) },
+				func(enc []byte) (*Recipient, error) { return s.SetupRecipient(enc, skR, info) }},
+			{"psk", func() ([]byte, *Sender, error) { return s.SetupSenderPSK(pkR, info, psk, pskID) },
+				func(enc []byte) (*Recipient, error) { return s.SetupRecipientPSK(enc, skR, info, psk, pskID) }},
+			{"auth", func() ([]byte, *Sender, error) { return s.SetupSenderAuth(pkR, info, skS) },
+				func(enc []byte) (*Recipient, error) { return s.SetupRecipientAuth(enc, skR, info, pkS) }},
+			{"authpsk", func() ([]byte, *Sender, error) { return s.SetupSenderAuthPSK(pkR, info, psk, pskID, skS) },
+				func(enc []byte) (*Recipient, error) { return s.SetupRecipientAuthPSK(enc, skR, info, psk, pskID, pkS) }},
+		}
+		for _, m := range modes {
+			enc, sender, err := m.sender()
+			if err != nil {
+				t.Fatalf("%04x %s: %v", kem, m.name, err)
+			}
+			recipient, err := m.recip(enc)
+			if err != nil {
+				t.Fatalf("%04x %s: %v", kem, m.name, err)
+			}
+			for i := 0; i < 3; i++ {
+				msg := []byte(fmt.Sprintf("message %d", i))
+				ct, err := sender.Seal(info, msg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				pt, err := recipient.Open(info, ct)
+				if err != nil {
+					t.Fatalf("%04x %s: %v", kem, m.name, err)
+				}
+				if !bytes.Equal(pt, msg) {
+					t.Errorf("%04x %s: got %q, want %q", kem, m.name, pt, msg)
+				}
+			}
+
+			// A recipient using the wrong mode must not be able to decrypt.
+			ct, err := sender.Seal(nil, []byte("hello"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, other := range modes {
+				if other.name == m.name {
+					continue
+				}
+				r, err := other.recip(enc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				r.ctx.seq = recipient.ctx.seq
+				if _, err := r.Open(nil, ct); err == nil {
+					t.Errorf("%04x: %s recipient opened %s ciphertext", kem, other.name, m.name)
+				}
+			}
+		}
+	}
+}
+
+func TestOpenFailure(t *testing.T) {
+	s := Suite{DHKEM_X25519_HKDF_SHA256, HKDF_SHA256, AES_128_GCM}
+	skR, err := s.KEM.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, sender, err := s.SetupSender(skR.PublicKey(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := s.SetupRecipient(enc, skR, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := sender.Seal(nil, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := append([]byte(nil), ct...)
+	bad[0] ^= 1
+	if _, err := recipient.Open(nil, bad); err == nil {
+		t.Fatal("tampered ciphertext opened successfully")
+	}
+	// A failed Open must not advance the sequence number.
+	if pt, err := recipient.Open(nil, ct); err != nil || string(pt) != "hello" {
+		t.Fatalf("Open after failure = %q, %v", pt, err)
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	skR, err := DHKEM_P256_HKDF_SHA256.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkR := skR.PublicKey()
+	for _, s := range []Suite{
+		{0x9999, HKDF_SHA256, AES_128_GCM},
+		{DHKEM_P256_HKDF_SHA256, 0x9999, AES_128_GCM},
+		{DHKEM_P256_HKDF_SHA256, HKDF_SHA256, 0x9999},
+		{DHKEM_X25519_HKDF_SHA256, HKDF_SHA256, AES_128_GCM}, // mismatched key
+	} {
+		if _, _, err := s.SetupSender(pkR, nil); err == nil {
+			t.Errorf("%+v: SetupSender unexpectedly succeeded", s)
+		}
+	}
+	s := Suite{DHKEM_P256_HKDF_SHA256, HKDF_SHA256, AES_128_GCM}
+	if _, _, err := s.SetupSenderPSK(pkR, nil, []byte("secret"), nil); err == nil {
+		t.Error("SetupSenderPSK accepted a PSK without an ID")
+	}
+	if _, _, err := s.SetupSenderPSK(pkR, nil, nil, nil); err == nil {
+		t.Error("SetupSenderPSK accepted an empty PSK")
+	}
+	if _, err := s.SetupRecipient([]byte("not a point"), skR, nil); err == nil {
+		t.Error("SetupRecipient accepted an invalid encapsulated key")
+	}
+	_, sender, err := s.SetupSender(pkR, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sender.Export(nil, 255*32+1); err == nil {
+		t.Error("Export accepted an excessive length")
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hpke
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// A KEM is an HPKE key encapsulation mechanism identifier, as registered in
+// RFC 9180, Section 7.1.
+type KEM uint16
+
+// The supported key encapsulation mechanisms. All of them are DHKEM
+// instances, built on top of crypto/ecdh.
+const (
+	DHKEM_P256_HKDF_SHA256   KEM = 0x0010
+	DHKEM_P384_HKDF_SHA384   KEM = 0x0011
+	DHKEM_P521_HKDF_SHA512   KEM = 0x0012
+	DHKEM_X25519_HKDF_SHA256 KEM = 0x0020
+)
+
+type dhKEM struct {
+	curve   ecdh.Curve
+	kdf     KDF
+	nSecret int // length of the KEM shared secret
+	nSK     int // length of an encoded private key
+
+	// bitmask is applied to the first byte of candidate scalars in
+	// DeriveKeyPair for the NIST curves. Zero for X25519.
+	bitmask byte
+}
+
+func (k KEM) params() (*dhKEM, error) {
+	switch k {
+	case DHKEM_P256_HKDF_SHA256:
+		return &dhKEM{ecdh.P256(), HKDF_SHA256, 32, 32, 0xff}, nil
+	case DHKEM_P384_HKDF_SHA384:
+		return &dhKEM{ecdh.P384(), HKDF_SHA384, 48, 48, 0xff}, nil
+	case DHKEM_P521_HKDF_SHA512:
+		return &dhKEM{ecdh.P521(), HKDF_SHA512, 64, 66, 0x01}, nil
+	case DHKEM_X25519_HKDF_SHA256:
+		return &dhKEM{ecdh.X25519(), HKDF_SHA256, 32, 32, 0}, nil
+	default:
+		return nil, errors.New("hpke: unsupported KEM")
+	}
+}
+
+// Curve returns the ecdh.Curve that the KEM operates on, or nil if the KEM
+// is not supported.
+func (k KEM) Curve() ecdh.Curve {
+	p, err := k.params()
+	if err != nil {
+		return nil
+	}
+	return p.curve
+}
+
+// GenerateKey generates a new random key pair for use with the KEM.
+func (k KEM) GenerateKey(rand io.Reader) (*ecdh.PrivateKey, error) {
+	p, err := k.params()
+	if err != nil {
+		return nil, err
+	}
+	return p.curve.GenerateKey(rand)
+}
+
+// DeriveKeyPair deterministically derives a key pair from the input keying
+// material ikm, as specified in RFC 9180, Section 7.1.3. ikm must be at least
+// as long as the private key.
+func (k KEM) DeriveKeyPair(ikm []byte) (*ecdh.PrivateKey, error) {
+	p, err := k.params()
+	if err != nil {
+		return nil, err
+	}
+	if len(ikm) < p.nSK {
+		return nil, errors.New("hpke: input keying material too short")
+	}
+	suiteID := k.suiteID()
+	prk := p.kdf.labeledExtract(suiteID, nil, "dkp_prk", ikm)
+	if p.curve == ecdh.X25519() {
+		sk := p.kdf.labeledExpand(suiteID, prk, "sk", nil, p.nSK)
+		return p.curve.NewPrivateKey(sk)
+	}
+	for counter := 0; counter < 256; counter++ {
+		candidate := p.kdf.labeledExpand(suiteID, prk, "candidate", []byte{byte(counter)}, p.nSK)
+		candidate[0] &= p.bitmask
+		if sk, err := p.curve.NewPrivateKey(candidate); err == nil {
+			return sk, nil
+		}
+	}
+	return nil, errors.New("hpke: failed to derive key pair")
+}
+
+func (k KEM) suiteID() []byte {
+	id := []byte("KEM\x00\x00")
+	binary.BigEndian.PutUint16(id[3:], uint16(k))
+	return id
+}
+
+// testingOnlyGenerateKey, if set, replaces the generation of the ephemeral
+// key in encap, so that tests can reproduce known answer vectors.
+var testingOnlyGenerateKey func() *ecdh.PrivateKey
+
+// encap implements Encap and AuthEncap from RFC 9180, Section 4.1. If skS is
+// nil, the base (unauthenticated) variant is used.
+func (p *dhKEM) encap(k KEM, pkR *ecdh.PublicKey, skS *ecdh.PrivateKey) (sharedSecret, enc []byte, err error) {
+	if pkR.Curve() != p.curve {
+		return nil, nil, errors.New("hpke: recipient public key does not match KEM")
+	}
+	if skS != nil && skS.Curve() != p.curve {
+		return nil, nil, errors.New("hpke: sender private key does not match KEM")
+	}
+	var skE *ecdh.PrivateKey
+	if testingOnlyGenerateKey != nil {
+		skE = testingOnlyGenerateKey()
+	} else {
+		skE, err = p.curve.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	dh, err := skE.ECDH(pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc = skE.PublicKey().Bytes()
+	kemContext := append(append([]byte(nil), enc...), pkR.Bytes()...)
+	if skS != nil {
+		dhS, err := skS.ECDH(pkR)
+		if err != nil {
+			return nil, nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, skS.PublicKey().Bytes()...)
+	}
+	return p.extractAndExpand(k, dh, kemContext), enc, nil
+}
+
+// decap implements Decap and AuthDecap from RFC 9180, Section 4.1. If pkS is
+// nil, the base (unauthenticated) variant is used.
+func (p *dhKEM) decap(k KEM, enc []byte, skR *ecdh.PrivateKey, pkS *ecdh.PublicKey) ([]byte, error) {
+	if skR.Curve() != p.curve {
+		return nil, errors.New("hpke: recipient private key does not match KEM")
+	}
+	if pkS != nil && pkS.Curve() != p.curve {
+		return nil, errors.New("hpke: sender public key does not match KEM")
+	}
+	pkE, err := p.curve.NewPublicKey(enc)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := skR.ECDH(pkE)
+	if err != nil {
+		return nil, err
+	}
+	kemContext := append(append([]byte(nil), enc...), skR.PublicKey().Bytes()...)
+	if pkS != nil {
+		dhS, err := skR.ECDH(pkS)
+		if err != nil {
+			return nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, pkS.Bytes()...)
+	}
+	return p.extractAndExpand(k, dh, kemContext), nil
+}
+
+func (p *dhKEM) extractAndExpand(k KEM, dh, kemContext []byte) []byte {
+	suiteID := k.suiteID()
+	eaePRK := p.kdf.labeledExtract(suiteID, nil, "eae_prk", dh)
+	return p.kdf.labeledExpand(suiteID, eaePRK, "shared_secret", kemContext, p.nSecret)
+}
@@ -0,0 +1,16 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package nistec implements the NIST P-384 and P-521 elliptic curves from
+// FIPS 186-4. P-224 and P-256 have constant-time implementations in
+// crypto/elliptic.
+//
+// This package uses fiat-crypto for its backend field arithmetic (not
+// math/big) and exposes constant-time, heap allocation-free, byte
+// slice-based safe APIs. Group operations use modern and safe complete
+// addition formulas. The point at infinity is handled and encoded according
+// to SEC 1, Version 2.0, and invalid curve points can't be represented.
+package nistec
+
+//go:generate go run generate.go
@@ -0,0 +1,237 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nistec_test
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/internal/nistec"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// curve gives access to the operations of one of the point types, using
+// the uncompressed encoding of the points.
+type curve struct {
+	params         elliptic.Curve
+	generator      func() []byte
+	add            func(p, q []byte) []byte
+	double         func(p []byte) []byte
+	scalarMult     func(p, scalar []byte) []byte
+	scalarBaseMult func(scalar []byte) []byte
+}
+
+func p384(t testing.TB) *curve {
+	point := func(b []byte) *nistec.P384Point {
+		p, err := nistec.NewP384Point().SetBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	return &curve{
+		params:    elliptic.P384(),
+		generator: func() []byte { return nistec.NewP384Point().SetGenerator().Bytes() },
+		add:       func(p, q []byte) []byte { return nistec.NewP384Point().Add(point(p), point(q)).Bytes() },
+		double:    func(p []byte) []byte { return nistec.NewP384Point().Double(point(p)).Bytes() },
+		scalarMult: func(p, scalar []byte) []byte {
+			r, err := nistec.NewP384Point().ScalarMult(point(p), scalar)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r.Bytes()
+		},
+		scalarBaseMult: func(scalar []byte) []byte {
+			r, err := nistec.NewP384Point().ScalarBaseMult(scalar)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r.Bytes()
+		},
+	}
+}
+
+func p521(t testing.TB) *curve {
+	point := func(b []byte) *nistec.P521Point {
+		p, err := nistec.NewP521Point().SetBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	return &curve{
+		params:    elliptic.P521(),
+		generator: func() []byte { return nistec.NewP521Point().SetGenerator().Bytes() },
+		add:       func(p, q []byte) []byte { return nistec.NewP521Point().Add(point(p), point(q)).Bytes() },
+		double:    func(p []byte) []byte { return nistec.NewP521Point().Double(point(p)).Bytes() },
+		scalarMult: func(p, scalar []byte) []byte {
+			r, err := nistec.NewP521Point().ScalarMult(point(p), scalar)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r.Bytes()
+		},
+		scalarBaseMult: func(scalar []byte) []byte {
+			r, err := nistec.NewP521Point().ScalarBaseMult(scalar)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r.Bytes()
+		},
+	}
+}
+
+func TestAllocations(t *testing.T) {
+	t.Run("P384", func(t *testing.T) {
+		if allocs := testing.AllocsPerRun(100, func() {
+			p := nistec.NewP384Point().SetGenerator()
+			scalar := make([]byte, 48)
+			rand.Read(scalar)
+			p.ScalarBaseMult(scalar)
+			p.ScalarMult(p, scalar)
+			out := p.Bytes()
+			if _, err := nistec.NewP384Point().SetBytes(out); err != nil {
+				t.Fatal(err)
+			}
+			out = p.BytesCompressed()
+			if _, err := p.SetBytes(out); err != nil {
+				t.Fatal(err)
+			}
+		}); allocs > 0 {
+			t.Errorf("expected zero allocations, got %0.1f", allocs)
+		}
+	})
+	t.Run("P521", func(t *testing.T) {
+		if allocs := testing.AllocsPerRun(100, func() {
+			p := nistec.NewP521Point().SetGenerator()
+			scalar := make([]byte, 66)
+			rand.Read(scalar)
+			p.ScalarBaseMult(scalar)
+			p.ScalarMult(p, scalar)
+			out := p.Bytes()
+			if _, err := nistec.NewP521Point().SetBytes(out); err != nil {
+				t.Fatal(err)
+			}
+			out = p.BytesCompressed()
+			if _, err := p.SetBytes(out); err != nil {
+				t.Fatal(err)
+			}
+		}); allocs > 0 {
+			t.Errorf("expected zero allocations, got %0.1f", allocs)
+		}
+	})
+}
+
+func TestEquivalents(t *testing.T) {
+	t.Run("P384", func(t *testing.T) { testEquivalents(t, p384(t)) })
+	t.Run("P521", func(t *testing.T) { testEquivalents(t, p521(t)) })
+}
+
+func testEquivalents(t *testing.T, c *curve) {
+	p := c.generator()
+
+	elementSize := (c.params.Params().BitSize + 7) / 8
+	two := make([]byte, elementSize)
+	two[len(two)-1] = 2
+	nPlusTwo := fillBytes(new(big.Int).Add(c.params.Params().N, big.NewInt(2)), elementSize)
+
+	p1 := c.double(p)
+	if p2 := c.add(p, p); !bytes.Equal(p1, p2) {
+		t.Error("P+P != 2*P")
+	}
+	if p3 := c.scalarMult(p, two); !bytes.Equal(p1, p3) {
+		t.Error("P+P != [2]P")
+	}
+	if p4 := c.scalarBaseMult(two); !bytes.Equal(p1, p4) {
+		t.Error("G+G != [2]G")
+	}
+	if p5 := c.scalarMult(p, nPlusTwo); !bytes.Equal(p1, p5) {
+		t.Error("P+P != [N+2]P")
+	}
+	if p6 := c.scalarBaseMult(nPlusTwo); !bytes.Equal(p1, p6) {
+		t.Error("G+G != [N+2]G")
+	}
+}
+
+// TestScalarMult compares the scalar multiplications with those of the
+// generic implementation in crypto/elliptic.
+func TestScalarMult(t *testing.T) {
+	t.Run("P384", func(t *testing.T) { testScalarMult(t, p384(t)) })
+	t.Run("P521", func(t *testing.T) { testScalarMult(t, p521(t)) })
+}
+
+func testScalarMult(t *testing.T, c *curve) {
+	params := c.params.Params()
+	elementSize := (params.BitSize + 7) / 8
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		k := fillBytes(new(big.Int).Rand(r, params.N), elementSize)
+		x, y := params.ScalarBaseMult(k)
+		want := elliptic.Marshal(params, x, y)
+		got := c.scalarBaseMult(k)
+		if !bytes.Equal(got, want) {
+			t.Errorf("[%x]G = %x, want %x", k, got, want)
+		}
+
+		k2 := fillBytes(new(big.Int).Rand(r, params.N), elementSize)
+		x, y = params.ScalarMult(x, y, k2)
+		want = elliptic.Marshal(params, x, y)
+		if got := c.scalarMult(got, k2); !bytes.Equal(got, want) {
+			t.Errorf("[%x][%x]G = %x, want %x", k2, k, got, want)
+		}
+	}
+}
+
+func fillBytes(x *big.Int, n int) []byte {
+	b := x.Bytes()
+	return append(make([]byte, n-len(b)), b...)
+}
+
+func BenchmarkScalarMult(b *testing.B) {
+	b.Run("P384", func(b *testing.B) {
+		p := nistec.NewP384Point().SetGenerator()
+		scalar := make([]byte, 48)
+		rand.Read(scalar)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.ScalarMult(p, scalar)
+		}
+	})
+	b.Run("P521", func(b *testing.B) {
+		p := nistec.NewP521Point().SetGenerator()
+		scalar := make([]byte, 66)
+		rand.Read(scalar)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.ScalarMult(p, scalar)
+		}
+	})
+}
+
+func BenchmarkScalarBaseMult(b *testing.B) {
+	b.Run("P384", func(b *testing.B) {
+		p := nistec.NewP384Point().SetGenerator()
+		scalar := make([]byte, 48)
+		rand.Read(scalar)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.ScalarBaseMult(scalar)
+		}
+	})
+	b.Run("P521", func(b *testing.B) {
+		p := nistec.NewP521Point().SetGenerator()
+		scalar := make([]byte, 66)
+		rand.Read(scalar)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.ScalarBaseMult(scalar)
+		}
+	})
+}
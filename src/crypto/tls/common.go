@@ -258,15 +258,88 @@ type ClientSessionState struct {
 	sessionTicket      []uint8               // Encrypted ticket used for session resumption with server
 	vers               uint16                // SSL/TLS version negotiated for the session
 	cipherSuite        uint16                // Ciphersuite negotiated for the session
-	masterSecret       []byte                // Full handshake MasterSecret, or TLS 1.3 resumption_master_secret
+	masterSecret       []byte                // Full handshake MasterSecret, or TLS 1.3 PSK
 	serverCertificates []*x509.Certificate   // Certificate chain presented by the server
 	verifiedChains     [][]*x509.Certificate // Certificate chains we built for verification
 	receivedAt         time.Time             // When the session ticket was received from the server
 
 	// TLS 1.3 fields.
-	nonce  []byte    // Ticket nonce sent by the server, to derive PSK
 	useBy  time.Time // Expiration of the ticket lifetime as set by the server
 	ageAdd uint32    // Random obfuscation factor for sending the ticket age
+	extra  []byte    // SessionState.Extra, carried across ResumptionState
+}
+
+// ResumptionState returns the session ticket sent by the server (also known as
+// the session's identity) and the state necessary to resume this session.
+//
+// It can be called by ClientSessionCache.Put to serialize (with
+// SessionState.Bytes) and store the session, or to inspect it.
+func (cs *ClientSessionState) ResumptionState() (ticket []byte, state *SessionState, err error) {
+	state = &SessionState{
+		Extra:       cs.extra,
+		version:     cs.vers,
+		isClient:    true,
+		cipherSuite: cs.cipherSuite,
+		createdAt:   uint64(cs.receivedAt.Unix()),
+		secret:      cs.masterSecret,
+	}
+	for _, cert := range cs.serverCertificates {
+		state.certificate.Certificate = append(state.certificate.Certificate, cert.Raw)
+	}
+	for _, chain := range cs.verifiedChains {
+		var rawChain [][]byte
+		for _, cert := range chain {
+			rawChain = append(rawChain, cert.Raw)
+		}
+		state.verifiedChains = append(state.verifiedChains, rawChain)
+	}
+	if cs.vers == VersionTLS13 {
+		state.useBy = uint64(cs.useBy.Unix())
+		state.ageAdd = cs.ageAdd
+	}
+	return cs.sessionTicket, state, nil
+}
+
+// NewResumptionState returns a state value that can be returned by
+// ClientSessionCache.Get to resume a previous session.
+//
+// state needs to be returned by ParseSessionState, and the ticket and session
+// state must have been returned by ClientSessionState.ResumptionState.
+func NewResumptionState(ticket []byte, state *SessionState) (*ClientSessionState, error) {
+	if !state.isClient {
+		return nil, errors.New("tls: session state was not created by a client")
+	}
+	cs := &ClientSessionState{
+		sessionTicket: ticket,
+		vers:          state.version,
+		cipherSuite:   state.cipherSuite,
+		masterSecret:  state.secret,
+		receivedAt:    time.Unix(int64(state.createdAt), 0),
+		extra:         state.Extra,
+	}
+	for _, raw := range state.certificate.Certificate {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return nil, err
+		}
+		cs.serverCertificates = append(cs.serverCertificates, cert)
+	}
+	for _, rawChain := range state.verifiedChains {
+		var chain []*x509.Certificate
+		for _, raw := range rawChain {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, cert)
+		}
+		cs.verifiedChains = append(cs.verifiedChains, chain)
+	}
+	if state.version == VersionTLS13 {
+		cs.useBy = time.Unix(int64(state.useBy), 0)
+		cs.ageAdd = state.ageAdd
+	}
+	return cs, nil
 }
 
 // ClientSessionCache is a cache of ClientSessionState objects that can be used
@@ -275,6 +348,10 @@ type ClientSessionState struct {
 // goroutines. Up to TLS 1.2, only ticket-based resumption is supported, not
 // SessionID-based resumption. In TLS 1.3 they were merged into PSK modes, which
 // are supported via this interface.
+//
+// Implementations that store sessions outside of the process can serialize
+// them with ClientSessionState.ResumptionState and SessionState.Bytes, and
+// restore them with ParseSessionState and NewResumptionState.
 type ClientSessionCache interface {
 	// Get searches for a ClientSessionState associated with the given key.
 	// On return, ok is true if one was found.
@@ -550,6 +627,39 @@ type Config struct {
 	// connections using that key might be compromised.
 	SessionTicketKey [32]byte
 
+	// UnwrapSession is called on the server to turn a ticket or PSK identity
+	// previously produced by WrapSession into a usable session.
+	//
+	// UnwrapSession will usually either decrypt a session state in the ticket
+	// (for example with DecryptTicket), or use the ticket as a handle to
+	// recover a previously stored state. It must use ParseSessionState to
+	// deserialize the session state.
+	//
+	// If UnwrapSession returns an error, the connection is terminated. If it
+	// returns (nil, nil), the session is ignored and a full handshake is
+	// performed, which allows vetoing the resumption of a session. If it
+	// is nil, DecryptTicket is used.
+	//
+	// UnwrapSession may be called multiple times for the same connection.
+	UnwrapSession func(identity []byte, cs ConnectionState) (*SessionState, error)
+
+	// WrapSession is called on the server to produce a session ticket or
+	// PSK identity.
+	//
+	// WrapSession must serialize the session state with SessionState.Bytes.
+	// It may then encrypt the serialized state (for example with
+	// EncryptTicket) and use it as the ticket, or store the state and return
+	// a handle for it. If it is nil, EncryptTicket is used.
+	//
+	// If WrapSession returns an error, the connection is terminated.
+	//
+	// Warning: the return value will be exposed on the wire and to clients
+	// in plaintext. The application is in charge of encrypting and
+	// authenticating it (and rotating keys) or returning high-entropy
+	// identifiers. Failing to do so correctly can compromise current,
+	// previous, and future connections depending on the protocol version.
+	WrapSession func(ConnectionState, *SessionState) ([]byte, error)
+
 	// ClientSessionCache is a cache of ClientSessionState entries for TLS
 	// session resumption. It is only used by clients.
 	ClientSessionCache ClientSessionCache
@@ -657,6 +767,8 @@ func (c *Config) Clone() *Config {
 		PreferServerCipherSuites:    c.PreferServerCipherSuites,
 		SessionTicketsDisabled:      c.SessionTicketsDisabled,
 		SessionTicketKey:            c.SessionTicketKey,
+		UnwrapSession:               c.UnwrapSession,
+		WrapSession:                 c.WrapSession,
 		ClientSessionCache:          c.ClientSessionCache,
 		MinVersion:                  c.MinVersion,
 		MaxVersion:                  c.MaxVersion,
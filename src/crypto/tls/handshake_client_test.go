@@ -993,6 +993,123 @@ func testResumption(t *testing.T, version uint16) {
 	testResumeState("WithoutSessionCache", false)
 }
 
+// serializingSessionCache is a ClientSessionCache that only keeps the
+// encoded form of the last session, like an external store would.
+type serializingSessionCache struct {
+	t      *testing.T
+	ticket []byte
+	state  []byte
+}
+
+func (c *serializingSessionCache) Get(sessionKey string) (*ClientSessionState, bool) {
+	if c.ticket == nil {
+		return nil, false
+	}
+	state, err := ParseSessionState(c.state)
+	if err != nil {
+		c.t.Errorf("ParseSessionState: %v", err)
+		return nil, false
+	}
+	session, err := NewResumptionState(c.ticket, state)
+	if err != nil {
+		c.t.Errorf("NewResumptionState: %v", err)
+		return nil, false
+	}
+	return session, true
+}
+
+func (c *serializingSessionCache) Put(sessionKey string, cs *ClientSessionState) {
+	if cs == nil {
+		c.ticket, c.state = nil, nil
+		return
+	}
+	ticket, state, err := cs.ResumptionState()
+	if err != nil {
+		c.t.Errorf("ResumptionState: %v", err)
+		return
+	}
+	state.Extra = []byte("client extra")
+	stateBytes, err := state.Bytes()
+	if err != nil {
+		c.t.Errorf("SessionState.Bytes: %v", err)
+		return
+	}
+	c.ticket, c.state = ticket, stateBytes
+}
+
+func TestSessionHooks(t *testing.T) {
+	t.Run("TLSv12", func(t *testing.T) { testSessionHooks(t, VersionTLS12) })
+	t.Run("TLSv13", func(t *testing.T) { testSessionHooks(t, VersionTLS13) })
+}
+
+func testSessionHooks(t *testing.T, version uint16) {
+	serverConfig := testConfig.Clone()
+	serverConfig.MaxVersion = version
+	var veto, fail bool
+	serverConfig.WrapSession = func(cs ConnectionState, ss *SessionState) ([]byte, error) {
+		if cs.Version != version {
+			t.Errorf("WrapSession: got version %x, expected %x", cs.Version, version)
+		}
+		ss.Extra = []byte("server extra")
+		return serverConfig.EncryptTicket(cs, ss)
+	}
+	serverConfig.UnwrapSession = func(identity []byte, cs ConnectionState) (*SessionState, error) {
+		if fail {
+			return nil, errors.New("unwrap failure")
+		}
+		ss, err := serverConfig.DecryptTicket(identity, cs)
+		if ss == nil || err != nil {
+			return ss, err
+		}
+		if string(ss.Extra) != "server extra" {
+			t.Errorf("UnwrapSession: got Extra %q", ss.Extra)
+		}
+		if veto {
+			return nil, nil
+		}
+		return ss, nil
+	}
+
+	cache := &serializingSessionCache{t: t}
+	clientConfig := testConfig.Clone()
+	clientConfig.MaxVersion = version
+	clientConfig.ClientSessionCache = cache
+
+	testResumeState := func(test string, didResume bool) {
+		t.Helper()
+		_, hs, err := testHandshake(t, clientConfig, serverConfig)
+		if err != nil {
+			t.Fatalf("%s: handshake failed: %s", test, err)
+		}
+		if hs.DidResume != didResume {
+			t.Fatalf("%s resumed: %v, expected: %v", test, hs.DidResume, didResume)
+		}
+	}
+
+	testResumeState("Handshake", false)
+	if cache.ticket == nil {
+		t.Fatal("no session was stored in the cache")
+	}
+	state, err := ParseSessionState(cache.state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(state.Extra) != "client extra" {
+		t.Errorf("got client Extra %q", state.Extra)
+	}
+	testResumeState("Resume", true)
+
+	veto = true
+	testResumeState("Veto", false)
+	veto = false
+	testResumeState("ResumeAfterVeto", true)
+
+	fail = true
+	if _, _, err := testHandshake(t, clientConfig, serverConfig); err == nil {
+		t.Error("handshake succeeded despite an UnwrapSession error")
+	}
+}
+
 func TestLRUClientSessionCache(t *testing.T) {
 	// Initialize cache of capacity 4.
 	cache := NewLRUClientSessionCache(4)
@@ -28,8 +28,6 @@ var tests = []interface{}{
 	&clientKeyExchangeMsg{},
 	&nextProtoMsg{},
 	&newSessionTicketMsg{},
-	&sessionState{},
-	&sessionStateTLS13{},
 	&encryptedExtensionsMsg{},
 	&endOfEarlyDataMsg{},
 	&keyUpdateMsg{},
@@ -320,40 +318,80 @@ func (*newSessionTicketMsg) Generate(rand *rand.Rand, size int) reflect.Value {
 	return reflect.ValueOf(m)
 }
 
-func (*sessionState) Generate(rand *rand.Rand, size int) reflect.Value {
-	s := &sessionState{}
-	s.vers = uint16(rand.Intn(10000))
-	s.cipherSuite = uint16(rand.Intn(10000))
-	s.masterSecret = randomBytes(rand.Intn(100), rand)
-	numCerts := rand.Intn(20)
-	s.certificates = make([][]byte, numCerts)
-	for i := 0; i < numCerts; i++ {
-		s.certificates[i] = randomBytes(rand.Intn(10)+1, rand)
+func (*SessionState) Generate(rand *rand.Rand, size int) reflect.Value {
+	s := &SessionState{}
+	s.version = uint16(rand.Intn(10000))
+	if rand.Intn(10) > 5 {
+		s.version = VersionTLS13
 	}
-	return reflect.ValueOf(s)
-}
-
-func (*sessionStateTLS13) Generate(rand *rand.Rand, size int) reflect.Value {
-	s := &sessionStateTLS13{}
+	s.isClient = rand.Intn(10) > 5
 	s.cipherSuite = uint16(rand.Intn(10000))
-	s.resumptionSecret = randomBytes(rand.Intn(100)+1, rand)
 	s.createdAt = uint64(rand.Int63())
-	for i := 0; i < rand.Intn(2)+1; i++ {
+	s.secret = randomBytes(rand.Intn(100)+1, rand)
+	if rand.Intn(10) > 5 {
+		s.Extra = randomBytes(rand.Intn(100)+1, rand)
+	}
+	for i := 0; i < rand.Intn(20); i++ {
 		s.certificate.Certificate = append(
 			s.certificate.Certificate, randomBytes(rand.Intn(500)+1, rand))
 	}
-	if rand.Intn(10) > 5 {
+	if rand.Intn(10) > 5 && len(s.certificate.Certificate) > 0 {
 		s.certificate.OCSPStaple = randomBytes(rand.Intn(100)+1, rand)
 	}
-	if rand.Intn(10) > 5 {
+	if rand.Intn(10) > 5 && len(s.certificate.Certificate) > 0 {
 		for i := 0; i < rand.Intn(2)+1; i++ {
 			s.certificate.SignedCertificateTimestamps = append(
 				s.certificate.SignedCertificateTimestamps, randomBytes(rand.Intn(500)+1, rand))
 		}
 	}
+	if s.isClient {
+		for i := 0; i < rand.Intn(3); i++ {
+			var chain [][]byte
+			for j := 0; j < rand.Intn(3)+1; j++ {
+				chain = append(chain, randomBytes(rand.Intn(500)+1, rand))
+			}
+			s.verifiedChains = append(s.verifiedChains, chain)
+		}
+		if s.version == VersionTLS13 {
+			s.useBy = uint64(rand.Int63())
+			s.ageAdd = uint32(rand.Int63())
+		}
+	}
 	return reflect.ValueOf(s)
 }
 
+func TestSessionStateEncoding(t *testing.T) {
+	rand := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	n := 100
+	if testing.Short() {
+		n = 5
+	}
+	for i := 0; i < n; i++ {
+		v, ok := quick.Value(reflect.TypeOf(&SessionState{}), rand)
+		if !ok {
+			t.Fatal("failed to create value")
+		}
+		s1 := v.Interface().(*SessionState)
+		encoded, err := s1.Bytes()
+		if err != nil {
+			t.Fatalf("#%d: failed to encode %#v: %v", i, s1, err)
+		}
+		s2, err := ParseSessionState(encoded)
+		if err != nil {
+			t.Fatalf("#%d: failed to parse %#v %x: %v", i, s1, encoded, err)
+		}
+		if !reflect.DeepEqual(s1, s2) {
+			t.Fatalf("#%d got:%#v want:%#v %x", i, s2, s1, encoded)
+		}
+		for j := 0; j < len(encoded); j++ {
+			if _, err := ParseSessionState(encoded[:j]); err == nil {
+				t.Fatalf("#%d parsed a prefix of length %d of %#v", i, j, s1)
+			}
+		}
+	}
+}
+
 func (*endOfEarlyDataMsg) Generate(rand *rand.Rand, size int) reflect.Value {
 	m := &endOfEarlyDataMsg{}
 	return reflect.ValueOf(m)
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"sync/atomic"
+	"time"
 )
 
 // serverHandshakeState contains details of a server handshake in progress.
@@ -27,7 +28,7 @@ type serverHandshakeState struct {
 	ecdsaOk      bool
 	rsaDecryptOk bool
 	rsaSignOk    bool
-	sessionState *sessionState
+	sessionState *SessionState
 	finishedHash finishedHash
 	masterSecret []byte
 	cert         *Certificate
@@ -68,7 +69,11 @@ func (hs *serverHandshakeState) handshake() error {
 
 	// For an overview of TLS handshaking, see RFC 5246, Section 7.3.
 	c.buffering = true
-	if hs.checkForResumption() {
+	resume, err := hs.checkForResumption()
+	if err != nil {
+		return err
+	}
+	if resume {
 		// The client has included a session ticket and so we do an abbreviated handshake.
 		if err := hs.doResumeHandshake(); err != nil {
 			return err
@@ -325,55 +330,60 @@ func (hs *serverHandshakeState) pickCipherSuite() error {
 }
 
 // checkForResumption reports whether we should perform resumption on this connection.
-func (hs *serverHandshakeState) checkForResumption() bool {
+func (hs *serverHandshakeState) checkForResumption() (bool, error) {
 	c := hs.c
 
-	if c.config.SessionTicketsDisabled {
-		return false
+	if c.config.SessionTicketsDisabled || len(hs.clientHello.sessionTicket) == 0 {
+		return false, nil
 	}
 
-	plaintext, usedOldKey := c.decryptTicket(hs.clientHello.sessionTicket)
-	if plaintext == nil {
-		return false
+	sessionState, err := c.unwrapSession(hs.clientHello.sessionTicket)
+	if err != nil {
+		c.sendAlert(alertInternalError)
+		return false, err
 	}
-	hs.sessionState = &sessionState{usedOldKey: usedOldKey}
-	ok := hs.sessionState.unmarshal(plaintext)
-	if !ok {
-		return false
+	if sessionState == nil || sessionState.isClient {
+		return false, nil
 	}
 
 	// Never resume a session for a different TLS version.
-	if c.vers != hs.sessionState.vers {
-		return false
+	if c.vers != sessionState.version {
+		return false, nil
+	}
+
+	createdAt := time.Unix(int64(sessionState.createdAt), 0)
+	if c.config.time().Sub(createdAt) > maxSessionTicketLifetime {
+		return false, nil
 	}
 
 	cipherSuiteOk := false
 	// Check that the client is still offering the ciphersuite in the session.
 	for _, id := range hs.clientHello.cipherSuites {
-		if id == hs.sessionState.cipherSuite {
+		if id == sessionState.cipherSuite {
 			cipherSuiteOk = true
 			break
 		}
 	}
 	if !cipherSuiteOk {
-		return false
+		return false, nil
 	}
 
 	// Check that we also support the ciphersuite from the session.
-	if !hs.setCipherSuite(hs.sessionState.cipherSuite, c.config.cipherSuites(), hs.sessionState.vers) {
-		return false
+	if !hs.setCipherSuite(sessionState.cipherSuite, c.config.cipherSuites(), sessionState.version) {
+		return false, nil
 	}
 
-	sessionHasClientCerts := len(hs.sessionState.certificates) != 0
+	sessionHasClientCerts := len(sessionState.certificate.Certificate) != 0
 	needClientCerts := requiresClientCert(c.config.ClientAuth)
 	if needClientCerts && !sessionHasClientCerts {
-		return false
+		return false, nil
 	}
 	if sessionHasClientCerts && c.config.ClientAuth == NoClientCert {
-		return false
+		return false, nil
 	}
 
-	return true
+	hs.sessionState = sessionState
+	return true, nil
 }
 
 func (hs *serverHandshakeState) doResumeHandshake() error {
@@ -393,12 +403,12 @@ func (hs *serverHandshakeState) doResumeHandshake() error {
 	}
 
 	if err := c.processCertsFromClient(Certificate{
-		Certificate: hs.sessionState.certificates,
+		Certificate: hs.sessionState.certificate.Certificate,
 	}); err != nil {
 		return err
 	}
 
-	hs.masterSecret = hs.sessionState.masterSecret
+	hs.masterSecret = hs.sessionState.secret
 
 	return nil
 }
@@ -658,18 +668,16 @@ func (hs *serverHandshakeState) sendSessionTicket() error {
 	c := hs.c
 	m := new(newSessionTicketMsg)
 
-	var certsFromClient [][]byte
-	for _, cert := range c.peerCertificates {
-		certsFromClient = append(certsFromClient, cert.Raw)
-	}
-	state := sessionState{
-		vers:         c.vers,
-		cipherSuite:  hs.suite.id,
-		masterSecret: hs.masterSecret,
-		certificates: certsFromClient,
+	state := c.sessionState()
+	state.cipherSuite = hs.suite.id
+	state.secret = hs.masterSecret
+	if hs.sessionState != nil {
+		// If this is re-wrapping an old key, then keep
+		// the original time it was created.
+		state.createdAt = hs.sessionState.createdAt
 	}
 	var err error
-	m.ticket, err = c.encryptTicket(state.marshal())
+	m.ticket, err = c.wrapSession(state)
 	if err != nil {
 		return err
 	}
@@ -16,147 +16,261 @@ import (
 	"io"
 )
 
-// sessionState contains the information that is serialized into a session
-// ticket in order to later resume a connection.
-type sessionState struct {
-	vers         uint16
-	cipherSuite  uint16
-	masterSecret []byte
-	certificates [][]byte
+// A SessionState is a resumable session. It is produced by a server when it
+// issues a session ticket, and by a client when it receives one.
+//
+// Servers can use it through Config.WrapSession and Config.UnwrapSession to
+// store tickets externally or to attach application data to a session.
+// Clients can obtain it from ClientSessionState.ResumptionState in order to
+// persist the contents of a ClientSessionCache.
+type SessionState struct {
+	// Encoded as a SessionState (in the language of RFC 8446, Section 3).
+	//
+	//   enum { server(1), client(2) } SessionStateType;
+	//
+	//   opaque Certificate<1..2^24-1>;
+	//
+	//   Certificate CertificateChain<0..2^24-1>;
+	//
+	//   struct {
+	//       uint16 version;
+	//       SessionStateType type;
+	//       uint16 cipher_suite;
+	//       uint64 created_at;
+	//       opaque secret<1..2^8-1>;
+	//       opaque extra<0..2^24-1>;
+	//       CertificateEntry certificate_list<0..2^24-1>;
+	//       select (SessionState.type) {
+	//           case server: Empty;
+	//           case client: struct {
+	//               CertificateChain verified_chains<0..2^24-1>;
+	//               select (SessionState.version) {
+	//                   case VersionTLS10..VersionTLS12: Empty;
+	//                   case VersionTLS13: struct {
+	//                       uint64 use_by;
+	//                       uint32 age_add;
+	//                   };
+	//               };
+	//           };
+	//       };
+	//   } SessionState;
+	//
+
+	// Extra is ignored by crypto/tls, but is encoded by Bytes and parsed by
+	// ParseSessionState. This allows WrapSession, UnwrapSession and
+	// ClientSessionCache implementations to store and retrieve additional
+	// data with the session.
+	Extra []byte
+
+	version     uint16
+	isClient    bool
+	cipherSuite uint16
+	// createdAt is the generation time of the secret on the server (which for
+	// TLS 1.2 might be earlier than the current session) and the time at which
+	// the ticket was received on the client.
+	createdAt uint64 // seconds since UNIX epoch
+	// secret is the TLS 1.2 master secret, the TLS 1.3 resumption master
+	// secret on the server, or the TLS 1.3 PSK on the client.
+	secret      []byte
+	certificate Certificate // CertificateEntry certificate_list<0..2^24-1>;
+
+	// Client-side fields.
+	verifiedChains [][][]byte
+	useBy          uint64 // seconds since UNIX epoch, TLS 1.3 only
+	ageAdd         uint32 // TLS 1.3 only
+
 	// usedOldKey is true if the ticket from which this session came from
-	// was encrypted with an older key and thus should be refreshed.
+	// was encrypted with an older key and thus should be refreshed. It is
+	// not encoded.
 	usedOldKey bool
 }
 
-func (s *sessionState) marshal() []byte {
-	length := 2 + 2 + 2 + len(s.masterSecret) + 2
-	for _, cert := range s.certificates {
-		length += 4 + len(cert)
-	}
-
-	ret := make([]byte, length)
-	x := ret
-	x[0] = byte(s.vers >> 8)
-	x[1] = byte(s.vers)
-	x[2] = byte(s.cipherSuite >> 8)
-	x[3] = byte(s.cipherSuite)
-	x[4] = byte(len(s.masterSecret) >> 8)
-	x[5] = byte(len(s.masterSecret))
-	x = x[6:]
-	copy(x, s.masterSecret)
-	x = x[len(s.masterSecret):]
-
-	x[0] = byte(len(s.certificates) >> 8)
-	x[1] = byte(len(s.certificates))
-	x = x[2:]
-
-	for _, cert := range s.certificates {
-		x[0] = byte(len(cert) >> 24)
-		x[1] = byte(len(cert) >> 16)
-		x[2] = byte(len(cert) >> 8)
-		x[3] = byte(len(cert))
-		copy(x[4:], cert)
-		x = x[4+len(cert):]
-	}
-
-	return ret
-}
+const (
+	sessionStateTypeServer = 1
+	sessionStateTypeClient = 2
+)
 
-func (s *sessionState) unmarshal(data []byte) bool {
-	if len(data) < 8 {
-		return false
+// Bytes encodes the session, including any private fields, so that it can be
+// parsed by ParseSessionState. The encoding contains secret values critical
+// to the security of future and possibly past sessions.
+//
+// The specific encoding should be considered opaque and may change
+// incompatibly between Go versions.
+func (s *SessionState) Bytes() ([]byte, error) {
+	var b cryptobyte.Builder
+	b.AddUint16(s.version)
+	if s.isClient {
+		b.AddUint8(sessionStateTypeClient)
+	} else {
+		b.AddUint8(sessionStateTypeServer)
 	}
-
-	s.vers = uint16(data[0])<<8 | uint16(data[1])
-	s.cipherSuite = uint16(data[2])<<8 | uint16(data[3])
-	masterSecretLen := int(data[4])<<8 | int(data[5])
-	data = data[6:]
-	if len(data) < masterSecretLen {
-		return false
+	b.AddUint16(s.cipherSuite)
+	addUint64(&b, s.createdAt)
+	b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(s.secret)
+	})
+	b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(s.Extra)
+	})
+	marshalCertificate(&b, s.certificate)
+	if s.isClient {
+		b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+			for _, chain := range s.verifiedChains {
+				b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+					for _, cert := range chain {
+						b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+							b.AddBytes(cert)
+						})
+					}
+				})
+			}
+		})
+		if s.version == VersionTLS13 {
+			addUint64(&b, s.useBy)
+			b.AddUint32(s.ageAdd)
+		}
 	}
+	return b.Bytes()
+}
 
-	s.masterSecret = data[:masterSecretLen]
-	data = data[masterSecretLen:]
-
-	if len(data) < 2 {
-		return false
+// ParseSessionState parses a SessionState encoded by SessionState.Bytes.
+func ParseSessionState(data []byte) (*SessionState, error) {
+	ss := &SessionState{}
+	s := cryptobyte.String(data)
+	var typ uint8
+	var extra []byte
+	if !s.ReadUint16(&ss.version) ||
+		!s.ReadUint8(&typ) ||
+		(typ != sessionStateTypeServer && typ != sessionStateTypeClient) ||
+		!s.ReadUint16(&ss.cipherSuite) ||
+		!readUint64(&s, &ss.createdAt) ||
+		!readUint8LengthPrefixed(&s, &ss.secret) ||
+		len(ss.secret) == 0 ||
+		!readUint24LengthPrefixed(&s, &extra) ||
+		!unmarshalCertificate(&s, &ss.certificate) {
+		return nil, errors.New("tls: invalid session encoding")
 	}
-
-	numCerts := int(data[0])<<8 | int(data[1])
-	data = data[2:]
-
-	s.certificates = make([][]byte, numCerts)
-	for i := range s.certificates {
-		if len(data) < 4 {
-			return false
+	if len(extra) != 0 {
+		ss.Extra = extra
+	}
+	if typ == sessionStateTypeServer {
+		if !s.Empty() {
+			return nil, errors.New("tls: invalid session encoding")
+		}
+		return ss, nil
+	}
+	ss.isClient = true
+	var chainList cryptobyte.String
+	if !s.ReadUint24LengthPrefixed(&chainList) {
+		return nil, errors.New("tls: invalid session encoding")
+	}
+	for !chainList.Empty() {
+		var certList cryptobyte.String
+		if !chainList.ReadUint24LengthPrefixed(&certList) {
+			return nil, errors.New("tls: invalid session encoding")
 		}
-		certLen := int(data[0])<<24 | int(data[1])<<16 | int(data[2])<<8 | int(data[3])
-		data = data[4:]
-		if certLen < 0 {
-			return false
+		var chain [][]byte
+		for !certList.Empty() {
+			var cert []byte
+			if !readUint24LengthPrefixed(&certList, &cert) || len(cert) == 0 {
+				return nil, errors.New("tls: invalid session encoding")
+			}
+			chain = append(chain, cert)
 		}
-		if len(data) < certLen {
-			return false
+		ss.verifiedChains = append(ss.verifiedChains, chain)
+	}
+	if ss.version == VersionTLS13 {
+		if !readUint64(&s, &ss.useBy) || !s.ReadUint32(&ss.ageAdd) {
+			return nil, errors.New("tls: invalid session encoding")
 		}
-		s.certificates[i] = data[:certLen]
-		data = data[certLen:]
 	}
+	if !s.Empty() {
+		return nil, errors.New("tls: invalid session encoding")
+	}
+	return ss, nil
+}
 
-	return len(data) == 0
+// sessionState returns a partially filled-out SessionState with information
+// from the current connection.
+func (c *Conn) sessionState() *SessionState {
+	var certs [][]byte
+	for _, cert := range c.peerCertificates {
+		certs = append(certs, cert.Raw)
+	}
+	return &SessionState{
+		version:     c.vers,
+		isClient:    c.isClient,
+		cipherSuite: c.cipherSuite,
+		createdAt:   uint64(c.config.time().Unix()),
+		certificate: Certificate{Certificate: certs},
+	}
 }
 
-// sessionStateTLS13 is the content of a TLS 1.3 session ticket. Its first
-// version (revision = 0) doesn't carry any of the information needed for 0-RTT
-// validation and the nonce is always empty.
-type sessionStateTLS13 struct {
-	// uint8 version  = 0x0304;
-	// uint8 revision = 0;
-	cipherSuite      uint16
-	createdAt        uint64
-	resumptionSecret []byte      // opaque resumption_master_secret<1..2^8-1>;
-	certificate      Certificate // CertificateEntry certificate_list<0..2^24-1>;
+// EncryptTicket encrypts a ticket with the Config's configured (or default)
+// session ticket keys. It can be used as a WrapSession implementation, for
+// example to add data to SessionState.Extra before encrypting it.
+func (c *Config) EncryptTicket(cs ConnectionState, ss *SessionState) ([]byte, error) {
+	c.serverInitOnce.Do(func() { c.serverInit(nil) })
+	stateBytes, err := ss.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return c.encryptTicket(stateBytes)
 }
 
-func (m *sessionStateTLS13) marshal() []byte {
-	var b cryptobyte.Builder
-	b.AddUint16(VersionTLS13)
-	b.AddUint8(0) // revision
-	b.AddUint16(m.cipherSuite)
-	addUint64(&b, m.createdAt)
-	b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
-		b.AddBytes(m.resumptionSecret)
-	})
-	marshalCertificate(&b, m.certificate)
-	return b.BytesOrPanic()
+// DecryptTicket decrypts a ticket encrypted by EncryptTicket. It can be used
+// as an UnwrapSession implementation, for example to inspect
+// SessionState.Extra and veto the resumption by returning nil.
+//
+// If the ticket can't be decrypted or parsed, DecryptTicket returns (nil, nil).
+func (c *Config) DecryptTicket(identity []byte, cs ConnectionState) (*SessionState, error) {
+	c.serverInitOnce.Do(func() { c.serverInit(nil) })
+	stateBytes, usedOldKey := c.decryptTicket(identity)
+	if stateBytes == nil {
+		return nil, nil
+	}
+	s, err := ParseSessionState(stateBytes)
+	if err != nil {
+		return nil, nil
+	}
+	s.usedOldKey = usedOldKey
+	return s, nil
 }
 
-func (m *sessionStateTLS13) unmarshal(data []byte) bool {
-	*m = sessionStateTLS13{}
-	s := cryptobyte.String(data)
-	var version uint16
-	var revision uint8
-	return s.ReadUint16(&version) &&
-		version == VersionTLS13 &&
-		s.ReadUint8(&revision) &&
-		revision == 0 &&
-		s.ReadUint16(&m.cipherSuite) &&
-		readUint64(&s, &m.createdAt) &&
-		readUint8LengthPrefixed(&s, &m.resumptionSecret) &&
-		len(m.resumptionSecret) != 0 &&
-		unmarshalCertificate(&s, &m.certificate) &&
-		s.Empty()
+// wrapSession turns a session into a ticket to be sent to the client, using
+// Config.WrapSession if set.
+func (c *Conn) wrapSession(ss *SessionState) ([]byte, error) {
+	if c.config.WrapSession != nil {
+		return c.config.WrapSession(c.connectionStateLocked(), ss)
+	}
+	return c.config.EncryptTicket(c.connectionStateLocked(), ss)
 }
 
-func (c *Conn) encryptTicket(state []byte) ([]byte, error) {
+// unwrapSession turns a ticket received from the client into a session,
+// using Config.UnwrapSession if set. A nil SessionState and nil error mean
+// that the ticket must not be used for resumption.
+func (c *Conn) unwrapSession(identity []byte) (*SessionState, error) {
+	if c.config.UnwrapSession != nil {
+		return c.config.UnwrapSession(identity, c.connectionStateLocked())
+	}
+	return c.config.DecryptTicket(identity, c.connectionStateLocked())
+}
+
+func (c *Config) encryptTicket(state []byte) ([]byte, error) {
+	keys := c.ticketKeys()
+	if len(keys) == 0 {
+		return nil, errors.New("tls: internal error: session ticket keys unavailable")
+	}
+
 	encrypted := make([]byte, ticketKeyNameLen+aes.BlockSize+len(state)+sha256.Size)
 	keyName := encrypted[:ticketKeyNameLen]
 	iv := encrypted[ticketKeyNameLen : ticketKeyNameLen+aes.BlockSize]
 	macBytes := encrypted[len(encrypted)-sha256.Size:]
 
-	if _, err := io.ReadFull(c.config.rand(), iv); err != nil {
+	if _, err := io.ReadFull(c.rand(), iv); err != nil {
 		return nil, err
 	}
-	key := c.config.ticketKeys()[0]
+	key := keys[0]
 	copy(keyName, key.keyName[:])
 	block, err := aes.NewCipher(key.aesKey[:])
 	if err != nil {
@@ -171,7 +285,7 @@ func (c *Conn) encryptTicket(state []byte) ([]byte, error) {
 	return encrypted, nil
 }
 
-func (c *Conn) decryptTicket(encrypted []byte) (plaintext []byte, usedOldKey bool) {
+func (c *Config) decryptTicket(encrypted []byte) (plaintext []byte, usedOldKey bool) {
 	if len(encrypted) < ticketKeyNameLen+aes.BlockSize+sha256.Size {
 		return nil, false
 	}
@@ -181,7 +295,7 @@ func (c *Conn) decryptTicket(encrypted []byte) (plaintext []byte, usedOldKey boo
 	macBytes := encrypted[len(encrypted)-sha256.Size:]
 	ciphertext := encrypted[ticketKeyNameLen+aes.BlockSize : len(encrypted)-sha256.Size]
 
-	keys := c.config.ticketKeys()
+	keys := c.ticketKeys()
 	keyIndex := -1
 	for i, candidateKey := range keys {
 		if bytes.Equal(keyName, candidateKey.keyName[:]) {
@@ -8,6 +8,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"runtime"
+	"time"
 )
 
 // CertPool is a set of certificates.
@@ -15,6 +16,36 @@ type CertPool struct {
 	bySubjectKeyId map[string][]int
 	byName         map[string][]int
 	certs          []*Certificate
+
+	// constraints holds the RootConstraints of certs[i], or nil if the
+	// certificate was added without constraints.
+	constraints []*RootConstraints
+}
+
+// RootConstraints restricts what a root certificate in a CertPool can be
+// used to verify. They are enforced by Certificate.Verify, in addition to
+// any constraints expressed in the certificates themselves, for every chain
+// that terminates at the constrained root.
+type RootConstraints struct {
+	// DNSDomains, if not empty, limits the root to leaf certificates for
+	// names within these domains. A domain matches itself and all of its
+	// subdomains. Every DNS name asserted by the leaf, as well as
+	// VerifyOptions.DNSName if set, must match one of the domains. Leaf
+	// certificates that assert no DNS names, or that assert IP addresses,
+	// are rejected.
+	DNSDomains []string
+
+	// ExtKeyUsages, if not empty, limits the root to these extended key
+	// usages. Every usage requested in VerifyOptions.KeyUsages must be in
+	// the list. If ExtKeyUsageAny is requested, every usage asserted by the
+	// leaf must be in the list instead, and a leaf that asserts no extended
+	// key usages is only accepted if the list includes ExtKeyUsageAny.
+	ExtKeyUsages []ExtKeyUsage
+
+	// NotAfter, if not zero, is the time after which the root is no longer
+	// trusted. It is compared against VerifyOptions.CurrentTime, or the
+	// current time if that is zero.
+	NotAfter time.Time
 }
 
 // NewCertPool returns a new, empty CertPool.
@@ -30,6 +61,7 @@ func (s *CertPool) copy() *CertPool {
 		bySubjectKeyId: make(map[string][]int, len(s.bySubjectKeyId)),
 		byName:         make(map[string][]int, len(s.byName)),
 		certs:          make([]*Certificate, len(s.certs)),
+		constraints:    make([]*RootConstraints, len(s.constraints)),
 	}
 	for k, v := range s.bySubjectKeyId {
 		indexes := make([]int, len(v))
@@ -42,11 +74,18 @@ func (s *CertPool) copy() *CertPool {
 		p.byName[k] = indexes
 	}
 	copy(p.certs, s.certs)
+	copy(p.constraints, s.constraints)
 	return p
 }
 
 // SystemCertPool returns a copy of the system cert pool.
 //
+// On Unix systems other than macOS, the SSL_CERT_FILE environment variable
+// can be set to the path of a PEM bundle to load instead of the default
+// system bundle, and SSL_CERT_DIR can be set to a directory of PEM files to
+// load instead of the default system directories. The variables are only
+// consulted the first time the system pool is loaded.
+//
 // Any mutations to the returned pool are not written to disk and do
 // not affect any other pool returned by SystemCertPool.
 //
@@ -83,33 +122,68 @@ func (s *CertPool) findPotentialParents(cert *Certificate) []int {
 }
 
 func (s *CertPool) contains(cert *Certificate) bool {
+	return s.indexOf(cert) >= 0
+}
+
+// indexOf returns the index of cert in s.certs, or -1 if s doesn't
+// contain cert.
+func (s *CertPool) indexOf(cert *Certificate) int {
 	if s == nil {
-		return false
+		return -1
 	}
 
 	candidates := s.byName[string(cert.RawSubject)]
 	for _, c := range candidates {
 		if s.certs[c].Equal(cert) {
-			return true
+			return c
 		}
 	}
 
-	return false
+	return -1
+}
+
+// rootConstraints returns the constraints attached to cert, or nil if s
+// doesn't contain cert or cert is unconstrained.
+func (s *CertPool) rootConstraints(cert *Certificate) *RootConstraints {
+	if i := s.indexOf(cert); i >= 0 && i < len(s.constraints) {
+		return s.constraints[i]
+	}
+	return nil
 }
 
 // AddCert adds a certificate to a pool.
 func (s *CertPool) AddCert(cert *Certificate) {
+	s.addCert(cert, nil)
+}
+
+// AddCertWithConstraints adds a certificate to a pool, restricting its use as
+// a root according to constraints. If the certificate is already in the pool,
+// its constraints are replaced.
+//
+// The constraints only apply when the pool is used as VerifyOptions.Roots.
+func (s *CertPool) AddCertWithConstraints(cert *Certificate, constraints RootConstraints) {
+	c := constraints
+	c.DNSDomains = append([]string(nil), constraints.DNSDomains...)
+	c.ExtKeyUsages = append([]ExtKeyUsage(nil), constraints.ExtKeyUsages...)
+	s.addCert(cert, &c)
+}
+
+func (s *CertPool) addCert(cert *Certificate, constraints *RootConstraints) {
 	if cert == nil {
 		panic("adding nil Certificate to CertPool")
 	}
 
 	// Check that the certificate isn't being added twice.
-	if s.contains(cert) {
+	if i := s.indexOf(cert); i >= 0 {
+		if constraints != nil {
+			s.setConstraints(i, constraints)
+		}
 		return
 	}
 
 	n := len(s.certs)
 	s.certs = append(s.certs, cert)
+	s.setConstraints(n, constraints)
 
 	if len(cert.SubjectKeyId) > 0 {
 		keyId := string(cert.SubjectKeyId)
@@ -119,6 +193,49 @@ func (s *CertPool) AddCert(cert *Certificate) {
 	s.byName[name] = append(s.byName[name], n)
 }
 
+func (s *CertPool) setConstraints(i int, constraints *RootConstraints) {
+	for len(s.constraints) <= i {
+		s.constraints = append(s.constraints, nil)
+	}
+	s.constraints[i] = constraints
+}
+
+// RemoveCert removes a certificate, and any constraints attached to it, from
+// a pool. It reports whether the certificate was in the pool.
+func (s *CertPool) RemoveCert(cert *Certificate) bool {
+	i := s.indexOf(cert)
+	if i < 0 {
+		return false
+	}
+
+	certs := s.certs
+	constraints := s.constraints
+	s.certs = nil
+	s.constraints = nil
+	s.bySubjectKeyId = make(map[string][]int, len(s.bySubjectKeyId))
+	s.byName = make(map[string][]int, len(s.byName))
+	for j, c := range certs {
+		if j == i {
+			continue
+		}
+		var cc *RootConstraints
+		if j < len(constraints) {
+			cc = constraints[j]
+		}
+		s.addCert(c, cc)
+	}
+	return true
+}
+
+// Certs returns the certificates in the pool, in the order they were added.
+// The returned slice is a copy, but the certificates are shared with the
+// pool and must not be modified.
+func (s *CertPool) Certs() []*Certificate {
+	res := make([]*Certificate, len(s.certs))
+	copy(res, s.certs)
+	return res
+}
+
 // AppendCertsFromPEM attempts to parse a series of PEM encoded certificates.
 // It appends any certificates found to s and reports whether any certificates
 // were successfully parsed.
@@ -65,6 +65,10 @@ const (
 	// CANotAuthorizedForExtKeyUsage results when an intermediate or root
 	// certificate does not permit a requested extended key usage.
 	CANotAuthorizedForExtKeyUsage
+	// RootConstraintsViolated results when every chain that could be built
+	// ends at a root whose RootConstraints, set with
+	// CertPool.AddCertWithConstraints, do not permit the leaf certificate.
+	RootConstraintsViolated
 )
 
 // CertificateInvalidError results when an odd error occurs. Users of this
@@ -95,6 +99,8 @@ func (e CertificateInvalidError) Error() string {
 		return "x509: issuer has name constraints but leaf doesn't have a SAN extension"
 	case UnconstrainedName:
 		return "x509: issuer has name constraints but leaf contains unknown or unconstrained name: " + e.Detail
+	case RootConstraintsViolated:
+		return "x509: root certificate constraints do not permit this certificate: " + e.Detail
 	}
 	return "x509: unknown error"
 }
@@ -720,6 +726,10 @@ func (c *Certificate) isValid(certType int, currentChain []*Certificate, opts *V
 // root that enumerates EKUs prevents a leaf from asserting an EKU not in that
 // list.
 //
+// Chains that end at a root added to opts.Roots with
+// CertPool.AddCertWithConstraints are only returned if the leaf satisfies
+// the root's RootConstraints.
+//
 // WARNING: this function doesn't do any revocation checking.
 func (c *Certificate) Verify(opts VerifyOptions) (chains [][]*Certificate, err error) {
 	// Platform-specific verification needs the ASN.1 contents so
@@ -773,6 +783,10 @@ func (c *Certificate) Verify(opts VerifyOptions) (chains [][]*Certificate, err e
 		keyUsages = []ExtKeyUsage{ExtKeyUsageServerAuth}
 	}
 
+	if candidateChains, err = c.checkRootConstraints(candidateChains, keyUsages, &opts); err != nil {
+		return nil, err
+	}
+
 	// If any key usage is acceptable then we're done.
 	for _, usage := range keyUsages {
 		if usage == ExtKeyUsageAny {
@@ -793,6 +807,106 @@ func (c *Certificate) Verify(opts VerifyOptions) (chains [][]*Certificate, err e
 	return chains, nil
 }
 
+// checkRootConstraints returns the chains whose root permits c, according to
+// the RootConstraints attached to the root in opts.Roots.
+func (c *Certificate) checkRootConstraints(chains [][]*Certificate, keyUsages []ExtKeyUsage, opts *VerifyOptions) ([][]*Certificate, error) {
+	var permitted [][]*Certificate
+	var firstErr error
+	for _, chain := range chains {
+		rc := opts.Roots.rootConstraints(chain[len(chain)-1])
+		if rc == nil {
+			permitted = append(permitted, chain)
+			continue
+		}
+		if detail := rc.check(c, keyUsages, opts); detail != "" {
+			if firstErr == nil {
+				firstErr = CertificateInvalidError{c, RootConstraintsViolated, detail}
+			}
+			continue
+		}
+		permitted = append(permitted, chain)
+	}
+	if len(permitted) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return permitted, nil
+}
+
+// check returns a description of why rc doesn't permit leaf to be verified
+// for keyUsages, or the empty string if it does.
+func (rc *RootConstraints) check(leaf *Certificate, keyUsages []ExtKeyUsage, opts *VerifyOptions) string {
+	if !rc.NotAfter.IsZero() {
+		now := opts.CurrentTime
+		if now.IsZero() {
+			now = time.Now()
+		}
+		if now.After(rc.NotAfter) {
+			return "root is not trusted after " + rc.NotAfter.Format(time.RFC3339)
+		}
+	}
+
+	if len(rc.DNSDomains) > 0 {
+		if len(leaf.IPAddresses) > 0 {
+			return fmt.Sprintf("IP address %q is not permitted", leaf.IPAddresses[0].String())
+		}
+		names := leaf.DNSNames
+		if len(names) == 0 && leaf.commonNameAsHostname() {
+			names = []string{leaf.Subject.CommonName}
+		}
+		if len(names) == 0 {
+			return "certificate asserts no DNS names"
+		}
+		if opts.DNSName != "" && net.ParseIP(opts.DNSName) == nil {
+			names = append(names[:len(names):len(names)], opts.DNSName)
+		}
+		for _, name := range names {
+			if !rc.permitsDomain(name) {
+				return fmt.Sprintf("DNS name %q is not permitted", name)
+			}
+		}
+	}
+
+	if len(rc.ExtKeyUsages) > 0 {
+		usages := keyUsages
+		for _, usage := range keyUsages {
+			if usage == ExtKeyUsageAny {
+				usages = leaf.ExtKeyUsage
+				if len(usages) == 0 {
+					usages = []ExtKeyUsage{ExtKeyUsageAny}
+				}
+				break
+			}
+		}
+	NextUsage:
+		for _, usage := range usages {
+			for _, allowed := range rc.ExtKeyUsages {
+				if usage == allowed || allowed == ExtKeyUsageAny {
+					continue NextUsage
+				}
+			}
+			return fmt.Sprintf("extended key usage %d is not permitted", usage)
+		}
+	}
+
+	return ""
+}
+
+func (rc *RootConstraints) permitsDomain(name string) bool {
+	// Wildcard names are checked as if they were their parent domain.
+	name = strings.TrimPrefix(name, "*.")
+	name = strings.TrimSuffix(name, ".")
+	for _, domain := range rc.DNSDomains {
+		domain = strings.TrimPrefix(domain, ".")
+		if domain == "" {
+			continue
+		}
+		if ok, err := matchDomainConstraint(name, domain); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
 func appendToFreshChain(chain []*Certificate, cert *Certificate) []*Certificate {
 	n := make([]*Certificate, len(chain)+1)
 	copy(n, chain)
@@ -2124,3 +2124,164 @@ func TestLongChain(t *testing.T) {
 	}
 	t.Logf("verification took %v", time.Since(start))
 }
+
+func generateLeaf(dnsNames []string, usages []ExtKeyUsage, issuer *Certificate, issuerKey crypto.PrivateKey) (*Certificate, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "Leaf"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     KeyUsageDigitalSignature,
+		ExtKeyUsage:  usages,
+		DNSNames:     dnsNames,
+	}
+	derBytes, err := CreateCertificate(rand.Reader, template, issuer, priv.Public(), issuerKey)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCertificate(derBytes)
+}
+
+func TestRootConstraints(t *testing.T) {
+	root, rootKey, err := generateCert("Partner Root", true, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		constraints RootConstraints
+		dnsNames    []string
+		usages      []ExtKeyUsage
+		opts        VerifyOptions
+		ok          bool
+	}{
+		{
+			name:        "domain permitted",
+			constraints: RootConstraints{DNSDomains: []string{"partner.example"}},
+			dnsNames:    []string{"partner.example", "www.partner.example"},
+			opts:        VerifyOptions{DNSName: "www.partner.example"},
+			ok:          true,
+		},
+		{
+			name:        "domain not permitted",
+			constraints: RootConstraints{DNSDomains: []string{"partner.example"}},
+			dnsNames:    []string{"www.partner.example", "bank.example"},
+		},
+		{
+			name:        "suffix is not a label boundary",
+			constraints: RootConstraints{DNSDomains: []string{"partner.example"}},
+			dnsNames:    []string{"evilpartner.example"},
+		},
+		{
+			name:        "no names",
+			constraints: RootConstraints{DNSDomains: []string{"partner.example"}},
+		},
+		{
+			name:        "wildcard permitted",
+			constraints: RootConstraints{DNSDomains: []string{".partner.example"}},
+			dnsNames:    []string{"*.partner.example"},
+			ok:          true,
+		},
+		{
+			name:        "usage permitted",
+			constraints: RootConstraints{ExtKeyUsages: []ExtKeyUsage{ExtKeyUsageServerAuth}},
+			dnsNames:    []string{"partner.example"},
+			usages:      []ExtKeyUsage{ExtKeyUsageServerAuth},
+			ok:          true,
+		},
+		{
+			name:        "usage not permitted",
+			constraints: RootConstraints{ExtKeyUsages: []ExtKeyUsage{ExtKeyUsageServerAuth}},
+			dnsNames:    []string{"partner.example"},
+			usages:      []ExtKeyUsage{ExtKeyUsageClientAuth},
+			opts:        VerifyOptions{KeyUsages: []ExtKeyUsage{ExtKeyUsageClientAuth}},
+		},
+		{
+			name:        "any usage checks leaf",
+			constraints: RootConstraints{ExtKeyUsages: []ExtKeyUsage{ExtKeyUsageServerAuth}},
+			dnsNames:    []string{"partner.example"},
+			usages:      []ExtKeyUsage{ExtKeyUsageServerAuth, ExtKeyUsageCodeSigning},
+			opts:        VerifyOptions{KeyUsages: []ExtKeyUsage{ExtKeyUsageAny}},
+		},
+		{
+			name:        "before cutoff",
+			constraints: RootConstraints{NotAfter: time.Now().Add(time.Hour)},
+			dnsNames:    []string{"partner.example"},
+			ok:          true,
+		},
+		{
+			name:        "after cutoff",
+			constraints: RootConstraints{NotAfter: time.Now().Add(-time.Minute)},
+			dnsNames:    []string{"partner.example"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaf, err := generateLeaf(tt.dnsNames, tt.usages, root, rootKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := tt.opts
+			opts.Roots = NewCertPool()
+			opts.Roots.AddCertWithConstraints(root, tt.constraints)
+
+			_, err = leaf.Verify(opts)
+			if tt.ok && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.ok {
+				if err, ok := err.(CertificateInvalidError); !ok || err.Reason != RootConstraintsViolated {
+					t.Errorf("got error %v, want RootConstraintsViolated", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCertPoolRemoveCert(t *testing.T) {
+	var certs []*Certificate
+	pool := NewCertPool()
+	for i := 0; i < 3; i++ {
+		cert, _, err := generateCert(fmt.Sprintf("Root %d", i), true, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certs = append(certs, cert)
+	}
+	pool.AddCert(certs[0])
+	pool.AddCertWithConstraints(certs[1], RootConstraints{DNSDomains: []string{"example.com"}})
+	pool.AddCert(certs[2])
+
+	if got := pool.Certs(); len(got) != 3 || got[0] != certs[0] || got[1] != certs[1] || got[2] != certs[2] {
+		t.Fatalf("Certs() = %v, want %v", got, certs)
+	}
+
+	if !pool.RemoveCert(certs[0]) {
+		t.Fatal("RemoveCert returned false for a certificate in the pool")
+	}
+	if pool.RemoveCert(certs[0]) {
+		t.Fatal("RemoveCert returned true for a removed certificate")
+	}
+	if pool.contains(certs[0]) || !pool.contains(certs[1]) || !pool.contains(certs[2]) {
+		t.Fatal("pool contents are wrong after RemoveCert")
+	}
+	if len(pool.Subjects()) != 2 {
+		t.Errorf("got %d subjects, want 2", len(pool.Subjects()))
+	}
+	// The constraints must follow the certificate they were attached to.
+	if rc := pool.rootConstraints(certs[1]); rc == nil || rc.DNSDomains[0] != "example.com" {
+		t.Errorf("constraints of remaining certificate were lost")
+	}
+	if rc := pool.rootConstraints(certs[2]); rc != nil {
+		t.Errorf("unconstrained certificate gained constraints")
+	}
+	if _, err := certs[2].Verify(VerifyOptions{Roots: pool, KeyUsages: []ExtKeyUsage{ExtKeyUsageAny}}); err != nil {
+		t.Errorf("root from a modified pool failed to verify: %v", err)
+	}
+}
@@ -347,6 +347,7 @@ var pkgDeps = map[string][]string{
 	// Core crypto.
 	"crypto/aes":               {"L3"},
 	"crypto/des":               {"L3"},
+	"crypto/hkdf":              {"L3", "crypto/hmac"},
 	"crypto/hmac":              {"L3"},
 	"crypto/internal/randutil": {"io", "sync"},
 	"crypto/md5":               {"L3"},
@@ -358,6 +359,7 @@ var pkgDeps = map[string][]string{
 	"CRYPTO": {
 		"crypto/aes",
 		"crypto/des",
+		"crypto/hkdf",
 		"crypto/hmac",
 		"crypto/internal/randutil",
 		"crypto/md5",
@@ -378,17 +380,22 @@ var pkgDeps = map[string][]string{
 	// Not part of CRYPTO because it imports crypto/rand and crypto/sha512.
 	"crypto/ed25519":                       {"L3", "CRYPTO", "crypto/rand", "crypto/ed25519/internal/edwards25519"},
 	"crypto/ed25519/internal/edwards25519": {"encoding/binary"},
+	"crypto/internal/nistec":               {"L0", "crypto/subtle", "crypto/internal/nistec/fiat"},
+	"crypto/internal/nistec/fiat":          {"L0", "crypto/subtle", "math/bits"},
 
 	// Mathematical crypto: dependencies on fmt (L4) and math/big.
 	// We could avoid some of the fmt, but math/big imports fmt anyway.
 	"crypto/dsa":      {"L4", "CRYPTO", "math/big"},
-	"crypto/ecdsa":    {"L4", "CRYPTO", "crypto/elliptic", "math/big", "encoding/asn1"},
+	"crypto/ecdh":     {"L4", "CRYPTO", "crypto/elliptic", "crypto/internal/nistec"},
+	"crypto/ecdsa":    {"L4", "CRYPTO", "crypto/ecdh", "crypto/elliptic", "math/big", "encoding/asn1"},
 	"crypto/elliptic": {"L4", "CRYPTO", "math/big"},
+	"crypto/hpke":     {"L4", "CRYPTO", "crypto/ecdh", "crypto/rand"},
 	"crypto/rsa":      {"L4", "CRYPTO", "crypto/rand", "math/big"},
 
 	"CRYPTO-MATH": {
 		"CRYPTO",
 		"crypto/dsa",
+		"crypto/ecdh",
 		"crypto/ecdsa",
 		"crypto/elliptic",
 		"crypto/rand",
@@ -399,7 +406,7 @@ var pkgDeps = map[string][]string{
 
 	// SSL/TLS.
 	"crypto/tls": {
-		"L4", "CRYPTO-MATH", "OS", "golang.org/x/crypto/cryptobyte", "golang.org/x/crypto/hkdf",
+		"L4", "CRYPTO-MATH", "OS", "golang.org/x/crypto/cryptobyte",
 		"container/list", "crypto/x509", "encoding/pem", "net", "syscall",
 	},
 	"crypto/x509": {
@@ -417,6 +424,7 @@ var pkgDeps = map[string][]string{
 	"net/http": {
 		"L4", "NET", "OS",
 		"compress/gzip",
+		"compress/zlib",
 		"container/list",
 		"context",
 		"crypto/rand",
@@ -440,16 +448,22 @@ var pkgDeps = map[string][]string{
 	// HTTP-using packages.
 	"expvar":             {"L4", "OS", "encoding/json", "net/http"},
 	"net/http/cgi":       {"L4", "NET", "OS", "crypto/tls", "net/http", "regexp"},
-	"net/http/cookiejar": {"L4", "NET", "net/http"},
+	"net/http/cookiejar": {"L4", "NET", "encoding/json", "net/http"},
+	"net/http/httpcache": {"L4", "NET", "OS", "container/list", "context", "crypto/sha256", "encoding/hex", "net/http", "net/textproto"},
 	"net/http/fcgi":      {"L4", "NET", "OS", "context", "net/http", "net/http/cgi"},
 	"net/http/httptest": {
-		"L4", "NET", "OS", "crypto/tls", "flag", "net/http", "net/http/internal", "crypto/x509",
+		"L4", "NET", "OS", "context", "crypto/tls", "flag", "net/http", "net/http/internal", "crypto/x509",
 		"golang.org/x/net/http/httpguts",
 	},
 	"net/http/httputil": {"L4", "NET", "OS", "context", "net/http", "net/http/internal", "golang.org/x/net/http/httpguts"},
 	"net/http/pprof":    {"L4", "OS", "html/template", "net/http", "runtime/pprof", "runtime/trace"},
 	"net/rpc":           {"L4", "NET", "encoding/gob", "html/template", "net/http", "go/token"},
 	"net/rpc/jsonrpc":   {"L4", "NET", "encoding/json", "net/rpc"},
+	"net/http/sse":      {"L4", "net/http"},
+	"net/http/websocket": {
+		"L4", "NET", "compress/flate", "context", "crypto/rand", "crypto/sha1",
+		"encoding/base64", "net/http", "net/url",
+	},
 }
 
 // isMacro reports whether p is a package dependency macro
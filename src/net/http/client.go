@@ -102,6 +102,16 @@ type Client struct {
 	// RoundTripper implementations should use the Request's Context
 	// for cancellation instead of implementing CancelRequest.
 	Timeout time.Duration
+
+	// RetryPolicy specifies the policy for retrying requests whose
+	// attempts fail with an error or a retryable response status.
+	// Retries are made for each request of a redirect chain, and
+	// count against Timeout.
+	//
+	// If RetryPolicy is nil, the Client does not retry requests;
+	// the Transport may still retry some requests on its own, as
+	// described in its documentation.
+	RetryPolicy *RetryPolicy
 }
 
 // DefaultClient is the default Client and is used by Get, Head, and Post.
@@ -638,7 +648,7 @@ func (c *Client) do(req *Request) (retres *Response, reterr error) {
 		reqs = append(reqs, req)
 		var err error
 		var didTimeout func() bool
-		if resp, didTimeout, err = c.send(req, deadline); err != nil {
+		if resp, didTimeout, err = c.sendWithRetries(req, deadline); err != nil {
 			// c.send() always closes req.Body
 			reqBodyClosed = true
 			if !deadline.IsZero() && didTimeout() {
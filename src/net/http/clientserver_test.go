@@ -9,6 +9,7 @@ package http_test
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/rand"
 	"crypto/sha1"
 	"crypto/tls"
@@ -1568,3 +1569,128 @@ func TestH12_WebSocketUpgrade(t *testing.T) {
 		},
 	}.run(t)
 }
+
+func TestUnencryptedHTTP2(t *testing.T) {
+	defer afterTest(t)
+	ts := httptest.NewUnstartedServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, r.Proto)
+	}))
+	var sp Protocols
+	sp.SetHTTP1(true)
+	sp.SetUnencryptedHTTP2(true)
+	ts.Config.Protocols = &sp
+	ts.Config.HTTP2 = &HTTP2Config{
+		MaxConcurrentStreams: 10,
+		MaxReadFrameSize:     1 << 20,
+	}
+	ts.Start()
+	defer ts.Close()
+
+	var cp Protocols
+	cp.SetUnencryptedHTTP2(true)
+	tr := &Transport{
+		Protocols: &cp,
+		HTTP2: &HTTP2Config{
+			MaxHeaderListSize: 1 << 20,
+		},
+	}
+	defer tr.CloseIdleConnections()
+	for _, test := range []struct {
+		c     *Client
+		path  string
+		proto string
+	}{
+		{&Client{Transport: tr}, "/", "HTTP/2.0"},
+		{&Client{Transport: tr}, "/again", "HTTP/2.0"},
+		{ts.Client(), "/", "HTTP/1.1"},
+	} {
+		res, err := test.c.Get(ts.URL + test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Proto != test.proto || string(body) != test.proto {
+			t.Errorf("GET %s: response proto %q, request proto %q; want %q", test.path, res.Proto, body, test.proto)
+		}
+	}
+}
+
+// Shutdown must close idle unencrypted HTTP/2 connections.
+func TestUnencryptedHTTP2Shutdown(t *testing.T) {
+	defer afterTest(t)
+	ts := httptest.NewUnstartedServer(HandlerFunc(func(w ResponseWriter, r *Request) {}))
+	var sp Protocols
+	sp.SetUnencryptedHTTP2(true)
+	ts.Config.Protocols = &sp
+	ts.Start()
+	defer ts.Close()
+
+	var cp Protocols
+	cp.SetUnencryptedHTTP2(true)
+	tr := &Transport{Protocols: &cp}
+	defer tr.CloseIdleConnections()
+	res, err := (&Client{Transport: tr}).Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := ts.Config.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+}
+
+// Canceling a request must cancel the dial of its unencrypted HTTP/2
+// connection.
+func TestUnencryptedHTTP2DialCanceled(t *testing.T) {
+	defer afterTest(t)
+	var p Protocols
+	p.SetUnencryptedHTTP2(true)
+	dialErr := make(chan error, 1)
+	tr := &Transport{
+		Protocols: &p,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			<-ctx.Done()
+			dialErr <- ctx.Err()
+			return nil, ctx.Err()
+		},
+	}
+	defer tr.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := NewRequest("GET", "http://example.com/", nil)
+	if res, err := (&Client{Transport: tr}).Do(req.WithContext(ctx)); err == nil {
+		res.Body.Close()
+		t.Fatal("request succeeded; want error")
+	}
+	select {
+	case err := <-dialErr:
+		if err != context.DeadlineExceeded {
+			t.Errorf("dial context error = %v; want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dial was not canceled")
+	}
+}
+
+func TestTransportProtocolsWithoutHTTP1(t *testing.T) {
+	defer afterTest(t)
+	ts := httptest.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {}))
+	defer ts.Close()
+
+	var p Protocols
+	p.SetHTTP2(true)
+	c := ts.Client()
+	c.Transport.(*Transport).Protocols = &p
+	if res, err := c.Get(ts.URL); err == nil {
+		res.Body.Close()
+		t.Fatal("request succeeded over HTTP/1 with HTTP1 disabled")
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cookiejar
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// An Entry is a cookie stored in a Jar, with the attributes defined in
+// RFC 6265 section 5.3.
+//
+// Entries are returned by Jar.Entries and added with Jar.SetEntries, so
+// that the contents of a Jar can be inspected, or saved and restored
+// later. Their fields can be encoded with encoding/json, as done by
+// Jar.Save and Jar.Load.
+type Entry struct {
+	Name  string
+	Value string
+
+	// Domain is the canonical host name the cookie applies to, in
+	// its ASCII form and without a leading dot.
+	Domain string
+
+	// Path is the path the cookie applies to.
+	Path string
+
+	// SameSite is the cookie's SameSite attribute as it was
+	// received, such as "SameSite=Lax", or empty if the cookie has
+	// none.
+	SameSite string
+
+	Secure   bool
+	HttpOnly bool
+
+	// Persistent reports whether the cookie has an expiry time.
+	// Cookies that are not persistent only last for the current
+	// session according to RFC 6265; it is up to the user of a Jar
+	// whether to save them.
+	Persistent bool
+
+	// HostOnly reports whether the cookie is only sent to Domain,
+	// and not to its subdomains.
+	HostOnly bool
+
+	// Expires is the time when the cookie expires. It is ignored
+	// unless Persistent is true.
+	Expires time.Time
+
+	// Creation and LastAccess are the times when the cookie was
+	// first stored and last sent.
+	Creation   time.Time
+	LastAccess time.Time
+}
+
+func (e *entry) export() Entry {
+	x := Entry{
+		Name:       e.Name,
+		Value:      e.Value,
+		Domain:     e.Domain,
+		Path:       e.Path,
+		SameSite:   e.SameSite,
+		Secure:     e.Secure,
+		HttpOnly:   e.HttpOnly,
+		Persistent: e.Persistent,
+		HostOnly:   e.HostOnly,
+		Creation:   e.Creation,
+		LastAccess: e.LastAccess,
+	}
+	if e.Persistent {
+		x.Expires = e.Expires
+	}
+	return x
+}
+
+var (
+	errNoName      = errors.New("cookiejar: entry has no name")
+	errInvalidPath = errors.New("cookiejar: entry path does not start with a slash")
+)
+
+// Entries returns the cookies stored in the jar that have not expired,
+// grouped by domain and in the order they were stored within a domain.
+// The result is a copy; modifying it does not change the jar.
+func (j *Jar) Entries() []Entry {
+	return j.entriesAt(time.Now())
+}
+
+func (j *Jar) entriesAt(now time.Time) []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var selected []entry
+	for _, submap := range j.entries {
+		for _, e := range submap {
+			if e.Persistent && !e.Expires.After(now) {
+				continue
+			}
+			selected = append(selected, e)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		s := selected
+		if s[i].Domain != s[j].Domain {
+			return s[i].Domain < s[j].Domain
+		}
+		return s[i].seqNum < s[j].seqNum
+	})
+	entries := make([]Entry, len(selected))
+	for i := range selected {
+		entries[i] = selected[i].export()
+	}
+	return entries
+}
+
+// SetEntries adds entries to the jar, replacing any cookie with the
+// same name, domain and path. Expired entries are skipped.
+//
+// The entries are checked as if their cookies had just been received
+// from their Domain, so that, for example, an entry for a public suffix
+// is stored as a host-only cookie. SetEntries returns the first error
+// encountered for an invalid entry, after adding all the valid ones.
+func (j *Jar) SetEntries(entries []Entry) error {
+	return j.setEntries(entries, time.Now())
+}
+
+func (j *Jar) setEntries(entries []Entry, now time.Time) error {
+	var firstErr error
+	valid := make([]entry, 0, len(entries))
+	for _, x := range entries {
+		e, err := j.importEntry(x)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if e.Persistent && !e.Expires.After(now) {
+			continue
+		}
+		valid = append(valid, e)
+	}
+	// Keep the relative order of the cookies that Cookies uses
+	// for equal path lengths.
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].Creation.Before(valid[j].Creation)
+	})
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, e := range valid {
+		key := jarKey(e.Domain, j.psList)
+		submap := j.entries[key]
+		if submap == nil {
+			submap = make(map[string]entry)
+			j.entries[key] = submap
+		}
+		e.seqNum = j.nextSeqNum
+		j.nextSeqNum++
+		submap[e.id()] = e
+	}
+	return firstErr
+}
+
+// importEntry validates x and returns it as an entry.
+func (j *Jar) importEntry(x Entry) (entry, error) {
+	if x.Name == "" {
+		return entry{}, errNoName
+	}
+	if x.Path == "" || x.Path[0] != '/' {
+		return entry{}, errInvalidPath
+	}
+	host, err := canonicalHost(x.Domain)
+	if err != nil {
+		return entry{}, err
+	}
+	if host == "" {
+		return entry{}, errMalformedDomain
+	}
+	var domainAttr string
+	if !x.HostOnly {
+		domainAttr = host
+	}
+	domain, hostOnly, err := j.domainAndType(host, domainAttr)
+	if err != nil {
+		return entry{}, err
+	}
+	e := entry{
+		Name:       x.Name,
+		Value:      x.Value,
+		Domain:     domain,
+		Path:       x.Path,
+		SameSite:   x.SameSite,
+		Secure:     x.Secure,
+		HttpOnly:   x.HttpOnly,
+		Persistent: x.Persistent,
+		HostOnly:   hostOnly,
+		Expires:    x.Expires,
+		Creation:   x.Creation,
+		LastAccess: x.LastAccess,
+	}
+	if !e.Persistent {
+		e.Expires = endOfTime
+	}
+	return e, nil
+}
+
+// Remove removes the cookie with the given name, domain and path from
+// the jar, if any. It reports whether a cookie was removed.
+func (j *Jar) Remove(domain, path, name string) bool {
+	domain, err := canonicalHost(domain)
+	if err != nil {
+		return false
+	}
+	key := jarKey(domain, j.psList)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	submap := j.entries[key]
+	e := entry{Domain: domain, Path: path, Name: name}
+	if _, ok := submap[e.id()]; !ok {
+		return false
+	}
+	delete(submap, e.id())
+	if len(submap) == 0 {
+		delete(j.entries, key)
+	}
+	return true
+}
+
+// RemoveDomain removes the cookies for domain and its subdomains from
+// the jar, and returns the number of cookies removed.
+func (j *Jar) RemoveDomain(domain string) int {
+	domain, err := canonicalHost(strings.TrimPrefix(domain, "."))
+	if err != nil || domain == "" {
+		return 0
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	n := 0
+	for key, submap := range j.entries {
+		for id, e := range submap {
+			if e.Domain == domain || hasDotSuffix(e.Domain, domain) {
+				delete(submap, id)
+				n++
+			}
+		}
+		if len(submap) == 0 {
+			delete(j.entries, key)
+		}
+	}
+	return n
+}
+
+// Clear removes all cookies from the jar.
+func (j *Jar) Clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = make(map[string]map[string]entry)
+}
+
+// Save writes the entries of the jar, as returned by Entries, to w as
+// a JSON array.
+func (j *Jar) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(j.Entries())
+}
+
+// Load reads a JSON array of entries, as written by Save, from r and
+// adds them to the jar as SetEntries does.
+func (j *Jar) Load(r io.Reader) error {
+	var entries []Entry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return err
+	}
+	return j.SetEntries(entries)
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cookiejar
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	jar := newTestJar()
+	u := mustParseURL("https://www.host.test/some/path")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "s", HttpOnly: true},
+		{Name: "a", Value: "1", Domain: "host.test", Path: "/", MaxAge: 3600, Secure: true},
+		{Name: "b", Value: "2", MaxAge: 3600, SameSite: http.SameSiteLaxMode},
+	})
+	jar.SetCookies(mustParseURL("http://other.co.uk/"), []*http.Cookie{
+		{Name: "c", Value: "3", MaxAge: 3600},
+	})
+
+	var buf bytes.Buffer
+	if err := jar.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	jar2 := newTestJar()
+	if err := jar2.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, u := range []string{"https://www.host.test/some/path", "https://sub.host.test/", "http://other.co.uk/"} {
+		got := cookieString(jar2.Cookies(mustParseURL(u)))
+		want := cookieString(jar.Cookies(mustParseURL(u)))
+		if got != want {
+			t.Errorf("after Load, cookies for %s = %q; want %q", u, got, want)
+		}
+	}
+
+	entries := jar2.Entries()
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries; want 4", len(entries))
+	}
+	for _, e := range entries {
+		switch e.Name {
+		case "a":
+			if e.HostOnly || e.Domain != "host.test" || !e.Secure || !e.Persistent {
+				t.Errorf("entry a = %+v", e)
+			}
+		case "b":
+			if e.SameSite != "SameSite=Lax" || !e.HostOnly || e.Path != "/some" {
+				t.Errorf("entry b = %+v", e)
+			}
+		case "session":
+			if e.Persistent || !e.Expires.IsZero() || !e.HttpOnly {
+				t.Errorf("entry session = %+v", e)
+			}
+		}
+	}
+}
+
+func TestSetEntries(t *testing.T) {
+	jar := newTestJar()
+	expires := tNow.Add(time.Hour)
+	err := jar.setEntries([]Entry{
+		{Name: "a", Value: "1", Domain: "WWW.Example.COM", Path: "/", HostOnly: true, Persistent: true, Expires: expires},
+		{Name: "b", Value: "2", Domain: "example.com", Path: "/", Persistent: true, Expires: expires},
+		{Name: "old", Value: "x", Domain: "example.com", Path: "/", Persistent: true, Expires: tNow.Add(-time.Hour)},
+		// A domain cookie for a public suffix is stored as a
+		// host cookie.
+		{Name: "ps", Value: "3", Domain: "co.uk", Path: "/", Persistent: true, Expires: expires},
+		{Name: "", Value: "bad", Domain: "example.com", Path: "/"},
+		{Name: "bad", Value: "bad", Domain: "example.com", Path: "relative"},
+		{Name: "ip", Value: "bad", Domain: "10.0.0.1", Path: "/"},
+	}, tNow)
+	if err != errNoName {
+		t.Errorf("setEntries error = %v; want %v", err, errNoName)
+	}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"http://www.example.com/", "a=1 b=2"},
+		{"http://sub.www.example.com/", "b=2"},
+		{"http://co.uk/", "ps=3"},
+		{"http://foo.co.uk/", ""},
+	}
+	for _, tt := range tests {
+		if got := cookieString(jar.cookies(mustParseURL(tt.url), tNow)); got != tt.want {
+			t.Errorf("cookies for %s = %q; want %q", tt.url, got, tt.want)
+		}
+	}
+	if got := len(jar.entriesAt(tNow)); got != 3 {
+		t.Errorf("got %d entries; want 3", got)
+	}
+	if got := len(jar.entriesAt(expires)); got != 0 {
+		t.Errorf("got %d entries after expiry; want 0", got)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	jar := newTestJar()
+	jar.setCookies(mustParseURL("http://www.example.com/"), []*http.Cookie{
+		{Name: "a", Value: "1"},
+		{Name: "b", Value: "2", Domain: "example.com"},
+	}, tNow)
+	jar.setCookies(mustParseURL("http://sub.www.example.com/"), []*http.Cookie{
+		{Name: "c", Value: "3"},
+	}, tNow)
+	jar.setCookies(mustParseURL("http://example.org/"), []*http.Cookie{
+		{Name: "d", Value: "4"},
+	}, tNow)
+
+	if jar.Remove("www.example.com", "/", "b") {
+		t.Errorf("Remove of b with the wrong domain reported success")
+	}
+	if !jar.Remove("Example.com", "/", "b") {
+		t.Errorf("Remove of b failed")
+	}
+	if got := cookieString(jar.cookies(mustParseURL("http://www.example.com/"), tNow)); got != "a=1" {
+		t.Errorf("after Remove, cookies = %q; want %q", got, "a=1")
+	}
+
+	if n := jar.RemoveDomain(".www.example.com"); n != 2 {
+		t.Errorf("RemoveDomain removed %d cookies; want 2", n)
+	}
+	var names []string
+	for _, e := range jar.entriesAt(tNow) {
+		names = append(names, e.Name)
+	}
+	if got := strings.Join(names, " "); got != "d" {
+		t.Errorf("after RemoveDomain, entries = %q; want %q", got, "d")
+	}
+
+	jar.Clear()
+	if got := len(jar.entriesAt(tNow)); got != 0 {
+		t.Errorf("after Clear, got %d entries", got)
+	}
+}
+
+// cookieString returns cookies in the form "name1=val1 name2=val2".
+func cookieString(cookies []*http.Cookie) string {
+	var s []string
+	for _, c := range cookies {
+		s = append(s, c.Name+"="+c.Value)
+	}
+	return strings.Join(s, " ")
+}
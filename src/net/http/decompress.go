@@ -0,0 +1,156 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrBodyCompressionRatio is returned by reads from a request body
+// decompressed by DecompressRequestHandler when the body expands by
+// more than the allowed ratio.
+var ErrBodyCompressionRatio = errors.New("http: request body compression ratio too high")
+
+// ratioSlack is how many bytes a decompressed request body may hold
+// before its compression ratio is checked, so that small, highly
+// compressible bodies are not rejected.
+const ratioSlack = 64 << 10
+
+// DecompressRequestHandler returns a Handler that runs h with the
+// request body transparently decompressed, if the request has a
+// Content-Encoding of gzip or deflate. The Content-Encoding and
+// Content-Length headers are removed from such requests, and their
+// ContentLength is set to -1. Requests with any other Content-Encoding
+// are answered with a 415 Unsupported Media Type error.
+//
+// Reads from a decompressed body fail with a *MaxBytesError once it
+// exceeds maxBytes bytes, and with ErrBodyCompressionRatio once it is
+// larger than 64 KB and more than maxRatio times the size of the
+// compressed data read. A zero maxBytes or maxRatio means no limit.
+// As with MaxBytesHandler, if h returns without writing a response
+// after a limit is exceeded, a 413 Request Entity Too Large error is
+// sent.
+func DecompressRequestHandler(h Handler, maxBytes int64, maxRatio int) Handler {
+	return HandlerFunc(func(w ResponseWriter, r *Request) {
+		ce := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+		var isGzip bool
+		switch ce {
+		case "", "identity":
+			h.ServeHTTP(w, r)
+			return
+		case "gzip", "x-gzip":
+			isGzip = true
+		case "deflate":
+		default:
+			Error(w, StatusText(StatusUnsupportedMediaType), StatusUnsupportedMediaType)
+			return
+		}
+		if r.Body == nil || r.Body == NoBody {
+			h.ServeHTTP(w, r)
+			return
+		}
+		r2 := *r
+		r2.Header = r.Header.Clone()
+		r2.Header.Del("Content-Encoding")
+		r2.Header.Del("Content-Length")
+		r2.ContentLength = -1
+		d := &decompressReader{
+			w:        w,
+			body:     r.Body,
+			isGzip:   isGzip,
+			maxBytes: maxBytes,
+			maxRatio: int64(maxRatio),
+		}
+		r2.Body = d
+		h.ServeHTTP(w, &r2)
+		if d.limitHit && !headerWritten(w) {
+			Error(w, StatusText(StatusRequestEntityTooLarge), StatusRequestEntityTooLarge)
+		}
+	})
+}
+
+// decompressReader is a request body decompressed by
+// DecompressRequestHandler.
+type decompressReader struct {
+	w        ResponseWriter
+	body     io.ReadCloser // underlying compressed body
+	isGzip   bool          // whether body is gzip rather than zlib data
+	maxBytes int64
+	maxRatio int64
+
+	zr       io.Reader // lazily-initialized decompressor
+	in       int64     // compressed bytes read from body
+	out      int64     // decompressed bytes returned
+	err      error     // sticky error
+	limitHit bool      // whether err is from exceeding a limit
+}
+
+// compressedReader counts the bytes read from the body of a
+// decompressReader.
+type compressedReader struct {
+	d *decompressReader
+}
+
+func (cr compressedReader) Read(p []byte) (int, error) {
+	n, err := cr.d.body.Read(p)
+	cr.d.in += int64(n)
+	return n, err
+}
+
+func (d *decompressReader) Read(p []byte) (n int, err error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+	if d.zr == nil {
+		if d.isGzip {
+			d.zr, err = gzip.NewReader(compressedReader{d})
+		} else {
+			d.zr, err = zlib.NewReader(compressedReader{d})
+		}
+		if err != nil {
+			d.err = err
+			return 0, err
+		}
+	}
+	if d.maxBytes > 0 && int64(len(p)) > d.maxBytes-d.out+1 {
+		// One byte more than the limit tells whether it is
+		// exceeded.
+		p = p[:d.maxBytes-d.out+1]
+	}
+	n, err = d.zr.Read(p)
+	if d.maxBytes > 0 && d.out+int64(n) > d.maxBytes {
+		n = int(d.maxBytes - d.out)
+		d.out = d.maxBytes
+		return n, d.tooLarge(&MaxBytesError{d.maxBytes})
+	}
+	d.out += int64(n)
+	if d.maxRatio > 0 && d.out > ratioSlack && d.out > d.maxRatio*d.in {
+		return n, d.tooLarge(ErrBodyCompressionRatio)
+	}
+	d.err = err
+	return n, err
+}
+
+// tooLarge records that the body exceeded a limit, reporting err.
+func (d *decompressReader) tooLarge(err error) error {
+	d.err = err
+	d.limitHit = true
+	// See maxBytesReader.Read.
+	type requestTooLarger interface {
+		requestTooLarge()
+	}
+	if res, ok := d.w.(requestTooLarger); ok {
+		res.requestTooLarge()
+	}
+	return err
+}
+
+func (d *decompressReader) Close() error {
+	return d.body.Close()
+}
@@ -33,6 +33,7 @@ var (
 	ExportHttp2ConfigureServer        = http2ConfigureServer
 	Export_shouldCopyHeaderOnRedirect = shouldCopyHeaderOnRedirect
 	Export_writeStatusLine            = writeStatusLine
+	ExportRetryAfter                  = retryAfter
 )
 
 const MaxWriteWaitBeforeConnReuse = maxWriteWaitBeforeConnReuse
@@ -33,6 +33,123 @@ type contextKey struct {
 
 func (k *contextKey) String() string { return "net/http context value " + k.name }
 
+// Protocols is a set of HTTP protocols.
+// The zero value is an empty set of protocols.
+//
+// The supported protocols are:
+//
+//   - HTTP1 is the HTTP/1.0 and HTTP/1.1 protocols.
+//     HTTP1 is supported on both unsecured TCP and secured TLS connections.
+//
+//   - HTTP2 is the HTTP/2 protocol over a TLS connection.
+//
+//   - UnencryptedHTTP2 is the HTTP/2 protocol over an unsecured TCP
+//     connection, with prior knowledge. This is sometimes called h2c.
+type Protocols struct {
+	bits uint8
+}
+
+const (
+	protoHTTP1 = 1 << iota
+	protoHTTP2
+	protoUnencryptedHTTP2
+)
+
+// HTTP1 reports whether p includes HTTP/1.
+func (p Protocols) HTTP1() bool { return p.bits&protoHTTP1 != 0 }
+
+// SetHTTP1 adds or removes HTTP/1 from p.
+func (p *Protocols) SetHTTP1(ok bool) { p.setBit(protoHTTP1, ok) }
+
+// HTTP2 reports whether p includes HTTP/2.
+func (p Protocols) HTTP2() bool { return p.bits&protoHTTP2 != 0 }
+
+// SetHTTP2 adds or removes HTTP/2 from p.
+func (p *Protocols) SetHTTP2(ok bool) { p.setBit(protoHTTP2, ok) }
+
+// UnencryptedHTTP2 reports whether p includes unencrypted HTTP/2.
+func (p Protocols) UnencryptedHTTP2() bool { return p.bits&protoUnencryptedHTTP2 != 0 }
+
+// SetUnencryptedHTTP2 adds or removes unencrypted HTTP/2 from p.
+func (p *Protocols) SetUnencryptedHTTP2(ok bool) { p.setBit(protoUnencryptedHTTP2, ok) }
+
+func (p *Protocols) setBit(bit uint8, ok bool) {
+	if ok {
+		p.bits |= bit
+	} else {
+		p.bits &^= bit
+	}
+}
+
+func (p Protocols) String() string {
+	var s []string
+	if p.HTTP1() {
+		s = append(s, "HTTP1")
+	}
+	if p.HTTP2() {
+		s = append(s, "HTTP2")
+	}
+	if p.UnencryptedHTTP2() {
+		s = append(s, "UnencryptedHTTP2")
+	}
+	return "{" + strings.Join(s, ",") + "}"
+}
+
+// defaultProtocols is the set of protocols used by a Server or
+// Transport whose Protocols field is nil.
+func defaultProtocols() Protocols {
+	var p Protocols
+	p.SetHTTP1(true)
+	p.SetHTTP2(true)
+	return p
+}
+
+// HTTP2Config defines HTTP/2 configuration parameters for Transport
+// and Server. Each field notes which of the two uses it.
+//
+// Values that are zero or out of range are replaced by defaults.
+type HTTP2Config struct {
+	// MaxConcurrentStreams optionally specifies the number of
+	// concurrent streams that a client may have open at a time.
+	// A Server advertises it to clients. If zero, the Server's
+	// default is 250. It is not used by Transport.
+	MaxConcurrentStreams int
+
+	// MaxReadFrameSize optionally specifies the largest frame
+	// a Server is willing to read.
+	// A valid value is between 16KiB and 16MiB, inclusive.
+	// It is not used by Transport.
+	MaxReadFrameSize int
+
+	// MaxReceiveBufferPerConnection is the maximum size of the
+	// flow control window for data a Server receives on a connection.
+	// A valid value is at least 64KiB and less than 2GiB.
+	// It is not used by Transport.
+	MaxReceiveBufferPerConnection int
+
+	// MaxReceiveBufferPerStream is the maximum size of the
+	// flow control window for data a Server receives on a stream
+	// (request). A valid value is at least 64KiB and less than 2GiB.
+	// It is not used by Transport.
+	MaxReceiveBufferPerStream int
+
+	// MaxHeaderListSize is the maximum size of the response header
+	// list that a Transport accepts, advertised in
+	// SETTINGS_MAX_HEADER_LIST_SIZE. If zero, it is derived from
+	// Transport.MaxResponseHeaderBytes. A Server derives its limit
+	// from Server.MaxHeaderBytes instead.
+	MaxHeaderListSize int
+}
+
+// h2ConfigValue returns v as an int32 if it is within [min, 1<<31-1],
+// and 0 otherwise.
+func h2ConfigValue(v, min int) int32 {
+	if v < min || int64(v) > 1<<31-1 {
+		return 0
+	}
+	return int32(v)
+}
+
 // Given a string of the form "host", "host:port", or "[ipv6::address]:port",
 // return true if the string includes a port.
 func hasPort(s string) bool { return strings.LastIndex(s, ":") > strings.LastIndex(s, "]") }
@@ -109,3 +109,22 @@ func TestCmdGoNoHTTPServer(t *testing.T) {
 		}
 	}
 }
+
+func TestProtocolsString(t *testing.T) {
+	var p Protocols
+	if got, want := p.String(), "{}"; got != want {
+		t.Errorf("zero Protocols = %v, want %v", got, want)
+	}
+	p.SetHTTP1(true)
+	p.SetUnencryptedHTTP2(true)
+	if got, want := p.String(), "{HTTP1,UnencryptedHTTP2}"; got != want {
+		t.Errorf("Protocols = %v, want %v", got, want)
+	}
+	p.SetHTTP1(false)
+	if p.HTTP1() || p.HTTP2() || !p.UnencryptedHTTP2() {
+		t.Errorf("Protocols = %v after SetHTTP1(false)", p)
+	}
+	if got, want := defaultProtocols().String(), "{HTTP1,HTTP2}"; got != want {
+		t.Errorf("defaultProtocols() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpcache
+
+import (
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControl holds the directives of Cache-Control header fields,
+// mapping each lower-cased directive name to its argument, if any.
+type cacheControl map[string]string
+
+func parseCacheControl(h http.Header) cacheControl {
+	cc := cacheControl{}
+	for _, line := range h["Cache-Control"] {
+		for _, part := range splitDirectives(line) {
+			part = textproto.TrimString(part)
+			if part == "" {
+				continue
+			}
+			name, value := part, ""
+			if i := strings.IndexByte(part, '='); i >= 0 {
+				name = textproto.TrimString(part[:i])
+				value = textproto.TrimString(part[i+1:])
+				if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+					value = value[1 : len(value)-1]
+				}
+			}
+			cc[strings.ToLower(name)] = value
+		}
+	}
+	return cc
+}
+
+// splitDirectives splits s on the commas that are not inside a
+// quoted string.
+func splitDirectives(s string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			if quoted {
+				i++
+			}
+		case ',':
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func (cc cacheControl) has(name string) bool {
+	_, ok := cc[name]
+	return ok
+}
+
+// seconds returns the delta-seconds argument of the named directive.
+func (cc cacheControl) seconds(name string) (time.Duration, bool) {
+	v, ok := cc[name]
+	if !ok {
+		return 0, false
+	}
+	return parseSeconds(v)
+}
+
+// parseSeconds parses a delta-seconds value (RFC 7234, Section 1.2.1).
+// Values too large to be represented are capped.
+func parseSeconds(v string) (time.Duration, bool) {
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		if ne, ok := err.(*strconv.NumError); !ok || ne.Err != strconv.ErrRange {
+			return 0, false
+		}
+		n = 1<<64 - 1
+	}
+	const max = 1<<63 - 1
+	if n > max/uint64(time.Second) {
+		return max, true
+	}
+	return time.Duration(n) * time.Second, true
+}
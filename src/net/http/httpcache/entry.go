@@ -0,0 +1,221 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An entry is a stored response.
+type entry struct {
+	resp *http.Response // without Body
+	body []byte
+
+	// reqHeader holds the header fields of the request that
+	// produced resp which are named by resp's Vary header.
+	reqHeader http.Header
+
+	requestTime  time.Time // when the request was sent
+	responseTime time.Time // when the response was received
+}
+
+// An entry is encoded as three parts: a MIME header with the times at
+// which the request was sent and the response received, the varying
+// request header fields as a second MIME header, and finally the
+// response in HTTP/1.1 wire format.
+
+func (e *entry) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	meta := http.Header{
+		"Request-Time":  {strconv.FormatInt(e.requestTime.UnixNano(), 10)},
+		"Response-Time": {strconv.FormatInt(e.responseTime.UnixNano(), 10)},
+	}
+	if err := meta.Write(&buf); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\r\n")
+	if err := e.reqHeader.Write(&buf); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\r\n")
+	resp := *e.resp
+	resp.Body = ioutil.NopCloser(bytes.NewReader(e.body))
+	resp.ContentLength = int64(len(e.body))
+	resp.TransferEncoding = nil
+	resp.Trailer = nil
+	resp.Close = false
+	if err := resp.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var errBadEntry = errors.New("httpcache: malformed cache entry")
+
+func decodeEntry(b []byte, req *http.Request) (*entry, error) {
+	br := bufio.NewReader(bytes.NewReader(b))
+	tr := textproto.NewReader(br)
+	meta, err := tr.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	reqHeader, err := tr.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	reqTime, err1 := strconv.ParseInt(meta.Get("Request-Time"), 10, 64)
+	respTime, err2 := strconv.ParseInt(meta.Get("Response-Time"), 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, errBadEntry
+	}
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = nil
+	return &entry{
+		resp:         resp,
+		body:         body,
+		reqHeader:    http.Header(reqHeader),
+		requestTime:  time.Unix(0, reqTime),
+		responseTime: time.Unix(0, respTime),
+	}, nil
+}
+
+// varyFields returns the request header field names listed in the Vary
+// header of h, canonicalized, and whether one of them is "*".
+func varyFields(h http.Header) (names []string, star bool) {
+	for _, line := range h["Vary"] {
+		for _, name := range strings.Split(line, ",") {
+			name = textproto.TrimString(name)
+			switch name {
+			case "":
+			case "*":
+				star = true
+			default:
+				names = append(names, textproto.CanonicalMIMEHeaderKey(name))
+			}
+		}
+	}
+	return names, star
+}
+
+// matches reports whether the stored response can be used for req,
+// according to the stored response's Vary header.
+func (e *entry) matches(req *http.Request) bool {
+	names, star := varyFields(e.resp.Header)
+	if star {
+		return false
+	}
+	for _, name := range names {
+		if strings.Join(req.Header[name], ",") != strings.Join(e.reqHeader[name], ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// date returns the value of the Date header, or the time the response
+// was received if it is missing or invalid.
+func (e *entry) date() time.Time {
+	if t, err := http.ParseTime(e.resp.Header.Get("Date")); err == nil {
+		return t
+	}
+	return e.responseTime
+}
+
+// age returns the current age of the stored response, as defined in
+// RFC 7234, Section 4.2.3.
+func (e *entry) age(now time.Time) time.Duration {
+	apparentAge := e.responseTime.Sub(e.date())
+	if apparentAge < 0 {
+		apparentAge = 0
+	}
+	ageValue, _ := parseSeconds(e.resp.Header.Get("Age"))
+	correctedAge := ageValue + e.responseTime.Sub(e.requestTime)
+	initialAge := apparentAge
+	if correctedAge > initialAge {
+		initialAge = correctedAge
+	}
+	return initialAge + now.Sub(e.responseTime)
+}
+
+// Maximum freshness lifetime assigned to responses without explicit
+// expiration information.
+const maxHeuristicLifetime = 24 * time.Hour
+
+// freshnessLifetime returns how long the stored response stays fresh,
+// as defined in RFC 7234, Section 4.2.1.
+func (e *entry) freshnessLifetime() time.Duration {
+	h := e.resp.Header
+	if d, ok := parseCacheControl(h).seconds("max-age"); ok {
+		return d
+	}
+	if v, ok := h["Expires"]; ok {
+		t, err := http.ParseTime(strings.Join(v, ""))
+		if err != nil {
+			// Invalid dates represent a time in the past.
+			return 0
+		}
+		return t.Sub(e.date())
+	}
+	if heuristicallyCacheable[e.resp.StatusCode] {
+		// Use 10% of the time since the last modification, as
+		// suggested by RFC 7234, Section 4.2.2.
+		if lm, err := http.ParseTime(h.Get("Last-Modified")); err == nil {
+			d := e.date().Sub(lm) / 10
+			if d > maxHeuristicLifetime {
+				d = maxHeuristicLifetime
+			}
+			if d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
+
+// update refreshes the stored response with the header of a 304 Not
+// Modified response that validated it, as described in RFC 7234,
+// Section 4.3.4.
+func (e *entry) update(h http.Header, requestTime, responseTime time.Time) {
+	header := e.resp.Header.Clone()
+	for k, vv := range h {
+		switch k {
+		case "Content-Length", "Transfer-Encoding", "Content-Encoding":
+			continue
+		}
+		header[k] = vv
+	}
+	resp := *e.resp
+	resp.Header = header
+	e.resp = &resp
+	e.requestTime = requestTime
+	e.responseTime = responseTime
+}
+
+// response returns a new response for req from the stored one.
+func (e *entry) response(req *http.Request, now time.Time) *http.Response {
+	resp := *e.resp
+	resp.Header = e.resp.Header.Clone()
+	resp.Header.Set("Age", strconv.FormatInt(int64(e.age(now)/time.Second), 10))
+	resp.Body = ioutil.NopCloser(bytes.NewReader(e.body))
+	resp.ContentLength = int64(len(e.body))
+	resp.TransferEncoding = nil
+	resp.Request = req
+	return &resp
+}
@@ -0,0 +1,412 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httpcache implements an HTTP cache as an http.RoundTripper.
+//
+// The cache follows the rules of RFC 7234 for a private cache, that is
+// a cache used by a single client. It stores responses to GET requests
+// that are cacheable according to their Cache-Control, Expires and
+// Last-Modified headers, and serves them while they are fresh. Stale
+// responses are revalidated with conditional requests built from their
+// ETag and Last-Modified headers. Responses with a Vary header are only
+// used for requests that match the request they were stored for.
+//
+// The stale-while-revalidate Cache-Control extension (RFC 5861) is
+// supported: within the time it allows, a stale response is returned
+// immediately while it is revalidated in the background.
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport is an http.RoundTripper that serves responses from a Cache
+// when possible, and otherwise sends requests with an underlying
+// RoundTripper and stores the responses.
+//
+// Responses served from the cache have an Age header. A response is
+// stored once its body has been read to the end.
+type Transport struct {
+	// Transport is used to send requests to the origin server.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// Cache stores the responses.
+	Cache Cache
+
+	// MaxEntrySize is the size in bytes of the largest response body
+	// that is stored. Larger responses are passed through, and are
+	// only buffered up to the limit.
+	// If zero, DefaultMaxEntrySize is used.
+	MaxEntrySize int64
+
+	mu           sync.Mutex
+	revalidating map[string]bool // keys being revalidated in the background
+}
+
+// DefaultMaxEntrySize is the default value of Transport.MaxEntrySize.
+const DefaultMaxEntrySize = 10 << 20
+
+// NewTransport returns a Transport that stores responses in c and sends
+// requests with http.DefaultTransport.
+func NewTransport(c Cache) *Transport {
+	return &Transport{Cache: c}
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) maxEntrySize() int64 {
+	if t.MaxEntrySize != 0 {
+		return t.MaxEntrySize
+	}
+	return DefaultMaxEntrySize
+}
+
+// cacheKey returns the key under which the response to a GET request
+// for u is stored.
+func cacheKey(u *url.URL) string {
+	u2 := *u
+	u2.Fragment = ""
+	return u2.String()
+}
+
+// Status codes that are cacheable by default (RFC 7231, Section 6.1),
+// except for 206 Partial Content, which this cache does not store.
+var heuristicallyCacheable = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// Request header fields that make a request conditional. Such requests
+// are passed through, as their responses depend on the client's own
+// stored representation.
+var conditionalHeaders = []string{
+	"If-Match",
+	"If-None-Match",
+	"If-Modified-Since",
+	"If-Unmodified-Since",
+	"If-Range",
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" && req.Method != "" {
+		resp, err := t.transport().RoundTrip(req)
+		if err == nil && !isSafe(req.Method) && resp.StatusCode < 400 {
+			t.invalidate(req, resp)
+		}
+		return resp, err
+	}
+	if req.Header.Get("Range") != "" || isConditional(req) {
+		return t.transport().RoundTrip(req)
+	}
+	reqCC := parseCacheControl(req.Header)
+	if reqCC.has("no-store") {
+		return t.transport().RoundTrip(req)
+	}
+	if len(req.Header["Cache-Control"]) == 0 && hasToken(req.Header["Pragma"], "no-cache") {
+		reqCC["no-cache"] = ""
+	}
+
+	key := cacheKey(req.URL)
+	e := t.load(key, req)
+	if e == nil {
+		if reqCC.has("only-if-cached") {
+			return gatewayTimeout(req), nil
+		}
+		return t.fetch(req, key, reqCC)
+	}
+
+	now := time.Now()
+	age := e.age(now)
+	lifetime := e.freshnessLifetime()
+	respCC := parseCacheControl(e.resp.Header)
+	if !reqCC.has("no-cache") && !respCC.has("no-cache") {
+		fresh := age < lifetime
+		if d, ok := reqCC.seconds("max-age"); ok && age > d {
+			fresh = false
+		}
+		if d, ok := reqCC.seconds("min-fresh"); ok && lifetime-age < d {
+			fresh = false
+		}
+		if fresh {
+			return e.response(req, now), nil
+		}
+		staleness := age - lifetime
+		if v, ok := reqCC["max-stale"]; ok && staleness >= 0 && !respCC.has("must-revalidate") {
+			if d, ok := parseSeconds(v); v == "" || (ok && staleness <= d) {
+				return e.response(req, now), nil
+			}
+		}
+		if d, ok := respCC.seconds("stale-while-revalidate"); ok && staleness >= 0 && staleness <= d && !respCC.has("must-revalidate") {
+			resp := e.response(req, now)
+			t.revalidateInBackground(req, key, e)
+			return resp, nil
+		}
+	}
+	if reqCC.has("only-if-cached") {
+		return gatewayTimeout(req), nil
+	}
+	return t.revalidate(req, key, reqCC, e)
+}
+
+// load returns the entry stored for req, or nil if there is no stored
+// response that can be used for it.
+func (t *Transport) load(key string, req *http.Request) *entry {
+	b, ok := t.Cache.Get(key)
+	if !ok {
+		return nil
+	}
+	e, err := decodeEntry(b, req)
+	if err != nil {
+		t.Cache.Delete(key)
+		return nil
+	}
+	if !e.matches(req) {
+		return nil
+	}
+	return e
+}
+
+// fetch sends req and arranges for the response to be stored if it is
+// cacheable.
+func (t *Transport) fetch(req *http.Request, key string, reqCC cacheControl) (*http.Response, error) {
+	requestTime := time.Now()
+	resp, err := t.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.maybeStore(req, key, reqCC, resp, requestTime, time.Now())
+	return resp, nil
+}
+
+// revalidate sends req to the origin server as a conditional request
+// using the validators of e. If the server reports that e is still
+// valid, e is updated and returned.
+func (t *Transport) revalidate(req *http.Request, key string, reqCC cacheControl, e *entry) (*http.Response, error) {
+	creq := req.Clone(req.Context())
+	if etag := e.resp.Header.Get("Etag"); etag != "" {
+		creq.Header.Set("If-None-Match", etag)
+	}
+	if lm := e.resp.Header.Get("Last-Modified"); lm != "" {
+		creq.Header.Set("If-Modified-Since", lm)
+	}
+	requestTime := time.Now()
+	resp, err := t.transport().RoundTrip(creq)
+	if err != nil {
+		return nil, err
+	}
+	responseTime := time.Now()
+	if resp.StatusCode != http.StatusNotModified {
+		resp.Request = req
+		t.maybeStore(req, key, reqCC, resp, requestTime, responseTime)
+		return resp, nil
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	e.update(resp.Header, requestTime, responseTime)
+	if !reqCC.has("no-store") && !parseCacheControl(e.resp.Header).has("no-store") {
+		t.store(key, e)
+	}
+	return e.response(req, responseTime), nil
+}
+
+// revalidateInBackground revalidates e without blocking the caller,
+// unless a revalidation for key is already running.
+func (t *Transport) revalidateInBackground(req *http.Request, key string, e *entry) {
+	t.mu.Lock()
+	if t.revalidating[key] {
+		t.mu.Unlock()
+		return
+	}
+	if t.revalidating == nil {
+		t.revalidating = make(map[string]bool)
+	}
+	t.revalidating[key] = true
+	t.mu.Unlock()
+
+	// The caller's context may end as soon as it gets the stale
+	// response, so do not use it.
+	req = req.Clone(context.Background())
+	go func() {
+		defer func() {
+			t.mu.Lock()
+			delete(t.revalidating, key)
+			t.mu.Unlock()
+		}()
+		resp, err := t.revalidate(req, key, cacheControl{}, e)
+		if err != nil {
+			return
+		}
+		// Read the body so that a new response is stored.
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+}
+
+// maybeStore arranges for resp to be stored once its body has been read
+// if it is cacheable.
+func (t *Transport) maybeStore(req *http.Request, key string, reqCC cacheControl, resp *http.Response, requestTime, responseTime time.Time) {
+	if !cacheable(reqCC, resp) || resp.ContentLength > t.maxEntrySize() {
+		return
+	}
+	names, _ := varyFields(resp.Header)
+	reqHeader := make(http.Header)
+	for _, name := range names {
+		if vv, ok := req.Header[name]; ok {
+			reqHeader[name] = vv
+		}
+	}
+	stored := *resp
+	stored.Header = resp.Header.Clone()
+	stored.Body = nil
+	e := &entry{
+		resp:         &stored,
+		reqHeader:    reqHeader,
+		requestTime:  requestTime,
+		responseTime: responseTime,
+	}
+	resp.Body = &cachingBody{
+		ReadCloser: resp.Body,
+		limit:      t.maxEntrySize(),
+		onEOF: func(body []byte) {
+			e.body = body
+			t.store(key, e)
+		},
+	}
+}
+
+func (t *Transport) store(key string, e *entry) {
+	b, err := e.encode()
+	if err != nil {
+		return
+	}
+	t.Cache.Set(key, b)
+}
+
+// invalidate removes the stored responses for the URLs that an unsafe
+// request changed, as described in RFC 7234, Section 4.4.
+func (t *Transport) invalidate(req *http.Request, resp *http.Response) {
+	t.Cache.Delete(cacheKey(req.URL))
+	for _, h := range []string{"Location", "Content-Location"} {
+		u, err := req.URL.Parse(resp.Header.Get(h))
+		if err != nil || resp.Header.Get(h) == "" || u.Host != req.URL.Host {
+			continue
+		}
+		t.Cache.Delete(cacheKey(u))
+	}
+}
+
+// cacheable reports whether resp may be stored.
+func cacheable(reqCC cacheControl, resp *http.Response) bool {
+	if reqCC.has("no-store") || !heuristicallyCacheable[resp.StatusCode] {
+		return false
+	}
+	cc := parseCacheControl(resp.Header)
+	if cc.has("no-store") {
+		return false
+	}
+	if _, star := varyFields(resp.Header); star {
+		return false
+	}
+	// Only store responses that can be used without revalidation
+	// for some time, or that can be revalidated.
+	return cc.has("max-age") || cc.has("public") || len(resp.Header["Expires"]) > 0 ||
+		resp.Header.Get("Etag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+func isSafe(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE":
+		return true
+	}
+	return false
+}
+
+func isConditional(req *http.Request) bool {
+	for _, h := range conditionalHeaders {
+		if req.Header.Get(h) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasToken reports whether one of the comma-separated lists in values
+// contains token, ignoring case.
+func hasToken(values []string, token string) bool {
+	for _, v := range values {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// gatewayTimeout returns the response to a request with the
+// only-if-cached directive that can't be served from the cache.
+func gatewayTimeout(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:     "504 Gateway Timeout",
+		StatusCode: http.StatusGatewayTimeout,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		Request:    req,
+	}
+}
+
+// cachingBody is a response body that calls onEOF with the complete
+// body once it has been read to the end, unless the body is larger
+// than limit.
+type cachingBody struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	limit int64
+	onEOF func(body []byte)
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.onEOF == nil {
+		return n, err
+	}
+	if int64(b.buf.Len()+n) > b.limit {
+		// Too large to store: stop buffering.
+		b.onEOF = nil
+		b.buf = bytes.Buffer{}
+		return n, err
+	}
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.onEOF(b.buf.Bytes())
+		b.onEOF = nil
+	}
+	return n, err
+}
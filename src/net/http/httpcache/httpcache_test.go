@@ -0,0 +1,282 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpcache
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cacheTest is a server whose handler counts requests, and a client
+// that goes through a Transport with a MemoryCache.
+type cacheTest struct {
+	t        *testing.T
+	ts       *httptest.Server
+	tr       *Transport
+	requests int32
+}
+
+func newCacheTest(t *testing.T, h http.HandlerFunc) *cacheTest {
+	ct := &cacheTest{t: t}
+	ct.ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&ct.requests, 1)
+		h(w, r)
+	}))
+	ct.tr = &Transport{Transport: ct.ts.Client().Transport, Cache: NewMemoryCache(1 << 20)}
+	return ct
+}
+
+func (ct *cacheTest) close() { ct.ts.Close() }
+
+func (ct *cacheTest) do(method string, header http.Header) (*http.Response, string) {
+	ct.t.Helper()
+	req, _ := http.NewRequest(method, ct.ts.URL, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	res, err := ct.tr.RoundTrip(req)
+	if err != nil {
+		ct.t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		ct.t.Fatal(err)
+	}
+	return res, string(body)
+}
+
+func (ct *cacheTest) get() (*http.Response, string) { return ct.do("GET", nil) }
+
+func (ct *cacheTest) wantRequests(want int32) {
+	ct.t.Helper()
+	if got := atomic.LoadInt32(&ct.requests); got != want {
+		ct.t.Errorf("server saw %d requests; want %d", got, want)
+	}
+}
+
+func TestFresh(t *testing.T) {
+	ct := newCacheTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		fmt.Fprint(w, "hello")
+	})
+	defer ct.close()
+	for i := 0; i < 3; i++ {
+		res, body := ct.get()
+		if body != "hello" {
+			t.Errorf("body = %q", body)
+		}
+		if _, ok := res.Header["Age"]; ok != (i > 0) {
+			t.Errorf("request %d: Age header = %q", i, res.Header.Get("Age"))
+		}
+	}
+	ct.wantRequests(1)
+}
+
+func TestRevalidate(t *testing.T) {
+	for _, validator := range []string{"Etag", "Last-Modified"} {
+		t.Run(validator, func(t *testing.T) {
+			const value = `"v1"`
+			lastModified := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+			conditional := map[string]string{"Etag": "If-None-Match", "Last-Modified": "If-Modified-Since"}[validator]
+			ct := newCacheTest(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "no-cache")
+				w.Header().Set("X-Served", time.Now().String())
+				v := value
+				if validator == "Last-Modified" {
+					v = lastModified
+				}
+				w.Header().Set(validator, v)
+				if r.Header.Get(conditional) == v {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				fmt.Fprint(w, "hello")
+			})
+			defer ct.close()
+			res1, _ := ct.get()
+			res2, body := ct.get()
+			if res2.StatusCode != 200 || body != "hello" {
+				t.Errorf("revalidated response = %v %q", res2.Status, body)
+			}
+			if res1.Header.Get("X-Served") == res2.Header.Get("X-Served") {
+				t.Errorf("stored header not updated by 304 response")
+			}
+			ct.wantRequests(2)
+		})
+	}
+}
+
+func TestVary(t *testing.T) {
+	ct := newCacheTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		fmt.Fprint(w, r.Header.Get("Accept-Language"))
+	})
+	defer ct.close()
+	for _, test := range []struct {
+		lang         string
+		wantRequests int32
+	}{
+		{"en", 1},
+		{"en", 1},
+		{"fr", 2},
+		{"fr", 2},
+	} {
+		_, body := ct.do("GET", http.Header{"Accept-Language": {test.lang}})
+		if body != test.lang {
+			t.Errorf("body = %q; want %q", body, test.lang)
+		}
+		ct.wantRequests(test.wantRequests)
+	}
+}
+
+func TestNoStore(t *testing.T) {
+	ct := newCacheTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store, max-age=60")
+	})
+	defer ct.close()
+	ct.get()
+	ct.get()
+	ct.wantRequests(2)
+}
+
+func TestMaxEntrySize(t *testing.T) {
+	for _, chunked := range []bool{false, true} {
+		for _, size := range []int{50, 51} {
+			body := strings.Repeat("x", size)
+			ct := newCacheTest(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "max-age=60")
+				if chunked {
+					w.(http.Flusher).Flush()
+				}
+				fmt.Fprint(w, body)
+			})
+			ct.tr.MaxEntrySize = 50
+			for i := 0; i < 2; i++ {
+				if _, got := ct.get(); got != body {
+					t.Errorf("chunked=%v, size %d: body = %q; want %q", chunked, size, got, body)
+				}
+			}
+			want := int32(1)
+			if size > 50 {
+				want = 2
+			}
+			if got := atomic.LoadInt32(&ct.requests); got != want {
+				t.Errorf("chunked=%v, size %d: server saw %d requests; want %d", chunked, size, got, want)
+			}
+			ct.close()
+		}
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	var version int32
+	ct := newCacheTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=0, stale-while-revalidate=60")
+		fmt.Fprint(w, atomic.AddInt32(&version, 1))
+	})
+	defer ct.close()
+	if _, body := ct.get(); body != "1" {
+		t.Fatalf("first body = %q; want 1", body)
+	}
+	if _, body := ct.get(); body != "1" {
+		t.Fatalf("stale body = %q; want 1", body)
+	}
+	// Wait for the background revalidation to store the new version.
+	for i := 0; ; i++ {
+		if _, body := ct.do("GET", http.Header{"Cache-Control": {"max-stale"}}); body == "2" {
+			break
+		}
+		if i == 100 {
+			t.Fatal("response was not revalidated in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUnsafeMethodInvalidates(t *testing.T) {
+	ct := newCacheTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+	})
+	defer ct.close()
+	ct.get()
+	ct.get()
+	ct.do("POST", nil)
+	ct.get()
+	ct.wantRequests(3)
+}
+
+func TestOnlyIfCached(t *testing.T) {
+	ct := newCacheTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+	})
+	defer ct.close()
+	onlyIfCached := http.Header{"Cache-Control": {"only-if-cached"}}
+	if res, _ := ct.do("GET", onlyIfCached); res.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("status = %v; want 504", res.StatusCode)
+	}
+	ct.get()
+	if res, _ := ct.do("GET", onlyIfCached); res.StatusCode != http.StatusOK {
+		t.Errorf("status = %v; want 200", res.StatusCode)
+	}
+	ct.wantRequests(1)
+}
+
+func TestParseCacheControl(t *testing.T) {
+	h := http.Header{"Cache-Control": {`max-age=60, No-Cache="Set-Cookie, Foo"`, "private"}}
+	want := cacheControl{"max-age": "60", "no-cache": "Set-Cookie, Foo", "private": ""}
+	if got := parseCacheControl(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCacheControl = %q; want %q", got, want)
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	c := NewMemoryCache(10)
+	c.Set("a", []byte("1234"))
+	c.Set("b", []byte("1234"))
+	c.Get("a")
+	c.Set("c", []byte("1234"))
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("least recently used entry was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("entry %q was evicted", key)
+		}
+	}
+	c.Set("d", []byte("too large to be stored"))
+	if _, ok := c.Get("d"); ok {
+		t.Errorf("entry larger than the cache was stored")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	c := NewDiskCache(dir + "/cache")
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("Get succeeded on empty cache")
+	}
+	c.Set("k", []byte("value"))
+	if v, ok := c.Get("k"); !ok || string(v) != "value" {
+		t.Errorf("Get = %q, %v; want %q, true", v, ok, "value")
+	}
+	c.Delete("k")
+	if _, ok := c.Get("k"); ok {
+		t.Error("Get succeeded after Delete")
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpcache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// A Cache stores the encoded responses kept by a Transport.
+// Implementations must be safe for concurrent use.
+//
+// Caching is best effort: a Cache may drop entries at any time, and
+// failures to store an entry are not reported.
+type Cache interface {
+	// Get returns the value stored for key, if any.
+	Get(key string) (value []byte, ok bool)
+
+	// Set stores value for key, replacing any previous value.
+	// The Cache must not modify value, and may retain it.
+	Set(key string, value []byte)
+
+	// Delete removes the value stored for key, if any.
+	Delete(key string)
+}
+
+// A MemoryCache is a Cache that keeps entries in memory. Once the total
+// size of the entries exceeds its limit, it evicts the least recently
+// used ones.
+type MemoryCache struct {
+	mu      sync.Mutex
+	maxSize int64
+	size    int64
+	ll      *list.List // of *memoryEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key   string
+	value []byte
+}
+
+func (e *memoryEntry) size() int64 { return int64(len(e.key) + len(e.value)) }
+
+// NewMemoryCache returns a MemoryCache holding at most maxSize bytes of
+// keys and values.
+func NewMemoryCache(maxSize int64) *MemoryCache {
+	return &MemoryCache{
+		maxSize: maxSize,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*memoryEntry).value, true
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeLocked(key)
+	e := &memoryEntry{key, value}
+	if e.size() > c.maxSize {
+		return
+	}
+	c.entries[key] = c.ll.PushFront(e)
+	c.size += e.size()
+	for c.size > c.maxSize {
+		c.removeLocked(c.ll.Back().Value.(*memoryEntry).key)
+	}
+}
+
+// Delete implements Cache.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeLocked(key)
+}
+
+func (c *MemoryCache) removeLocked(key string) {
+	el, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	c.ll.Remove(el)
+	delete(c.entries, key)
+	c.size -= el.Value.(*memoryEntry).size()
+}
+
+// A DiskCache is a Cache that stores each entry in its own file in a
+// directory. It does not limit the size of the directory.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a DiskCache storing entries in dir. The directory
+// is created when the first entry is stored, if it does not exist.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements Cache.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	value, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set implements Cache.
+func (c *DiskCache) Set(key string, value []byte) {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return
+	}
+	// Write to a temporary file first, so that concurrent readers
+	// never see a partial entry.
+	f, err := ioutil.TempFile(c.dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(value)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// Delete implements Cache.
+func (c *DiskCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"net/http/internal"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A FakeClock is a clock for tests whose time only changes when it is
+// advanced. Used as the Clock of a Network, it measures the latency
+// and deadlines of the network's connections, and the timeouts of the
+// http.Servers and http.Transports using them, such as
+// Transport.IdleConnTimeout and Server.IdleTimeout.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*FakeTimer // active timers
+}
+
+// NewFakeClock returns a FakeClock set to the time now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance advances the clock by d, calling the functions of the timers
+// that expire on the way in the order of their expiration times, each
+// with the clock set to its time.
+//
+// Timer functions are called in the goroutine calling Advance, but the
+// work they trigger, such as closing a connection whose deadline has
+// passed, may continue in other goroutines after Advance returns.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	for {
+		sort.SliceStable(c.timers, func(i, j int) bool {
+			return c.timers[i].when.Before(c.timers[j].when)
+		})
+		if len(c.timers) == 0 || c.timers[0].when.After(end) {
+			break
+		}
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		if t.when.After(c.now) {
+			c.now = t.when
+		}
+		c.mu.Unlock()
+		t.f()
+		c.mu.Lock()
+	}
+	c.now = end
+	c.mu.Unlock()
+}
+
+// AfterFunc waits for the clock to advance by d and then calls f, as
+// time.AfterFunc does for the system clock.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) *FakeTimer {
+	t := &FakeTimer{c: c, f: f}
+	t.Reset(d)
+	return t
+}
+
+// A FakeTimer is a timer of a FakeClock.
+type FakeTimer struct {
+	c    *FakeClock
+	f    func()
+	when time.Time // guarded by c.mu
+}
+
+// Stop prevents the timer from firing. It reports whether the timer
+// was active.
+func (t *FakeTimer) Stop() bool {
+	c := t.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, t1 := range c.timers {
+		if t1 == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Reset changes the timer to expire after duration d, measured from
+// the current time of its clock. It reports whether the timer was
+// active.
+func (t *FakeTimer) Reset(d time.Duration) bool {
+	active := t.Stop()
+	c := t.c
+	c.mu.Lock()
+	t.when = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	c.mu.Unlock()
+	if d <= 0 {
+		go c.Advance(0)
+	}
+	return active
+}
+
+// fakeClock adapts a FakeClock to internal.Clock.
+type fakeClock struct {
+	c *FakeClock
+}
+
+func (c fakeClock) Now() time.Time { return c.c.Now() }
+
+func (c fakeClock) AfterFunc(d time.Duration, f func()) internal.Timer {
+	return c.c.AfterFunc(d, f)
+}
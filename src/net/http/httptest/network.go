@@ -0,0 +1,437 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/internal"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A Network is an in-memory network for tests, on which listeners
+// accept connections from dialers without using the operating system.
+// Its connections deliver data with a configurable latency and
+// bandwidth, and can be made to fail, so that tests can exercise
+// timeouts and error handling deterministically, especially when time
+// is measured by a FakeClock.
+//
+// Addresses on a Network have the form "host:port". A Network's fields
+// must not be changed after it is first used.
+type Network struct {
+	// Latency is the time it takes for the data written on a
+	// connection to reach the peer.
+	Latency time.Duration
+
+	// Bandwidth, if positive, is the rate in bytes per second at
+	// which the data written on a connection in each direction
+	// reaches the peer. Writes never block; data that the link
+	// can't carry yet is buffered.
+	Bandwidth int64
+
+	// Fault, if non-nil, is called before each write of p on a
+	// connection from the address src to dst. If it returns a
+	// non-nil error, the write fails with that error and the
+	// connection is reset: further reads and writes at both ends
+	// fail.
+	Fault func(src, dst net.Addr, p []byte) error
+
+	// Clock, if non-nil, measures time on the network, including
+	// latency, bandwidth and connection deadlines. If nil, the
+	// system clock is used.
+	//
+	// The timeouts of http.Servers and http.Transports are also
+	// measured by Clock on connections of the network, except for
+	// servers of TLS connections.
+	Clock *FakeClock
+
+	mu        sync.Mutex
+	listeners map[string]*netListener
+	lastPort  int
+}
+
+// clock returns the internal.Clock of the network.
+func (n *Network) clock() internal.Clock {
+	if n.Clock != nil {
+		return fakeClock{n.Clock}
+	}
+	return internal.RealClock
+}
+
+// port returns an unused port number. n.mu must be held.
+func (n *Network) port() string {
+	if n.lastPort < 10000 {
+		n.lastPort = 10000
+	}
+	n.lastPort++
+	return strconv.Itoa(n.lastPort)
+}
+
+// Listen returns a listener for the address addr. If the host in addr
+// is empty, 127.0.0.1 is used; if the port is empty or 0, an unused
+// port is chosen.
+func (n *Network) Listen(addr string) (net.Listener, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, &net.OpError{Op: "listen", Net: netName, Err: err}
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if port == "" || port == "0" {
+		port = n.port()
+	}
+	a := netAddr(net.JoinHostPort(host, port))
+	if _, ok := n.listeners[string(a)]; ok {
+		return nil, &net.OpError{Op: "listen", Net: netName, Addr: a, Err: errors.New("address already in use")}
+	}
+	l := &netListener{
+		n:     n,
+		addr:  a,
+		conns: make(chan *netConn, 64),
+		done:  make(chan struct{}),
+	}
+	if n.listeners == nil {
+		n.listeners = make(map[string]*netListener)
+	}
+	n.listeners[string(a)] = l
+	return l, nil
+}
+
+// DialContext connects to the listener for the address addr, which
+// must be in use. The network argument is ignored. DialContext has the
+// signature of http.Transport.DialContext.
+func (n *Network) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	n.mu.Lock()
+	l := n.listeners[addr]
+	local := netAddr(net.JoinHostPort("127.0.0.1", n.port()))
+	n.mu.Unlock()
+	remote := netAddr(addr)
+	if l == nil {
+		return nil, &net.OpError{Op: "dial", Net: netName, Source: local, Addr: remote, Err: errors.New("connection refused")}
+	}
+	clock := n.clock()
+	p1 := newPipe(n, clock) // client to server
+	p2 := newPipe(n, clock) // server to client
+	client := &netConn{n: n, local: local, remote: remote, r: p2, w: p1}
+	server := &netConn{n: n, local: remote, remote: local, r: p1, w: p2}
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		return nil, &net.OpError{Op: "dial", Net: netName, Source: local, Addr: remote, Err: errors.New("connection refused")}
+	case <-ctx.Done():
+		return nil, &net.OpError{Op: "dial", Net: netName, Source: local, Addr: remote, Err: ctx.Err()}
+	}
+}
+
+// NewServer starts and returns a new Server listening on the network.
+// The caller should call Close when finished, to shut it down.
+func (n *Network) NewServer(handler http.Handler) *Server {
+	ts := n.NewUnstartedServer(handler)
+	ts.Start()
+	return ts
+}
+
+// NewUnstartedServer returns a new Server listening on the network
+// but doesn't start it. The client returned by its Client method
+// connects through the network.
+//
+// After changing its configuration, the caller should call Start or
+// StartTLS.
+//
+// The caller should call Close when finished, to shut it down.
+func (n *Network) NewUnstartedServer(handler http.Handler) *Server {
+	l, err := n.Listen(":0")
+	if err != nil {
+		panic("httptest: " + err.Error())
+	}
+	return &Server{
+		Listener: l,
+		Config:   &http.Server{Handler: handler},
+		network:  n,
+	}
+}
+
+// netName is the name of the network in the addresses and errors of
+// a Network.
+const netName = "memory"
+
+type netAddr string
+
+func (a netAddr) Network() string { return netName }
+func (a netAddr) String() string  { return string(a) }
+
+type netListener struct {
+	n     *Network
+	addr  netAddr
+	conns chan *netConn // connections waiting to be accepted
+	done  chan struct{} // closed by Close
+
+	closeOnce sync.Once
+}
+
+func (l *netListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, &net.OpError{Op: "accept", Net: netName, Addr: l.addr, Err: errClosed}
+	}
+}
+
+func (l *netListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		l.n.mu.Lock()
+		delete(l.n.listeners, string(l.addr))
+		l.n.mu.Unlock()
+	})
+	return nil
+}
+
+func (l *netListener) Addr() net.Addr { return l.addr }
+
+var (
+	errClosed  = errors.New("use of closed network connection")
+	errReset   = errors.New("connection reset by peer")
+	errTimeout = timeoutError{}
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// A netConn is one end of a connection on a Network.
+type netConn struct {
+	n             *Network
+	local, remote netAddr
+	r             *pipe // data from the peer
+	w             *pipe // data to the peer
+}
+
+func (c *netConn) opError(op string, err error) error {
+	if err == io.EOF {
+		return err
+	}
+	return &net.OpError{Op: op, Net: netName, Source: c.local, Addr: c.remote, Err: err}
+}
+
+func (c *netConn) Read(b []byte) (int, error) {
+	n, err := c.r.read(b)
+	if err != nil {
+		err = c.opError("read", err)
+	}
+	return n, err
+}
+
+func (c *netConn) Write(b []byte) (int, error) {
+	if f := c.n.Fault; f != nil {
+		if err := f(c.local, c.remote, b); err != nil {
+			c.r.fail(errReset, errReset)
+			c.w.fail(errReset, errReset)
+			return 0, c.opError("write", err)
+		}
+	}
+	n, err := c.w.write(b)
+	if err != nil {
+		err = c.opError("write", err)
+	}
+	return n, err
+}
+
+// Close closes the connection. The peer reads the data written before
+// Close, followed by io.EOF; its writes fail.
+func (c *netConn) Close() error {
+	c.r.fail(errClosed, errReset)
+	c.w.closeWrite()
+	return nil
+}
+
+func (c *netConn) LocalAddr() net.Addr  { return c.local }
+func (c *netConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *netConn) SetDeadline(t time.Time) error {
+	c.r.setDeadline(t, true)
+	c.w.setDeadline(t, false)
+	return nil
+}
+
+func (c *netConn) SetReadDeadline(t time.Time) error {
+	c.r.setDeadline(t, true)
+	return nil
+}
+
+func (c *netConn) SetWriteDeadline(t time.Time) error {
+	c.w.setDeadline(t, false)
+	return nil
+}
+
+// Clock returns the clock that measures the connection's deadlines,
+// for net/http.
+func (c *netConn) Clock() internal.Clock {
+	return c.n.clock()
+}
+
+// A pipe carries data in one direction of a connection.
+type pipe struct {
+	clock     internal.Clock
+	latency   time.Duration
+	bandwidth int64
+
+	mu        sync.Mutex
+	wake      chan struct{} // closed and replaced when the state changes
+	chunks    []chunk       // data in transit or unread
+	next      time.Time     // when the link can carry more data
+	eof       bool          // whether the writer closed the pipe
+	rerr      error         // if non-nil, fails reads
+	werr      error         // if non-nil, fails writes
+	rdeadline time.Time
+	wdeadline time.Time
+}
+
+// A chunk is the data of a write, readable from a given time.
+type chunk struct {
+	b  []byte
+	at time.Time
+}
+
+func newPipe(n *Network, clock internal.Clock) *pipe {
+	return &pipe{
+		clock:     clock,
+		latency:   n.Latency,
+		bandwidth: n.Bandwidth,
+		wake:      make(chan struct{}),
+	}
+}
+
+// signalLocked wakes up the goroutines waiting for a change of state.
+// p.mu must be held.
+func (p *pipe) signalLocked() {
+	close(p.wake)
+	p.wake = make(chan struct{})
+}
+
+func (p *pipe) signal() {
+	p.mu.Lock()
+	p.signalLocked()
+	p.mu.Unlock()
+}
+
+func (p *pipe) read(b []byte) (int, error) {
+	for {
+		p.mu.Lock()
+		if p.rerr != nil {
+			p.mu.Unlock()
+			return 0, p.rerr
+		}
+		now := p.clock.Now()
+		var wait time.Time // when to check again, or zero
+		if len(p.chunks) > 0 {
+			c := &p.chunks[0]
+			if !now.Before(c.at) {
+				n := copy(b, c.b)
+				c.b = c.b[n:]
+				if len(c.b) == 0 {
+					p.chunks = p.chunks[1:]
+				}
+				p.mu.Unlock()
+				return n, nil
+			}
+			wait = c.at
+		} else if p.eof {
+			p.mu.Unlock()
+			return 0, io.EOF
+		}
+		if !p.rdeadline.IsZero() {
+			if !now.Before(p.rdeadline) {
+				p.mu.Unlock()
+				return 0, errTimeout
+			}
+			if wait.IsZero() || p.rdeadline.Before(wait) {
+				wait = p.rdeadline
+			}
+		}
+		wake := p.wake
+		p.mu.Unlock()
+
+		var t internal.Timer
+		if !wait.IsZero() {
+			t = p.clock.AfterFunc(wait.Sub(now), p.signal)
+		}
+		<-wake
+		if t != nil {
+			t.Stop()
+		}
+	}
+}
+
+func (p *pipe) write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.werr != nil {
+		return 0, p.werr
+	}
+	now := p.clock.Now()
+	if !p.wdeadline.IsZero() && !now.Before(p.wdeadline) {
+		return 0, errTimeout
+	}
+	sent := now
+	if p.next.After(sent) {
+		sent = p.next
+	}
+	if p.bandwidth > 0 {
+		sent = sent.Add(time.Duration(len(b)) * time.Second / time.Duration(p.bandwidth))
+	}
+	p.next = sent
+	p.chunks = append(p.chunks, chunk{append([]byte(nil), b...), sent.Add(p.latency)})
+	p.signalLocked()
+	return len(b), nil
+}
+
+// fail makes further reads and writes fail with the given errors.
+func (p *pipe) fail(rerr, werr error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rerr == nil {
+		p.rerr = rerr
+	}
+	if p.werr == nil {
+		p.werr = werr
+	}
+	p.chunks = nil
+	p.signalLocked()
+}
+
+// closeWrite closes the writing end of the pipe.
+func (p *pipe) closeWrite() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.eof = true
+	if p.werr == nil {
+		p.werr = errClosed
+	}
+	p.signalLocked()
+}
+
+func (p *pipe) setDeadline(t time.Time, read bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if read {
+		p.rdeadline = t
+	} else {
+		p.wdeadline = t
+	}
+	p.signalLocked()
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func dialPair(t *testing.T, n *Network) (client, server net.Conn) {
+	l, err := n.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	client, err = n.DialContext(context.Background(), "tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err = l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+func TestNetworkLatencyAndBandwidth(t *testing.T) {
+	t0 := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(t0)
+	n := &Network{Latency: time.Second, Bandwidth: 100, Clock: clock}
+	c, s := dialPair(t, n)
+	defer c.Close()
+	defer s.Close()
+
+	// 100 bytes take 1s to send and 1s to arrive.
+	c.Write(make([]byte, 100))
+	s.SetReadDeadline(t0.Add(1500 * time.Millisecond))
+	go clock.Advance(1500 * time.Millisecond)
+	buf := make([]byte, 200)
+	_, err := s.Read(buf)
+	if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Fatalf("Read before data arrived: err = %v; want timeout", err)
+	}
+
+	s.SetReadDeadline(time.Time{})
+	go clock.Advance(500 * time.Millisecond)
+	nr, err := s.Read(buf)
+	if nr != 100 || err != nil {
+		t.Fatalf("Read = %d, %v; want 100, nil", nr, err)
+	}
+	if got, want := clock.Now(), t0.Add(2*time.Second); !got.Equal(want) {
+		t.Errorf("data read at %v; want %v", got, want)
+	}
+}
+
+func TestNetworkClose(t *testing.T) {
+	c, s := dialPair(t, &Network{})
+	io.WriteString(c, "hello")
+	c.Close()
+	got, err := ioutil.ReadAll(s)
+	if string(got) != "hello" || err != nil {
+		t.Errorf("ReadAll = %q, %v; want hello, nil", got, err)
+	}
+	if _, err := s.Write([]byte("x")); err == nil {
+		t.Errorf("Write to closed peer succeeded")
+	}
+	if _, err := c.Read(make([]byte, 1)); err == nil {
+		t.Errorf("Read from closed conn succeeded")
+	}
+}
+
+func TestNetworkFault(t *testing.T) {
+	errInjected := errors.New("injected fault")
+	n := &Network{
+		Fault: func(src, dst net.Addr, p []byte) error {
+			if strings.Contains(string(p), "fail") {
+				return errInjected
+			}
+			return nil
+		},
+	}
+	c, s := dialPair(t, n)
+	defer c.Close()
+	defer s.Close()
+	if _, err := io.WriteString(c, "ok"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(c, "fail"); err == nil || !strings.Contains(err.Error(), errInjected.Error()) {
+		t.Errorf("Write error = %v; want %v", err, errInjected)
+	}
+	if _, err := s.Read(make([]byte, 10)); err == nil || !strings.Contains(err.Error(), "reset") {
+		t.Errorf("Read after fault: err = %v; want connection reset", err)
+	}
+}
+
+func TestNetworkDialRefused(t *testing.T) {
+	n := &Network{}
+	if _, err := n.DialContext(context.Background(), "tcp", "127.0.0.1:80"); err == nil {
+		t.Errorf("Dial to address without listener succeeded")
+	}
+	l, err := n.Listen("127.0.0.1:80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.Listen("127.0.0.1:80"); err == nil {
+		t.Errorf("second Listen on the same address succeeded")
+	}
+	l.Close()
+	if _, err := n.DialContext(context.Background(), "tcp", "127.0.0.1:80"); err == nil {
+		t.Errorf("Dial to closed listener succeeded")
+	}
+}
+
+func TestNetworkServer(t *testing.T) {
+	n := &Network{Latency: time.Millisecond}
+	for _, tls := range []bool{false, true} {
+		ts := n.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, r.RemoteAddr)
+		}))
+		if tls {
+			ts.StartTLS()
+		} else {
+			ts.Start()
+		}
+		res, err := ts.Client().Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if !strings.HasPrefix(string(got), "127.0.0.1:") {
+			t.Errorf("tls=%v: RemoteAddr = %q", tls, got)
+		}
+		ts.Close()
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	t0 := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(t0)
+	var fired []time.Duration
+	record := func() { fired = append(fired, c.Now().Sub(t0)) }
+	c.AfterFunc(3*time.Second, record)
+	c.AfterFunc(1*time.Second, record)
+	stopped := c.AfterFunc(2*time.Second, record)
+	reset := c.AfterFunc(time.Second, record)
+	if !stopped.Stop() {
+		t.Errorf("Stop of active timer returned false")
+	}
+	if !reset.Reset(5 * time.Second) {
+		t.Errorf("Reset of active timer returned false")
+	}
+	c.Advance(4 * time.Second)
+	if len(fired) != 2 || fired[0] != time.Second || fired[1] != 3*time.Second {
+		t.Errorf("after 4s, timers fired at %v; want [1s 3s]", fired)
+	}
+	c.Advance(time.Second)
+	if len(fired) != 3 || fired[2] != 5*time.Second {
+		t.Errorf("after 5s, timers fired at %v; want [1s 3s 5s]", fired)
+	}
+	if stopped.Stop() {
+		t.Errorf("Stop of stopped timer returned true")
+	}
+}
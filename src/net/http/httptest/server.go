@@ -22,7 +22,8 @@ import (
 )
 
 // A Server is an HTTP server listening on a system-chosen port on the
-// local loopback interface, for use in end-to-end HTTP tests.
+// local loopback interface, or on a Network, for use in end-to-end
+// HTTP tests.
 type Server struct {
 	URL      string // base URL of form http://ipaddr:port with no trailing slash
 	Listener net.Listener
@@ -50,6 +51,10 @@ type Server struct {
 	// client is configured for use with the server.
 	// Its transport is automatically closed when Close is called.
 	client *http.Client
+
+	// network is the Network the server listens on, if it was
+	// created by Network.NewUnstartedServer.
+	network *Network
 }
 
 func newLocalListener() net.Listener {
@@ -120,7 +125,7 @@ func (s *Server) Start() {
 		panic("Server already started")
 	}
 	if s.client == nil {
-		s.client = &http.Client{Transport: &http.Transport{}}
+		s.client = &http.Client{Transport: s.newTransport()}
 	}
 	s.URL = "http://" + s.Listener.Addr().String()
 	s.wrap()
@@ -137,7 +142,7 @@ func (s *Server) StartTLS() {
 		panic("Server already started")
 	}
 	if s.client == nil {
-		s.client = &http.Client{Transport: &http.Transport{}}
+		s.client = &http.Client{Transport: s.newTransport()}
 	}
 	cert, err := tls.X509KeyPair(internal.LocalhostCert, internal.LocalhostKey)
 	if err != nil {
@@ -162,17 +167,26 @@ func (s *Server) StartTLS() {
 	}
 	certpool := x509.NewCertPool()
 	certpool.AddCert(s.certificate)
-	s.client.Transport = &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs: certpool,
-		},
+	tr := s.newTransport()
+	tr.TLSClientConfig = &tls.Config{
+		RootCAs: certpool,
 	}
+	s.client.Transport = tr
 	s.Listener = tls.NewListener(s.Listener, s.TLS)
 	s.URL = "https://" + s.Listener.Addr().String()
 	s.wrap()
 	s.goServe()
 }
 
+// newTransport returns a new transport for the client of the server.
+func (s *Server) newTransport() *http.Transport {
+	tr := &http.Transport{}
+	if s.network != nil {
+		tr.DialContext = s.network.DialContext
+	}
+	return tr
+}
+
 // NewTLSServer starts and returns a new Server using TLS.
 // The caller should call Close when finished, to shut it down.
 func NewTLSServer(handler http.Handler) *Server {
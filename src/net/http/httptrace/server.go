@@ -0,0 +1,144 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptrace
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/textproto"
+	"reflect"
+)
+
+// unique type to prevent assignment.
+type serverEventContextKey struct{}
+
+// ContextServerTrace returns the ServerTrace associated with the
+// provided context. If none, it returns nil.
+func ContextServerTrace(ctx context.Context) *ServerTrace {
+	trace, _ := ctx.Value(serverEventContextKey{}).(*ServerTrace)
+	return trace
+}
+
+// WithServerTrace returns a new context based on the provided parent
+// ctx. An http.Server traces the connections it serves with the
+// hooks of the ServerTrace in their context, in addition to any
+// previous hooks registered with ctx. Any hooks defined in the
+// provided trace will be called first.
+//
+// To trace a Server's connections, return the context from the
+// Server's BaseContext or ConnContext function. The trace is also
+// available to Handlers through the context of their Request.
+func WithServerTrace(ctx context.Context, trace *ServerTrace) context.Context {
+	if trace == nil {
+		panic("nil trace")
+	}
+	old := ContextServerTrace(ctx)
+	trace.compose(old)
+	return context.WithValue(ctx, serverEventContextKey{}, trace)
+}
+
+// ServerTrace is a set of hooks to run at various stages of an
+// incoming HTTP connection and of the requests received on it. Any
+// particular hook may be nil.
+//
+// The hooks for a connection are called sequentially, from the
+// goroutine serving the connection, except for Hijacked which is
+// called from the Handler's goroutine. The request hooks for one
+// request are called after its GotRequestHeaders hook and before its
+// WroteResponse hook, if any.
+//
+// ServerTrace currently only traces requests made with HTTP/1.x.
+// For connections using HTTP/2, only the ConnAccepted,
+// TLSHandshakeStart, and TLSHandshakeDone hooks are called.
+type ServerTrace struct {
+	// ConnAccepted is called when the server starts serving a
+	// newly accepted connection, before any data is read from it.
+	ConnAccepted func(ConnAcceptedInfo)
+
+	// TLSHandshakeStart is called when the TLS handshake of a
+	// connection is started.
+	TLSHandshakeStart func()
+
+	// TLSHandshakeDone is called after the TLS handshake with either the
+	// successful handshake's connection state, or a non-nil error on handshake
+	// failure.
+	TLSHandshakeDone func(tls.ConnectionState, error)
+
+	// GotRequestHeaders is called when the request line and header
+	// of a request have been read, before the request is passed to
+	// the Handler.
+	GotRequestHeaders func(GotRequestHeadersInfo)
+
+	// RequestBodyDone is called when the request body has been
+	// read to the end, whether by the Handler or by the server
+	// discarding what the Handler left unread. It is not called if
+	// the body is not read to the end. For requests without a
+	// body, it is called right after GotRequestHeaders.
+	RequestBodyDone func()
+
+	// WroteFirstResponseByte is called when the first byte of the
+	// response, or of a 1xx informational response sent before it,
+	// is written to the connection.
+	WroteFirstResponseByte func()
+
+	// WroteResponse is called once the Handler has returned and
+	// the response has been written to the connection. It is not
+	// called for requests whose connection was hijacked, nor when
+	// the Handler panics.
+	WroteResponse func(WroteResponseInfo)
+
+	// Hijacked is called when a Handler takes over the connection
+	// using the http.Hijacker interface.
+	Hijacked func()
+}
+
+// ConnAcceptedInfo contains information provided to the ConnAccepted
+// hook.
+type ConnAcceptedInfo struct {
+	// Conn is the accepted connection. It is owned by the
+	// http.Server and should not be read, written or closed by
+	// users of ServerTrace.
+	Conn net.Conn
+}
+
+// GotRequestHeadersInfo contains information provided to the
+// GotRequestHeaders hook.
+type GotRequestHeadersInfo struct {
+	// Method, RequestURI, and Proto are the fields of the
+	// request line.
+	Method     string
+	RequestURI string
+	Proto      string
+
+	// Header is the header of the request. It must not be
+	// modified.
+	Header textproto.MIMEHeader
+}
+
+// WroteResponseInfo contains information provided to the WroteResponse
+// hook.
+type WroteResponseInfo struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+
+	// BodyBytes is the number of bytes of response body written
+	// by the Handler, not counting the framing added by chunked
+	// encoding.
+	BodyBytes int64
+
+	// Err is the first error encountered while writing to the
+	// connection, if any.
+	Err error
+}
+
+// compose modifies t such that it respects the previously-registered
+// hooks in old.
+func (t *ServerTrace) compose(old *ServerTrace) {
+	if old == nil {
+		return
+	}
+	composeHooks(reflect.ValueOf(t).Elem(), reflect.ValueOf(old).Elem())
+}
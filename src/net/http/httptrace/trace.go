@@ -3,7 +3,7 @@
 // license that can be found in the LICENSE file.
 
 // Package httptrace provides mechanisms to trace the events within
-// HTTP client requests.
+// HTTP client requests and HTTP server connections.
 package httptrace
 
 import (
@@ -161,6 +161,11 @@ type ClientTrace struct {
 	// request and any body. It may be called multiple times
 	// in the case of retried requests.
 	WroteRequest func(WroteRequestInfo)
+
+	// RetryAttempt is called when an http.Client with a RetryPolicy
+	// is about to make another attempt at sending a request,
+	// either to retry a failed attempt or to hedge a slow one.
+	RetryAttempt func(RetryAttemptInfo)
 }
 
 // WroteRequestInfo contains information provided to the WroteRequest
@@ -170,14 +175,42 @@ type WroteRequestInfo struct {
 	Err error
 }
 
+// RetryAttemptInfo contains information provided to the RetryAttempt
+// hook.
+type RetryAttemptInfo struct {
+	// Attempt is the number of the upcoming attempt, starting at 2.
+	Attempt int
+
+	// Delay is how long the Client waits before starting the
+	// attempt.
+	Delay time.Duration
+
+	// Hedged is whether the attempt is started while an earlier
+	// attempt is still in progress.
+	Hedged bool
+
+	// StatusCode is the response status of the failed attempt
+	// being retried, or zero if it failed with Err or if Hedged
+	// is true.
+	StatusCode int
+
+	// Err is the error of the failed attempt being retried, if any.
+	Err error
+}
+
 // compose modifies t such that it respects the previously-registered hooks in old,
 // subject to the composition policy requested in t.Compose.
 func (t *ClientTrace) compose(old *ClientTrace) {
 	if old == nil {
 		return
 	}
-	tv := reflect.ValueOf(t).Elem()
-	ov := reflect.ValueOf(old).Elem()
+	composeHooks(reflect.ValueOf(t).Elem(), reflect.ValueOf(old).Elem())
+}
+
+// composeHooks sets each func field of the struct tv to a func that
+// calls both its current value and the value of the same field of ov,
+// in that order.
+func composeHooks(tv, ov reflect.Value) {
 	structType := tv.Type()
 	for i := 0; i < structType.NumField(); i++ {
 		tf := tv.Field(i)
@@ -36,6 +36,29 @@ func TestWithClientTrace(t *testing.T) {
 	}
 }
 
+func TestWithServerTrace(t *testing.T) {
+	var buf bytes.Buffer
+	hijacked := func(b byte) func() {
+		return func() {
+			buf.WriteByte(b)
+		}
+	}
+
+	ctx := context.Background()
+	ctx = WithServerTrace(ctx, &ServerTrace{
+		Hijacked: hijacked('O'),
+	})
+	ctx = WithServerTrace(ctx, &ServerTrace{
+		Hijacked: hijacked('N'),
+	})
+	trace := ContextServerTrace(ctx)
+
+	trace.Hijacked()
+	if got, want := buf.String(), "NO"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
 func TestCompose(t *testing.T) {
 	var buf bytes.Buffer
 	var testNum int
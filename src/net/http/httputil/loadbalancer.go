@@ -0,0 +1,231 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Load balancing across several backends
+
+package httputil
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A LoadBalancer distributes the requests of a ReverseProxy among a
+// set of backends in round-robin order. Its Rewrite method may be used
+// in a ReverseProxy's Rewrite function:
+//
+//	lb := httputil.NewLoadBalancer(targets)
+//	proxy := &httputil.ReverseProxy{
+//		Rewrite: func(r *httputil.ProxyRequest) {
+//			lb.Rewrite(r)
+//			r.SetXForwarded()
+//		},
+//	}
+//
+// If HealthCheckPath is set, the backends are checked periodically once
+// StartHealthChecks is called, and requests are only routed to the
+// backends that passed their last check. If no backend is healthy,
+// requests are routed to all of them.
+//
+// The fields of a LoadBalancer must not be modified after
+// StartHealthChecks is called.
+type LoadBalancer struct {
+	// HealthCheckPath is the path requested with GET on each
+	// backend to check its health, joined to the backend's base
+	// path. A backend is healthy if it responds with a status
+	// code below 500. If empty, no health checks are done.
+	HealthCheckPath string
+
+	// HealthCheckInterval is the time between two checks of a
+	// backend. It is also the time a check may take before the
+	// backend is considered unhealthy.
+	// If zero, a default of 10 seconds is used.
+	HealthCheckInterval time.Duration
+
+	// Transport is used to send health checks.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	backends []*backend
+	next     uint32 // accessed atomically
+
+	mu   sync.Mutex
+	stop chan struct{} // closed by Close; nil until StartHealthChecks
+	wg   sync.WaitGroup
+}
+
+type backend struct {
+	target    *url.URL
+	unhealthy int32 // accessed atomically; 1 if the last check failed
+}
+
+// NewLoadBalancer returns a LoadBalancer that routes requests to the
+// scheme, host, and base path of each of targets, as
+// ProxyRequest.SetURL does. It panics if targets is empty.
+func NewLoadBalancer(targets []*url.URL) *LoadBalancer {
+	if len(targets) == 0 {
+		panic("httputil: NewLoadBalancer called with no targets")
+	}
+	lb := &LoadBalancer{}
+	for _, t := range targets {
+		lb.backends = append(lb.backends, &backend{target: t})
+	}
+	return lb
+}
+
+// NewLoadBalancingReverseProxy returns a new ReverseProxy that routes
+// requests with lb and sets the X-Forwarded-For, X-Forwarded-Host, and
+// X-Forwarded-Proto headers of the outbound requests.
+func NewLoadBalancingReverseProxy(lb *LoadBalancer) *ReverseProxy {
+	return &ReverseProxy{
+		Rewrite: func(r *ProxyRequest) {
+			lb.Rewrite(r)
+			r.SetXForwarded()
+		},
+	}
+}
+
+// Rewrite routes the outbound request of r to the next healthy backend,
+// using r.SetURL.
+func (lb *LoadBalancer) Rewrite(r *ProxyRequest) {
+	r.SetURL(lb.pick().target)
+}
+
+// pick returns the next healthy backend, or the next backend if none
+// is healthy.
+func (lb *LoadBalancer) pick() *backend {
+	n := uint32(len(lb.backends))
+	start := atomic.AddUint32(&lb.next, 1) - 1
+	for i := uint32(0); i < n; i++ {
+		b := lb.backends[(start+i)%n]
+		if atomic.LoadInt32(&b.unhealthy) == 0 {
+			if i > 0 {
+				// Skip the unhealthy backends next time
+				// too, without disturbing the rotation
+				// of the healthy ones.
+				atomic.CompareAndSwapUint32(&lb.next, start+1, start+i+1)
+			}
+			return b
+		}
+	}
+	return lb.backends[start%n]
+}
+
+// HealthyTargets returns the targets of the backends that passed their
+// last health check, in the order they were given to NewLoadBalancer.
+func (lb *LoadBalancer) HealthyTargets() []*url.URL {
+	var targets []*url.URL
+	for _, b := range lb.backends {
+		if atomic.LoadInt32(&b.unhealthy) == 0 {
+			targets = append(targets, b.target)
+		}
+	}
+	return targets
+}
+
+func (lb *LoadBalancer) interval() time.Duration {
+	if lb.HealthCheckInterval > 0 {
+		return lb.HealthCheckInterval
+	}
+	return 10 * time.Second
+}
+
+// StartHealthChecks starts checking the health of the backends in the
+// background, until Close is called. The first check of each backend
+// is started immediately. StartHealthChecks does nothing if
+// HealthCheckPath is empty or if the checks were already started.
+func (lb *LoadBalancer) StartHealthChecks() {
+	if lb.HealthCheckPath == "" {
+		return
+	}
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	if lb.stop != nil {
+		return
+	}
+	lb.stop = make(chan struct{})
+	for _, b := range lb.backends {
+		lb.wg.Add(1)
+		go lb.checkLoop(b)
+	}
+}
+
+// Close stops the health checks started by StartHealthChecks and waits
+// for the running ones to finish. Requests may still be routed with lb
+// after Close, using the results of the last checks.
+func (lb *LoadBalancer) Close() error {
+	lb.mu.Lock()
+	if lb.stop != nil {
+		select {
+		case <-lb.stop:
+		default:
+			close(lb.stop)
+		}
+	}
+	lb.mu.Unlock()
+	lb.wg.Wait()
+	return nil
+}
+
+func (lb *LoadBalancer) checkLoop(b *backend) {
+	defer lb.wg.Done()
+	t := time.NewTicker(lb.interval())
+	defer t.Stop()
+	for {
+		lb.check(b)
+		select {
+		case <-t.C:
+		case <-lb.stop:
+			return
+		}
+	}
+}
+
+// check checks the health of b once and records the result.
+func (lb *LoadBalancer) check(b *backend) {
+	ctx, cancel := context.WithTimeout(context.Background(), lb.interval())
+	defer cancel()
+	go func() {
+		select {
+		case <-lb.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	u := *b.target
+	u.Path, u.RawPath = joinURLPath(b.target, &url.URL{Path: lb.HealthCheckPath})
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		atomic.StoreInt32(&b.unhealthy, 1)
+		return
+	}
+	transport := lb.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		select {
+		case <-lb.stop:
+			// Interrupted by Close; keep the last result.
+		default:
+			atomic.StoreInt32(&b.unhealthy, 1)
+		}
+		return
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+	if res.StatusCode < 500 {
+		atomic.StoreInt32(&b.unhealthy, 0)
+	} else {
+		atomic.StoreInt32(&b.unhealthy, 1)
+	}
+}
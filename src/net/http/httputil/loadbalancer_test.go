@@ -0,0 +1,125 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httputil
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadBalancerRoundRobin(t *testing.T) {
+	var targets []*url.URL
+	for _, s := range []string{"http://a/x", "http://b/y", "http://c"} {
+		u, _ := url.Parse(s)
+		targets = append(targets, u)
+	}
+	lb := NewLoadBalancer(targets)
+	route := func() string {
+		out := httptest.NewRequest("GET", "http://front/p", nil)
+		lb.Rewrite(&ProxyRequest{In: out, Out: out})
+		return out.URL.String()
+	}
+	want := []string{"http://a/x/p", "http://b/y/p", "http://c/p", "http://a/x/p"}
+	for i, w := range want {
+		if got := route(); got != w {
+			t.Errorf("request %d routed to %q; want %q", i, got, w)
+		}
+	}
+
+	// Unhealthy backends are skipped, and the remaining ones
+	// share the load evenly.
+	atomic.StoreInt32(&lb.backends[0].unhealthy, 1)
+	counts := map[string]int{}
+	for i := 0; i < 10; i++ {
+		counts[route()]++
+	}
+	if counts["http://b/y/p"] != 5 || counts["http://c/p"] != 5 {
+		t.Errorf("with a unhealthy, requests were routed to %v", counts)
+	}
+
+	// With no healthy backend, all of them are used.
+	for _, b := range lb.backends {
+		atomic.StoreInt32(&b.unhealthy, 1)
+	}
+	counts = map[string]int{}
+	for i := 0; i < 9; i++ {
+		counts[route()]++
+	}
+	if len(counts) != 3 {
+		t.Errorf("with no healthy backend, requests were routed to %v", counts)
+	}
+}
+
+func TestLoadBalancerHealthChecks(t *testing.T) {
+	var failing int32
+	newBackend := func(name string, fail *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/base/healthz" {
+				if fail != nil && atomic.LoadInt32(fail) != 0 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+				return
+			}
+			w.Write([]byte(name))
+		}))
+	}
+	a := newBackend("a", nil)
+	defer a.Close()
+	b := newBackend("b", &failing)
+	defer b.Close()
+	ua, _ := url.Parse(a.URL + "/base")
+	ub, _ := url.Parse(b.URL + "/base")
+
+	atomic.StoreInt32(&failing, 1)
+	lb := NewLoadBalancer([]*url.URL{ua, ub})
+	lb.HealthCheckPath = "/healthz"
+	lb.HealthCheckInterval = 10 * time.Millisecond
+	lb.StartHealthChecks()
+	defer lb.Close()
+
+	waitHealthy := func(n int) {
+		t.Helper()
+		for i := 0; i < 500; i++ {
+			if len(lb.HealthyTargets()) == n {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("healthy targets = %v; want %d of them", lb.HealthyTargets(), n)
+	}
+	waitHealthy(1)
+
+	frontend := httptest.NewServer(NewLoadBalancingReverseProxy(lb))
+	defer frontend.Close()
+	get := func() string {
+		res, err := frontend.Client().Get(frontend.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return string(body)
+	}
+	for i := 0; i < 4; i++ {
+		if got := get(); got != "a" {
+			t.Fatalf("request %d served by %q with b unhealthy; want a", i, got)
+		}
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	waitHealthy(2)
+	seen := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		seen[get()] = true
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Errorf("requests served by %v; want both a and b", seen)
+	}
+}
@@ -1163,3 +1163,153 @@ func TestSingleJoinSlash(t *testing.T) {
 		}
 	}
 }
+
+func TestJoinURLPath(t *testing.T) {
+	tests := []struct {
+		a        *url.URL
+		b        *url.URL
+		wantPath string
+		wantRaw  string
+	}{
+		{&url.URL{Path: "/a/b"}, &url.URL{Path: "/c"}, "/a/b/c", ""},
+		{&url.URL{Path: "/a/b", RawPath: "badpath"}, &url.URL{Path: "c"}, "/a/b/c", "/a/b/c"},
+		{&url.URL{Path: "/a/b", RawPath: "/a%2Fb"}, &url.URL{Path: "/c"}, "/a/b/c", "/a%2Fb/c"},
+		{&url.URL{Path: "/a/b/", RawPath: "/a%2Fb%2F"}, &url.URL{Path: "c"}, "/a/b//c", "/a%2Fb%2F/c"},
+		{&url.URL{Path: "/a/b/", RawPath: "/a%2Fb/"}, &url.URL{Path: "/c/d", RawPath: "/c%2Fd"}, "/a/b/c/d", "/a%2Fb/c%2Fd"},
+	}
+
+	for _, tt := range tests {
+		p, rp := joinURLPath(tt.a, tt.b)
+		if p != tt.wantPath || rp != tt.wantRaw {
+			t.Errorf("joinURLPath(URL(%q,%q),URL(%q,%q)) want (%q,%q) got (%q,%q)",
+				tt.a.Path, tt.a.RawPath,
+				tt.b.Path, tt.b.RawPath,
+				tt.wantPath, tt.wantRaw,
+				p, rp)
+		}
+	}
+}
+
+func TestReverseProxyRewrite(t *testing.T) {
+	type headers struct {
+		host              string
+		path              string
+		rawPath           string
+		forwarded         string
+		xForwardedFor     string
+		xForwardedHost    string
+		xForwardedProto   string
+		connection        string
+		customHopByHopVal string
+	}
+	backendc := make(chan headers, 1)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backendc <- headers{
+			host:              r.Host,
+			path:              r.URL.Path,
+			rawPath:           r.URL.RawPath,
+			forwarded:         r.Header.Get("Forwarded"),
+			xForwardedFor:     r.Header.Get("X-Forwarded-For"),
+			xForwardedHost:    r.Header.Get("X-Forwarded-Host"),
+			xForwardedProto:   r.Header.Get("X-Forwarded-Proto"),
+			connection:        r.Header.Get("X-Connection"),
+			customHopByHopVal: r.Header.Get("X-Hop"),
+		}
+	}))
+	defer backend.Close()
+	backendURL, err := url.Parse(backend.URL + "/base%2Fpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxyHandler := &ReverseProxy{
+		Rewrite: func(r *ProxyRequest) {
+			r.SetURL(backendURL)
+			r.SetXForwarded()
+			r.SetForwarded()
+			// Headers set by Rewrite are kept, even
+			// hop-by-hop ones.
+			r.Out.Header.Set("X-Connection", r.In.Header.Get("Connection"))
+			r.Out.Header.Set("X-Hop", "kept")
+		},
+	}
+	frontend := httptest.NewServer(proxyHandler)
+	defer frontend.Close()
+
+	req, _ := http.NewRequest("GET", frontend.URL+"/dir", nil)
+	req.Host = "example.com:8080"
+	req.Header.Set("Connection", "X-Hop")
+	req.Header.Set("X-Hop", "removed")
+	req.Header.Set("Forwarded", "for=192.0.2.43")
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+	req.Header.Set("X-Forwarded-Host", "evil.example")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	res, err := frontend.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	got := <-backendc
+	want := headers{
+		host:              backendURL.Host,
+		path:              "/base/path/dir",
+		rawPath:           "/base%2Fpath/dir",
+		forwarded:         `for=127.0.0.1;host="example.com:8080";proto=http`,
+		xForwardedFor:     "127.0.0.1",
+		xForwardedHost:    "example.com:8080",
+		xForwardedProto:   "http",
+		connection:        "X-Hop",
+		customHopByHopVal: "kept",
+	}
+	if got != want {
+		t.Errorf("backend got:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
+func TestReverseProxyDirectorAndRewrite(t *testing.T) {
+	for _, p := range []*ReverseProxy{
+		{},
+		{Director: func(*http.Request) {}, Rewrite: func(*ProxyRequest) {}},
+	} {
+		p.ErrorLog = log.New(ioutil.Discard, "", 0)
+		rw := httptest.NewRecorder()
+		p.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+		if rw.Code != http.StatusBadGateway {
+			t.Errorf("status = %v; want %v", rw.Code, http.StatusBadGateway)
+		}
+	}
+}
+
+func TestSetForwarded(t *testing.T) {
+	tests := []struct {
+		remoteAddr string
+		host       string
+		tls        bool
+		prior      []string
+		want       string
+	}{
+		{"192.0.2.60:1234", "example.com", false, nil, "for=192.0.2.60;host=example.com;proto=http"},
+		{"[2001:db8:cafe::17]:4711", "example.com", true, nil, `for="[2001:db8:cafe::17]";host=example.com;proto=https`},
+		{"192.0.2.60:1234", "", false, []string{"for=192.0.2.43", "for=198.51.100.17"}, "for=192.0.2.43, for=198.51.100.17, for=192.0.2.60;proto=http"},
+		{"unix", "a\"b", false, nil, `host="a\"b";proto=http`},
+	}
+	for _, tt := range tests {
+		target := "http://example.com/"
+		if tt.tls {
+			target = "https://example.com/"
+		}
+		in := httptest.NewRequest("GET", target, nil)
+		in.RemoteAddr = tt.remoteAddr
+		in.Host = tt.host
+		out := in.Clone(context.Background())
+		out.Header = http.Header{}
+		if tt.prior != nil {
+			out.Header["Forwarded"] = tt.prior
+		}
+		(&ProxyRequest{In: in, Out: out}).SetForwarded()
+		if got := out.Header.Get("Forwarded"); got != tt.want {
+			t.Errorf("SetForwarded(RemoteAddr=%q, Host=%q) = %q; want %q", tt.remoteAddr, tt.host, got, tt.want)
+		}
+	}
+}
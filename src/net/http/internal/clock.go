@@ -0,0 +1,36 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// A Clock is a source of time for the timeouts of net/http.
+//
+// Connections may report the clock that measures their deadlines by
+// implementing a Clock method returning a Clock, as the in-memory
+// connections of net/http/httptest do, so that tests can control
+// time. Other connections use RealClock.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// A Timer is a timer created by Clock.AfterFunc. *time.Timer
+// implements Timer.
+type Timer interface {
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// RealClock is the system clock.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
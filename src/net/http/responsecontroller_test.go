@@ -0,0 +1,282 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	. "net/http"
+	"testing"
+	"time"
+)
+
+func TestResponseControllerFlush_h1(t *testing.T) { testResponseControllerFlush(t, h1Mode) }
+func TestResponseControllerFlush_h2(t *testing.T) { testResponseControllerFlush(t, h2Mode) }
+
+func testResponseControllerFlush(t *testing.T, h2 bool) {
+	defer afterTest(t)
+	continuec := make(chan struct{})
+	cst := newClientServerTest(t, h2, HandlerFunc(func(w ResponseWriter, r *Request) {
+		ctl := NewResponseController(w)
+		w.Write([]byte("one"))
+		if err := ctl.Flush(); err != nil {
+			t.Errorf("ctl.Flush() = %v, want nil", err)
+			return
+		}
+		<-continuec
+		w.Write([]byte("two"))
+	}))
+	defer cst.close()
+
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected connection error: %v", err)
+	}
+	defer res.Body.Close()
+
+	buf := make([]byte, 16)
+	n, err := res.Body.Read(buf)
+	close(continuec)
+	if err != nil || string(buf[:n]) != "one" {
+		t.Fatalf("Body.Read = %q, %v, want %q, nil", buf[:n], err, "one")
+	}
+
+	got, err := ioutil.ReadAll(res.Body)
+	if err != nil || string(got) != "two" {
+		t.Fatalf("Body.Read = %q, %v, want %q, nil", got, err, "two")
+	}
+}
+
+func TestResponseControllerHijack_h1(t *testing.T) { testResponseControllerHijack(t, h1Mode) }
+func TestResponseControllerHijack_h2(t *testing.T) { testResponseControllerHijack(t, h2Mode) }
+
+func testResponseControllerHijack(t *testing.T, h2 bool) {
+	defer afterTest(t)
+	const header = "X-Header"
+	const value = "set"
+	cst := newClientServerTest(t, h2, HandlerFunc(func(w ResponseWriter, r *Request) {
+		ctl := NewResponseController(w)
+		c, _, err := ctl.Hijack()
+		if h2 {
+			if err != ErrNotSupported {
+				t.Errorf("ctl.Hijack = %v, want ErrNotSupported", err)
+			}
+			w.Header().Set(header, value)
+			return
+		}
+		if err != nil {
+			t.Errorf("ctl.Hijack = _, _, %v, want _, _, nil", err)
+			return
+		}
+		defer c.Close()
+		fmt.Fprintf(c, "HTTP/1.0 200 OK\r\n%v: %v\r\nContent-Length: 0\r\n\r\n", header, value)
+	}))
+	defer cst.close()
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if got, want := res.Header.Get(header), value; got != want {
+		t.Errorf("response header %q = %q, want %q", header, got, want)
+	}
+}
+
+func TestResponseControllerSetPastWriteDeadline(t *testing.T) {
+	defer afterTest(t)
+	cst := newClientServerTest(t, h1Mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		ctl := NewResponseController(w)
+		w.Write([]byte("one"))
+		if err := ctl.Flush(); err != nil {
+			t.Errorf("before setting deadline: ctl.Flush() = %v, want nil", err)
+		}
+		if err := ctl.SetWriteDeadline(time.Now().Add(-10 * time.Second)); err != nil {
+			t.Errorf("ctl.SetWriteDeadline() = %v, want nil", err)
+		}
+		for i := 0; i < 100; i++ {
+			if _, err := w.Write(make([]byte, 1<<10)); err != nil {
+				return
+			}
+			ctl.Flush()
+		}
+		t.Errorf("after setting deadline: writes succeeded")
+	}))
+	defer cst.close()
+
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected connection error: %v", err)
+	}
+	defer res.Body.Close()
+	b, _ := ioutil.ReadAll(res.Body)
+	if len(b) < 3 || string(b[:3]) != "one" {
+		t.Errorf("unexpected body: %.10q...", b)
+	}
+}
+
+// A write deadline set by one handler must not apply to the
+// following requests on the same connection.
+func TestResponseControllerWriteDeadlineKeepAlive(t *testing.T) {
+	defer afterTest(t)
+	cst := newClientServerTest(t, h1Mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if r.URL.Path == "/first" {
+			ctl := NewResponseController(w)
+			if err := ctl.SetWriteDeadline(time.Now().Add(50 * time.Millisecond)); err != nil {
+				t.Errorf("ctl.SetWriteDeadline() = %v, want nil", err)
+			}
+		}
+		io.WriteString(w, r.RemoteAddr)
+	}))
+	defer cst.close()
+
+	get := func(path string) string {
+		t.Helper()
+		res, err := cst.c.Get(cst.ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	addr1 := get("/first")
+	time.Sleep(100 * time.Millisecond) // past the first handler's deadline
+	addr2 := get("/second")
+	if addr1 != addr2 {
+		t.Errorf("second request served on a new connection (%s, then %s); want the first connection reused", addr1, addr2)
+	}
+}
+
+func TestResponseControllerSetFutureReadDeadline(t *testing.T) {
+	defer afterTest(t)
+	respBody := "response body"
+	cst := newClientServerTest(t, h1Mode, HandlerFunc(func(w ResponseWriter, req *Request) {
+		ctl := NewResponseController(w)
+		if err := ctl.SetReadDeadline(time.Now().Add(1 * time.Millisecond)); err != nil {
+			t.Errorf("ctl.SetReadDeadline() = %v, want nil", err)
+		}
+		_, err := io.Copy(ioutil.Discard, req.Body)
+		if ne, ok := err.(interface{ Timeout() bool }); !ok || !ne.Timeout() {
+			t.Errorf("server reading request body: got err %v; want timeout", err)
+		}
+		w.Write([]byte(respBody))
+	}))
+	defer cst.close()
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	res, err := cst.c.Post(cst.ts.URL, "text/apocryphal", pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	got, err := ioutil.ReadAll(res.Body)
+	if string(got) != respBody || err != nil {
+		t.Errorf("client read response body: %q, %v; want %q, nil", got, err, respBody)
+	}
+}
+
+type wrapWriter struct {
+	ResponseWriter
+}
+
+func (w wrapWriter) Unwrap() ResponseWriter {
+	return w.ResponseWriter
+}
+
+func TestWrappedResponseController_h1(t *testing.T) { testWrappedResponseController(t, h1Mode) }
+func TestWrappedResponseController_h2(t *testing.T) { testWrappedResponseController(t, h2Mode) }
+
+func testWrappedResponseController(t *testing.T, h2 bool) {
+	defer afterTest(t)
+	cst := newClientServerTest(t, h2, HandlerFunc(func(w ResponseWriter, r *Request) {
+		ctl := NewResponseController(wrapWriter{w})
+		if err := ctl.Flush(); err != nil {
+			t.Errorf("ctl.Flush() = %v, want nil", err)
+		}
+		// The HTTP/2 server does not support deadlines.
+		var wantDeadlineErr error
+		if h2 {
+			wantDeadlineErr = ErrNotSupported
+		}
+		if err := ctl.SetReadDeadline(time.Time{}); err != wantDeadlineErr {
+			t.Errorf("ctl.SetReadDeadline() = %v, want %v", err, wantDeadlineErr)
+		}
+		if err := ctl.SetWriteDeadline(time.Time{}); err != wantDeadlineErr {
+			t.Errorf("ctl.SetWriteDeadline() = %v, want %v", err, wantDeadlineErr)
+		}
+		if err := ctl.EnableFullDuplex(); err != nil {
+			t.Errorf("ctl.EnableFullDuplex() = %v, want nil", err)
+		}
+	}))
+	defer cst.close()
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected connection error: %v", err)
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+}
+
+func TestResponseControllerNotSupported(t *testing.T) {
+	ctl := NewResponseController(struct{ ResponseWriter }{})
+	if err := ctl.Flush(); err != ErrNotSupported {
+		t.Errorf("ctl.Flush() = %v, want ErrNotSupported", err)
+	}
+	if err := ctl.SetReadDeadline(time.Time{}); err != ErrNotSupported {
+		t.Errorf("ctl.SetReadDeadline() = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestResponseControllerEnableFullDuplex_h1(t *testing.T) {
+	testResponseControllerEnableFullDuplex(t, h1Mode)
+}
+func TestResponseControllerEnableFullDuplex_h2(t *testing.T) {
+	testResponseControllerEnableFullDuplex(t, h2Mode)
+}
+
+func testResponseControllerEnableFullDuplex(t *testing.T, h2 bool) {
+	defer afterTest(t)
+	cst := newClientServerTest(t, h2, HandlerFunc(func(w ResponseWriter, req *Request) {
+		ctl := NewResponseController(w)
+		if err := ctl.EnableFullDuplex(); err != nil {
+			t.Errorf("ctl.EnableFullDuplex() = %v, want nil", err)
+		}
+		w.WriteHeader(200)
+		ctl.Flush()
+		for {
+			var buf [1]byte
+			n, err := req.Body.Read(buf[:])
+			if n != 1 || err != nil {
+				break
+			}
+			w.Write(buf[:])
+			ctl.Flush()
+		}
+	}))
+	defer cst.close()
+	pr, pw := io.Pipe()
+	res, err := cst.c.Post(cst.ts.URL, "text/apocryphal", pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	for i := byte(0); i < 10; i++ {
+		if _, err := pw.Write([]byte{i}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		var buf [1]byte
+		if n, err := res.Body.Read(buf[:]); n != 1 || err != nil {
+			t.Fatalf("Read: %v, %v", n, err)
+		}
+		if buf[0] != i {
+			t.Fatalf("read byte %v, want %v", buf[0], i)
+		}
+	}
+	pw.Close()
+}
@@ -0,0 +1,422 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// HTTP client retries. See Client.RetryPolicy.
+
+package http
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A RetryPolicy tells a Client when and how to retry requests whose
+// attempts fail.
+//
+// A request is only retried if its body can be replayed: it must have
+// no body, or GetBody must be set. Each new attempt gets a fresh body
+// from GetBody.
+//
+// A RetryPolicy must not be modified while in use by a Client.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts made for a
+	// single request, including the first one and any hedged
+	// attempts. If zero, a default of 3 is used.
+	MaxAttempts int
+
+	// ShouldRetry reports whether req should be retried after an
+	// attempt returned resp or err. Exactly one of resp and err is
+	// non-nil. ShouldRetry must not read from or close resp.Body.
+	//
+	// If ShouldRetry is nil, the Client retries requests that the
+	// Transport considers idempotent (see the Transport
+	// documentation) after a network error, or after a response
+	// with status 429, 502, 503 or 504.
+	//
+	// Requests are never retried once their context is done.
+	ShouldRetry func(req *Request, resp *Response, err error) bool
+
+	// Backoff is the delay before the first retry. The delay
+	// doubles with each further retry, up to MaxBackoff. A random
+	// jitter of up to half the delay is subtracted from it, so that
+	// clients failing at the same time do not retry in lockstep.
+	// If zero, a default of 100ms is used.
+	Backoff time.Duration
+
+	// MaxBackoff is the maximum delay between two attempts.
+	// If zero, a default of 10s is used.
+	//
+	// If a retried response has a Retry-After header, the delay it
+	// asks for replaces the computed one. If that delay is longer
+	// than MaxBackoff, the response is returned to the caller
+	// instead of being retried.
+	MaxBackoff time.Duration
+
+	// Budget optionally limits retries and hedged attempts across
+	// all requests that share it.
+	Budget *RetryBudget
+
+	// HedgeDelay, if positive, enables request hedging for requests
+	// that are idempotent and replayable. If an attempt has not
+	// returned response headers after HedgeDelay, another attempt
+	// is started without canceling the first one. The first result
+	// that is not retried is returned, and the other attempts are
+	// canceled. Hedged attempts count towards MaxAttempts.
+	HedgeDelay time.Duration
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts > 0 {
+		return p.MaxAttempts
+	}
+	return 3
+}
+
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff > 0 {
+		return p.MaxBackoff
+	}
+	return 10 * time.Second
+}
+
+// backoff returns the delay before the given attempt, counting from
+// 2 for the first retry.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.Backoff
+	if d <= 0 {
+		d = 100 * time.Millisecond
+	}
+	max := p.maxBackoff()
+	for i := 2; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d - time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func (p *RetryPolicy) shouldRetry(req *Request, resp *Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(req, resp, err)
+	}
+	if !req.isReplayable() {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case StatusTooManyRequests, StatusBadGateway, StatusServiceUnavailable, StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// A RetryBudget limits the retries made across many requests, so that
+// retries do not add much load to a server that is already failing.
+// It is safe for concurrent use.
+//
+// A budget holds tokens, and starts full. Each attempt that is deemed
+// retryable takes one token away, and each attempt that succeeds gives
+// back a fraction of a token. Retries and hedged attempts are only made
+// while more than half of the tokens remain.
+type RetryBudget struct {
+	mu     sync.Mutex
+	max    float64
+	ratio  float64
+	tokens float64
+}
+
+// NewRetryBudget returns a RetryBudget that holds maxTokens tokens,
+// where each successful attempt gives back ratio tokens.
+func NewRetryBudget(maxTokens int, ratio float64) *RetryBudget {
+	return &RetryBudget{
+		max:    float64(maxTokens),
+		ratio:  ratio,
+		tokens: float64(maxTokens),
+	}
+}
+
+// allow reports whether a hedged attempt may be started.
+func (b *RetryBudget) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens > b.max/2
+}
+
+// failure records a retryable attempt and reports whether it may be
+// retried.
+func (b *RetryBudget) failure() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens -= 1; b.tokens < 0 {
+		b.tokens = 0
+	}
+	return b.tokens > b.max/2
+}
+
+// success records a successful attempt.
+func (b *RetryBudget) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens += b.ratio; b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+// retryAfter returns the delay requested by the Retry-After header of
+// resp, which is either a number of seconds or an HTTP date.
+func retryAfter(resp *Response, now time.Time) (time.Duration, bool) {
+	v := resp.Header.get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseUint(v, 10, 63); err == nil {
+		if secs > uint64(1<<63-1)/uint64(time.Second) {
+			return 1<<63 - 1, true
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// A retryAttempt is the result of one attempt at sending a request.
+type retryAttempt struct {
+	n          int // attempt number, starting at 1
+	resp       *Response
+	didTimeout func() bool
+	err        error
+	cancel     func() // cancels the attempt's context; nil unless hedging
+}
+
+// discard releases the resources held by an attempt whose result is
+// not returned to the caller.
+func (a *retryAttempt) discard() {
+	if a.resp != nil {
+		// Read a little of the body so that the connection can
+		// be reused, as for redirects.
+		const maxBodySlurpSize = 2 << 10
+		if a.resp.ContentLength == -1 || a.resp.ContentLength <= maxBodySlurpSize {
+			io.CopyN(ioutil.Discard, a.resp.Body, maxBodySlurpSize)
+		}
+		a.resp.Body.Close()
+	}
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// cancelOnCloseBody cancels the context of a hedged attempt once the
+// caller is done with its response body.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// sendWithRetries is like send, but retries req according to
+// c.RetryPolicy.
+func (c *Client) sendWithRetries(req *Request, deadline time.Time) (resp *Response, didTimeout func() bool, err error) {
+	p := c.RetryPolicy
+	if p == nil {
+		return c.send(req, deadline)
+	}
+	ctx := req.Context()
+	trace := httptrace.ContextClientTrace(ctx)
+	maxAttempts := p.maxAttempts()
+	hedging := p.HedgeDelay > 0 && req.isReplayable()
+
+	// Each attempt after the first gets its own copy of the original
+	// headers, as send adds the Jar's cookies to them.
+	header := req.Header.Clone()
+	newAttempt := func() (r *Request, ok bool) {
+		r = new(Request)
+		*r = *req
+		r.Header = header.Clone()
+		if req.Body != nil && req.Body != NoBody {
+			if req.GetBody == nil {
+				return nil, false
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, false
+			}
+			r.Body = body
+		}
+		return r, true
+	}
+
+	var (
+		results  = make(chan retryAttempt, maxAttempts)
+		cancels  = make([]func(), maxAttempts+1)
+		attempts = 0
+		inFlight = 0
+		hedgeT   *time.Timer
+		hedgeC   <-chan time.Time
+	)
+	start := func(r *Request) {
+		attempts++
+		inFlight++
+		n := attempts
+		if !hedging {
+			resp, didTimeout, err := c.send(r, deadline)
+			results <- retryAttempt{n: n, resp: resp, didTimeout: didTimeout, err: err}
+			return
+		}
+		actx, cancel := context.WithCancel(ctx)
+		cancels[n] = cancel
+		r = r.WithContext(actx)
+		go func() {
+			resp, didTimeout, err := c.send(r, deadline)
+			results <- retryAttempt{n: n, resp: resp, didTimeout: didTimeout, err: err, cancel: cancel}
+		}()
+	}
+	stopHedge := func() {
+		if hedgeT != nil {
+			hedgeT.Stop()
+			hedgeT, hedgeC = nil, nil
+		}
+	}
+	// finish returns the result of attempt a and abandons the others.
+	finish := func(a retryAttempt) (*Response, func() bool, error) {
+		stopHedge()
+		if inFlight > 0 {
+			for n, cancel := range cancels {
+				if n != a.n && cancel != nil {
+					cancel()
+				}
+			}
+			go func(n int) {
+				for ; n > 0; n-- {
+					a := <-results
+					a.discard()
+				}
+			}(inFlight)
+		}
+		if a.cancel != nil {
+			if a.resp != nil {
+				a.resp.Body = &cancelOnCloseBody{a.resp.Body, a.cancel}
+			} else {
+				a.cancel()
+			}
+		}
+		return a.resp, a.didTimeout, a.err
+	}
+
+	start(req)
+	for {
+		if hedging && hedgeC == nil && attempts < maxAttempts {
+			hedgeT = time.NewTimer(p.HedgeDelay)
+			hedgeC = hedgeT.C
+		}
+		var a retryAttempt
+		select {
+		case a = <-results:
+			inFlight--
+		case <-hedgeC:
+			hedgeT, hedgeC = nil, nil
+			if !p.Budget.allow() {
+				// Wait for the running attempts; no more
+				// hedging for this request.
+				hedging = false
+				continue
+			}
+			r, ok := newAttempt()
+			if !ok {
+				hedging = false
+				continue
+			}
+			if trace != nil && trace.RetryAttempt != nil {
+				trace.RetryAttempt(httptrace.RetryAttemptInfo{Attempt: attempts + 1, Hedged: true})
+			}
+			start(r)
+			continue
+		}
+
+		if !p.shouldRetry(req, a.resp, a.err) {
+			if a.err == nil {
+				p.Budget.success()
+			}
+			return finish(a)
+		}
+		allowed := p.Budget.failure()
+		if inFlight > 0 {
+			// A hedged attempt is still running; wait for it
+			// rather than starting another one.
+			a.discard()
+			continue
+		}
+		stopHedge()
+		if !allowed || attempts >= maxAttempts {
+			return finish(a)
+		}
+		delay := p.backoff(attempts + 1)
+		if a.resp != nil {
+			if d, ok := retryAfter(a.resp, time.Now()); ok {
+				if d > p.maxBackoff() {
+					return finish(a)
+				}
+				delay = d
+			}
+		}
+		if !deadline.IsZero() && time.Now().Add(delay).After(deadline) {
+			return finish(a)
+		}
+		r, ok := newAttempt()
+		if !ok {
+			return finish(a)
+		}
+		if trace != nil && trace.RetryAttempt != nil {
+			info := httptrace.RetryAttemptInfo{Attempt: attempts + 1, Delay: delay, Err: a.err}
+			if a.resp != nil {
+				info.StatusCode = a.resp.StatusCode
+			}
+			trace.RetryAttempt(info)
+		}
+		a.discard()
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			r.closeBody()
+			return nil, alwaysFalse, ctx.Err()
+		case <-req.Cancel:
+			t.Stop()
+			r.closeBody()
+			return nil, alwaysFalse, errRequestCanceled
+		}
+		start(r)
+	}
+}
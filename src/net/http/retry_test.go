@@ -0,0 +1,263 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	. "net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientRetryStatus(t *testing.T) {
+	defer afterTest(t)
+	var n int32
+	ts := httptest.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		if atomic.AddInt32(&n, 1) < 3 {
+			w.WriteHeader(StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	c := ts.Client()
+	c.RetryPolicy = &RetryPolicy{Backoff: time.Millisecond}
+	var infos []httptrace.RetryAttemptInfo
+	trace := &httptrace.ClientTrace{
+		RetryAttempt: func(info httptrace.RetryAttemptInfo) { infos = append(infos, info) },
+	}
+	req, _ := NewRequest("GET", ts.URL, nil)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != 200 || string(body) != "ok" {
+		t.Errorf("got %v %q; want 200 \"ok\"", res.Status, body)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("RetryAttempt called %d times; want 2", len(infos))
+	}
+	for i, info := range infos {
+		if info.Attempt != i+2 || info.StatusCode != StatusServiceUnavailable || info.Hedged {
+			t.Errorf("RetryAttempt #%d = %+v", i, info)
+		}
+	}
+}
+
+func TestClientRetryExhausted(t *testing.T) {
+	defer afterTest(t)
+	var n int32
+	ts := httptest.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		atomic.AddInt32(&n, 1)
+		w.WriteHeader(StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	c := ts.Client()
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 4, Backoff: time.Millisecond}
+	res, err := c.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusBadGateway {
+		t.Errorf("status = %v; want %v", res.StatusCode, StatusBadGateway)
+	}
+	if got := atomic.LoadInt32(&n); got != 4 {
+		t.Errorf("server saw %d attempts; want 4", got)
+	}
+}
+
+func TestClientRetryNetworkError(t *testing.T) {
+	defer afterTest(t)
+	var n int32
+	ts := httptest.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		if atomic.AddInt32(&n, 1) == 1 {
+			c, _, _ := w.(Hijacker).Hijack()
+			c.Close()
+			return
+		}
+	}))
+	defer ts.Close()
+
+	c := ts.Client()
+	c.Transport.(*Transport).DisableKeepAlives = true
+	c.RetryPolicy = &RetryPolicy{Backoff: time.Millisecond}
+	res, err := c.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if got := atomic.LoadInt32(&n); got != 2 {
+		t.Errorf("server saw %d attempts; want 2", got)
+	}
+}
+
+func TestClientRetryRewindsBody(t *testing.T) {
+	defer afterTest(t)
+	var n int32
+	ts := httptest.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("attempt %d: body = %q", atomic.LoadInt32(&n)+1, body)
+		}
+		if atomic.AddInt32(&n, 1) == 1 {
+			w.WriteHeader(StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	c := ts.Client()
+	c.RetryPolicy = &RetryPolicy{Backoff: time.Millisecond}
+	for _, test := range []struct {
+		idempotencyKey bool
+		wantAttempts   int32
+	}{
+		{false, 1},
+		{true, 2},
+	} {
+		atomic.StoreInt32(&n, 0)
+		req, _ := NewRequest("POST", ts.URL, bytes.NewReader([]byte("payload")))
+		if test.idempotencyKey {
+			req.Header.Set("Idempotency-Key", "x")
+		}
+		res, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if got := atomic.LoadInt32(&n); got != test.wantAttempts {
+			t.Errorf("Idempotency-Key %v: server saw %d attempts; want %d", test.idempotencyKey, got, test.wantAttempts)
+		}
+	}
+}
+
+func TestClientRetryAfterTooLong(t *testing.T) {
+	defer afterTest(t)
+	var n int32
+	ts := httptest.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		atomic.AddInt32(&n, 1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	c := ts.Client()
+	c.RetryPolicy = &RetryPolicy{MaxBackoff: time.Minute}
+	res, err := c.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if got := atomic.LoadInt32(&n); got != 1 {
+		t.Errorf("server saw %d attempts; want 1", got)
+	}
+}
+
+func TestClientRetryBudget(t *testing.T) {
+	defer afterTest(t)
+	var n int32
+	ts := httptest.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		atomic.AddInt32(&n, 1)
+		w.WriteHeader(StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	c := ts.Client()
+	c.RetryPolicy = &RetryPolicy{
+		MaxAttempts: 10,
+		Backoff:     time.Millisecond,
+		Budget:      NewRetryBudget(4, 0.1),
+	}
+	// Tokens go from 4 to 3 (retry allowed), then 2 (not allowed).
+	for _, want := range []int32{2, 1} {
+		atomic.StoreInt32(&n, 0)
+		res, err := c.Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if got := atomic.LoadInt32(&n); got != want {
+			t.Errorf("server saw %d attempts; want %d", got, want)
+		}
+	}
+}
+
+func TestClientRetryHedging(t *testing.T) {
+	defer afterTest(t)
+	var n int32
+	canceled := make(chan bool, 1)
+	ts := httptest.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		if atomic.AddInt32(&n, 1) == 1 {
+			// The first attempt hangs until the hedged one wins.
+			select {
+			case <-r.Context().Done():
+				canceled <- true
+			case <-time.After(10 * time.Second):
+				canceled <- false
+			}
+			return
+		}
+		w.Write([]byte("hedged"))
+	}))
+	defer ts.Close()
+
+	c := ts.Client()
+	c.RetryPolicy = &RetryPolicy{HedgeDelay: 10 * time.Millisecond}
+	var hedged int32
+	trace := &httptrace.ClientTrace{
+		RetryAttempt: func(info httptrace.RetryAttemptInfo) {
+			if info.Hedged {
+				atomic.AddInt32(&hedged, 1)
+			}
+		},
+	}
+	req, _ := NewRequest("GET", ts.URL, nil)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != "hedged" {
+		t.Errorf("body = %q; want %q", body, "hedged")
+	}
+	if atomic.LoadInt32(&hedged) == 0 {
+		t.Errorf("RetryAttempt not called for the hedged attempt")
+	}
+	if !<-canceled {
+		t.Errorf("first attempt was not canceled")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		v    string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Sat, 01 Jun 2019 12:01:00 GMT", time.Minute, true},
+		{"Sat, 01 Jun 2019 11:00:00 GMT", 0, true},
+	} {
+		res := &Response{Header: Header{"Retry-After": {test.v}}}
+		if got, ok := ExportRetryAfter(res, now); got != test.want || ok != test.ok {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v, %v", test.v, got, ok, test.want, test.ok)
+		}
+	}
+}
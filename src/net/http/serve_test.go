@@ -23,6 +23,7 @@ import (
 	"net"
 	. "net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
 	"net/http/httputil"
 	"net/http/internal"
 	"net/url"
@@ -3019,6 +3020,107 @@ func testRequestBodyLimit(t *testing.T, h2 bool) {
 	}
 }
 
+func TestMaxBytesHandler_h1(t *testing.T) { testMaxBytesHandler(t, h1Mode) }
+func TestMaxBytesHandler_h2(t *testing.T) { testMaxBytesHandler(t, h2Mode) }
+func testMaxBytesHandler(t *testing.T, h2 bool) {
+	setParallel(t)
+	defer afterTest(t)
+	const limit = 10
+	cst := newClientServerTest(t, h2, MaxBytesHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		_, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			if mbe, ok := err.(*MaxBytesError); !ok || mbe.Limit != limit {
+				t.Errorf("ReadAll error = %#v; want MaxBytesError with limit %d", err, limit)
+			}
+			return
+		}
+		io.WriteString(w, "ok")
+	}), limit))
+	defer cst.close()
+
+	tests := []struct {
+		body       io.Reader
+		wantStatus int
+	}{
+		{strings.NewReader("short"), 200},
+		{struct{ io.Reader }{strings.NewReader("short")}, 200},
+		{strings.NewReader("this body is too long"), 413}, // rejected by Content-Length
+		{struct{ io.Reader }{strings.NewReader("this body is too long")}, 413},
+	}
+	for i, tt := range tests {
+		res, err := cst.c.Post(cst.ts.URL, "text/plain", tt.body)
+		if err != nil {
+			t.Fatalf("%d. Post: %v", i, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != tt.wantStatus {
+			t.Errorf("%d. status = %d; want %d", i, res.StatusCode, tt.wantStatus)
+		}
+	}
+}
+
+func TestDecompressRequestHandler(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	ts := httptest.NewServer(DecompressRequestHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		if ce := r.Header.Get("Content-Encoding"); ce != "" {
+			t.Errorf("Content-Encoding = %q; want none", ce)
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			if err != ErrBodyCompressionRatio {
+				if _, ok := err.(*MaxBytesError); !ok {
+					t.Errorf("ReadAll error = %v", err)
+				}
+			}
+			return
+		}
+		fmt.Fprintf(w, "%d %s", len(b), b[:5])
+	}), 1<<20, 100))
+	defer ts.Close()
+
+	gzipped := func(b []byte) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(b)
+		zw.Close()
+		return buf.Bytes()
+	}
+	random := make([]byte, 256<<10)
+	rand.New(rand.NewSource(1)).Read(random)
+	tests := []struct {
+		name       string
+		encoding   string
+		body       []byte
+		wantStatus int
+		wantBody   string
+	}{
+		{"identity", "", []byte("hello"), 200, "5 hello"},
+		{"gzip", "gzip", gzipped([]byte("hello, world")), 200, "12 hello"},
+		{"incompressible", "gzip", gzipped(random), 200, fmt.Sprintf("%d %s", len(random), random[:5])},
+		{"ratio", "gzip", gzipped(make([]byte, 512<<10)), 413, ""},
+		{"size", "gzip", gzipped(bytes.Repeat(random, 5)), 413, ""},
+		{"unsupported", "br", []byte("hello"), 415, ""},
+	}
+	for _, tt := range tests {
+		req, _ := NewRequest("POST", ts.URL, bytes.NewReader(tt.body))
+		if tt.encoding != "" {
+			req.Header.Set("Content-Encoding", tt.encoding)
+		}
+		res, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != tt.wantStatus {
+			t.Errorf("%s: status = %d; want %d", tt.name, res.StatusCode, tt.wantStatus)
+		} else if tt.wantStatus == 200 && string(body) != tt.wantBody {
+			t.Errorf("%s: body = %q; want %q", tt.name, body, tt.wantBody)
+		}
+	}
+}
+
 // TestClientWriteShutdown tests that if the client shuts down the write
 // side of their TCP connection, the server doesn't send a 400 Bad Request.
 func TestClientWriteShutdown(t *testing.T) {
@@ -6071,6 +6173,111 @@ func TestServerContexts(t *testing.T) {
 	}
 }
 
+func TestServerTrace(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	logEvent := func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, fmt.Sprintf(format, args...))
+	}
+	done := make(chan bool, 2)
+	trace := &httptrace.ServerTrace{
+		ConnAccepted: func(info httptrace.ConnAcceptedInfo) {
+			logEvent("ConnAccepted")
+		},
+		TLSHandshakeStart: func() {
+			logEvent("TLSHandshakeStart")
+		},
+		TLSHandshakeDone: func(cs tls.ConnectionState, err error) {
+			logEvent("TLSHandshakeDone(%v, %v)", cs.HandshakeComplete, err)
+		},
+		GotRequestHeaders: func(info httptrace.GotRequestHeadersInfo) {
+			logEvent("GotRequestHeaders(%s %s %s, %s)", info.Method, info.RequestURI, info.Proto, info.Header.Get("X-Foo"))
+		},
+		RequestBodyDone: func() {
+			logEvent("RequestBodyDone")
+		},
+		WroteFirstResponseByte: func() {
+			logEvent("WroteFirstResponseByte")
+		},
+		WroteResponse: func(info httptrace.WroteResponseInfo) {
+			logEvent("WroteResponse(%d, %d, %v)", info.StatusCode, info.BodyBytes, info.Err)
+			done <- true
+		},
+		Hijacked: func() {
+			logEvent("Hijacked")
+			done <- true
+		},
+	}
+	ts := httptest.NewUnstartedServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		if httptrace.ContextServerTrace(r.Context()) == nil {
+			t.Errorf("no ServerTrace in request context")
+		}
+		logEvent("handler")
+		if r.URL.Path == "/hijack" {
+			c, _, err := w.(Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			io.WriteString(c, "HTTP/1.1 200 OK\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+			c.Close()
+			return
+		}
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(StatusTeapot)
+		io.WriteString(w, "hello")
+	}))
+	ts.Config.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		return httptrace.WithServerTrace(ctx, trace)
+	}
+	ts.StartTLS()
+	defer ts.Close()
+	c := ts.Client()
+
+	req, _ := NewRequest("POST", ts.URL+"/path", strings.NewReader("body"))
+	req.Header.Set("X-Foo", "foo")
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	<-done
+
+	res, err = c.Get(ts.URL + "/hijack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{
+		"ConnAccepted",
+		"TLSHandshakeStart",
+		"TLSHandshakeDone(true, <nil>)",
+		"GotRequestHeaders(POST /path HTTP/1.1, foo)",
+		"handler",
+		"RequestBodyDone",
+		"WroteFirstResponseByte",
+		"WroteResponse(418, 5, <nil>)",
+		"GotRequestHeaders(GET /hijack HTTP/1.1, )",
+		"RequestBodyDone",
+		"handler",
+		"Hijacked",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(events, "\n"), strings.Join(want, "\n"))
+	}
+}
+
 // Issue 30710: ensure that as per the spec, a server responds
 // with 501 Not Implemented for unsupported transfer-encodings.
 func TestUnsupportedTransferEncodingsReturn501(t *testing.T) {
@@ -17,6 +17,8 @@ import (
 	"io/ioutil"
 	"log"
 	"net"
+	"net/http/httptrace"
+	"net/http/internal"
 	"net/textproto"
 	"net/url"
 	"os"
@@ -258,6 +260,9 @@ type conn struct {
 	// *tls.Conn.
 	rwc net.Conn
 
+	// clock measures the deadlines of rwc. See connClock.
+	clock internal.Clock
+
 	// remoteAddr is rwc.RemoteAddr().String(). It is not populated synchronously
 	// inside the Listener's Accept goroutine, as some implementations block.
 	// It is populated immediately inside the (*conn).serve goroutine.
@@ -298,6 +303,9 @@ type conn struct {
 	// by a Handler with the Hijacker interface.
 	// It is guarded by mu.
 	hijackedv bool
+
+	// trace is the ServerTrace of the connection's context, if any.
+	trace *httptrace.ServerTrace
 }
 
 func (c *conn) hijacked() bool {
@@ -421,6 +429,7 @@ type response struct {
 	cancelCtx        context.CancelFunc // when ServeHTTP exits
 	wroteHeader      bool               // reply header has been (logically) written
 	wroteContinue    bool               // 100 Continue response was written
+	wroteFirstByte   bool               // some of the response reached the conn; for ServerTrace
 	wants10KeepAlive bool               // HTTP/1.0 w/ Connection "keep-alive"
 	wantsClose       bool               // HTTP request has Connection "close"
 
@@ -453,6 +462,11 @@ type response struct {
 	// input from it.
 	requestBodyLimitHit bool
 
+	// fullDuplex is set by EnableFullDuplex. When set, the
+	// unread request body is not consumed before the response
+	// header is written.
+	fullDuplex bool
+
 	// trailers are the headers to be sent after the handler
 	// finishes writing the body. This field is initialized from
 	// the Trailer response header when the response header is
@@ -620,6 +634,7 @@ func (srv *Server) newConn(rwc net.Conn) *conn {
 	c := &conn{
 		server: srv,
 		rwc:    rwc,
+		clock:  connClock(rwc),
 	}
 	if debugServerConnections {
 		c.rwc = newLoggingConn("server", c.rwc)
@@ -627,6 +642,17 @@ func (srv *Server) newConn(rwc net.Conn) *conn {
 	return c
 }
 
+// connClock returns the clock that measures the deadlines of c: the
+// one reported by its Clock method, if any, or the system clock. Tests
+// use connections with fake clocks to control the timeouts of servers
+// and transports.
+func connClock(c net.Conn) internal.Clock {
+	if cc, ok := c.(interface{ Clock() internal.Clock }); ok {
+		return cc.Clock()
+	}
+	return internal.RealClock
+}
+
 type readResult struct {
 	n   int
 	err error
@@ -942,7 +968,7 @@ func (c *conn) readRequest(ctx context.Context) (w *response, err error) {
 		wholeReqDeadline time.Time // or zero if none
 		hdrDeadline      time.Time // or zero if none
 	)
-	t0 := time.Now()
+	t0 := c.clock.Now()
 	if d := c.server.readHeaderTimeout(); d != 0 {
 		hdrDeadline = t0.Add(d)
 	}
@@ -952,7 +978,7 @@ func (c *conn) readRequest(ctx context.Context) (w *response, err error) {
 	c.rwc.SetReadDeadline(hdrDeadline)
 	if d := c.server.WriteTimeout; d != 0 {
 		defer func() {
-			c.rwc.SetWriteDeadline(time.Now().Add(d))
+			c.rwc.SetWriteDeadline(c.clock.Now().Add(d))
 		}()
 	}
 
@@ -1320,7 +1346,7 @@ func (cw *chunkWriter) writeHeader(p []byte) {
 	// TODO(bradfitz): where does RFC 2616 say that? See Issue 15527
 	// about HTTP/1.x Handlers concurrently reading and writing, like
 	// HTTP/2 handlers can do. Maybe this code should be relaxed?
-	if w.req.ContentLength != 0 && !w.closeAfterReply {
+	if w.req.ContentLength != 0 && !w.closeAfterReply && !w.fullDuplex {
 		var discard, tooBig bool
 
 		switch bdy := w.req.Body.(type) {
@@ -1389,7 +1415,7 @@ func (cw *chunkWriter) writeHeader(p []byte) {
 	}
 
 	if !header.has("Date") {
-		setHeader.date = appendTime(cw.res.dateBuf[:0], time.Now())
+		setHeader.date = appendTime(cw.res.dateBuf[:0], w.conn.clock.Now())
 	}
 
 	if hasCL && hasTE && te != "identity" {
@@ -1639,6 +1665,19 @@ func (w *response) Flush() {
 	w.cw.flush()
 }
 
+func (w *response) SetReadDeadline(deadline time.Time) error {
+	return w.conn.rwc.SetReadDeadline(deadline)
+}
+
+func (w *response) SetWriteDeadline(deadline time.Time) error {
+	return w.conn.rwc.SetWriteDeadline(deadline)
+}
+
+func (w *response) EnableFullDuplex() error {
+	w.fullDuplex = true
+	return nil
+}
+
 func (c *conn) finalFlush() {
 	if c.bufr != nil {
 		// Steal the bufio.Reader (~4KB worth of memory) and its associated
@@ -1760,6 +1799,10 @@ func isCommonNetReadError(err error) bool {
 func (c *conn) serve(ctx context.Context) {
 	c.remoteAddr = c.rwc.RemoteAddr().String()
 	ctx = context.WithValue(ctx, LocalAddrContextKey, c.rwc.LocalAddr())
+	c.trace = httptrace.ContextServerTrace(ctx)
+	if c.trace != nil && c.trace.ConnAccepted != nil {
+		c.trace.ConnAccepted(httptrace.ConnAcceptedInfo{Conn: c.rwc})
+	}
 	defer func() {
 		if err := recover(); err != nil && err != ErrAbortHandler {
 			const size = 64 << 10
@@ -1775,12 +1818,19 @@ func (c *conn) serve(ctx context.Context) {
 
 	if tlsConn, ok := c.rwc.(*tls.Conn); ok {
 		if d := c.server.ReadTimeout; d != 0 {
-			c.rwc.SetReadDeadline(time.Now().Add(d))
+			c.rwc.SetReadDeadline(c.clock.Now().Add(d))
 		}
 		if d := c.server.WriteTimeout; d != 0 {
-			c.rwc.SetWriteDeadline(time.Now().Add(d))
+			c.rwc.SetWriteDeadline(c.clock.Now().Add(d))
+		}
+		if c.trace != nil && c.trace.TLSHandshakeStart != nil {
+			c.trace.TLSHandshakeStart()
 		}
-		if err := tlsConn.Handshake(); err != nil {
+		err := tlsConn.Handshake()
+		if c.trace != nil && c.trace.TLSHandshakeDone != nil {
+			c.trace.TLSHandshakeDone(tlsConn.ConnectionState(), err)
+		}
+		if err != nil {
 			// If the handshake failed due to the client not speaking
 			// TLS, assume they're speaking plaintext HTTP and write a
 			// 400 response on the TLS conn's underlying net.Conn.
@@ -1803,14 +1853,22 @@ func (c *conn) serve(ctx context.Context) {
 		}
 	}
 
+	c.r = &connReader{conn: c}
+	c.bufr = newBufioReader(c.r)
+
+	if c.tlsState == nil && c.maybeServeUnencryptedHTTP2() {
+		return
+	}
+	if !c.server.protocols().HTTP1() {
+		return
+	}
+
 	// HTTP/1.x from here on.
 
 	ctx, cancelCtx := context.WithCancel(ctx)
 	c.cancelCtx = cancelCtx
 	defer cancelCtx()
 
-	c.r = &connReader{conn: c}
-	c.bufr = newBufioReader(c.r)
 	c.bufw = newBufioWriterSize(checkConnErrorWriter{c}, 4<<10)
 
 	for {
@@ -1860,8 +1918,17 @@ func (c *conn) serve(ctx context.Context) {
 			}
 		}
 
-		// Expect 100 Continue support
 		req := w.req
+		if c.trace != nil && c.trace.GotRequestHeaders != nil {
+			c.trace.GotRequestHeaders(httptrace.GotRequestHeadersInfo{
+				Method:     req.Method,
+				RequestURI: req.RequestURI,
+				Proto:      req.Proto,
+				Header:     textproto.MIMEHeader(req.Header),
+			})
+		}
+
+		// Expect 100 Continue support
 		if req.expectsContinue() {
 			if req.ProtoAtLeast(1, 1) && req.ContentLength != 0 {
 				// Wrap the Body reader with one that replies on the connection
@@ -1874,10 +1941,24 @@ func (c *conn) serve(ctx context.Context) {
 
 		c.curReq.Store(w)
 
+		var bodyDone func()
+		if c.trace != nil {
+			bodyDone = c.trace.RequestBodyDone
+		}
 		if requestBodyRemains(req.Body) {
-			registerOnHitEOF(req.Body, w.conn.r.startBackgroundRead)
+			onHitEOF := w.conn.r.startBackgroundRead
+			if bodyDone != nil {
+				onHitEOF = func() {
+					w.conn.r.startBackgroundRead()
+					bodyDone()
+				}
+			}
+			registerOnHitEOF(req.Body, onHitEOF)
 		} else {
 			w.conn.r.startBackgroundRead()
+			if bodyDone != nil {
+				bodyDone()
+			}
 		}
 
 		// HTTP cannot have multiple simultaneous active requests.[*]
@@ -1893,6 +1974,13 @@ func (c *conn) serve(ctx context.Context) {
 			return
 		}
 		w.finishRequest()
+		if c.trace != nil && c.trace.WroteResponse != nil {
+			c.trace.WroteResponse(httptrace.WroteResponseInfo{
+				StatusCode: w.status,
+				BodyBytes:  w.written,
+				Err:        c.werr,
+			})
+		}
 		if !w.shouldReuseConnection() {
 			if w.requestBodyLimitHit || w.closedRequestBodyEarly() {
 				c.closeWriteAndWait()
@@ -1911,15 +1999,66 @@ func (c *conn) serve(ctx context.Context) {
 		}
 
 		if d := c.server.idleTimeout(); d != 0 {
-			c.rwc.SetReadDeadline(time.Now().Add(d))
+			c.rwc.SetReadDeadline(c.clock.Now().Add(d))
 			if _, err := c.bufr.Peek(4); err != nil {
 				return
 			}
 		}
+
+		// Clear any deadlines set by the handler with a
+		// ResponseController, so that they don't apply to
+		// the next request.
 		c.rwc.SetReadDeadline(time.Time{})
+		c.rwc.SetWriteDeadline(time.Time{})
 	}
 }
 
+// maybeServeUnencryptedHTTP2 serves c with the bundled HTTP/2 server if
+// the server accepts unencrypted HTTP/2 and the client starts the
+// connection with the HTTP/2 preface. It reports whether it did.
+func (c *conn) maybeServeUnencryptedHTTP2() bool {
+	h2 := c.server.unencryptedHTTP2
+	if h2 == nil {
+		return false
+	}
+	if d := c.server.readHeaderTimeout(); d != 0 {
+		c.rwc.SetReadDeadline(c.clock.Now().Add(d))
+	}
+	hasPreface := func(preface string) bool {
+		// Never read past the preface, so that the bytes of an
+		// HTTP/1 request stay in c.bufr.
+		c.r.setReadLimit(int64(len(preface) - c.bufr.Buffered()))
+		got, err := c.bufr.Peek(len(preface))
+		c.r.setInfiniteReadLimit()
+		return err == nil && string(got) == preface
+	}
+	// Check a prefix first, as the full preface is longer than the
+	// shortest valid HTTP/1 request.
+	if !hasPreface("PRI * HTTP/2.0") || !hasPreface(http2ClientPreface) {
+		return false
+	}
+	c.rwc.SetReadDeadline(time.Time{})
+	preface, _ := c.bufr.Peek(c.bufr.Buffered())
+	h2.ServeConn(&unencryptedHTTP2Conn{
+		Conn: c.rwc,
+		r:    io.MultiReader(bytes.NewReader(append([]byte(nil), preface...)), c.rwc),
+	}, &http2ServeConnOpts{
+		Handler:    serverHandler{c.server},
+		BaseConfig: c.server,
+	})
+	return true
+}
+
+// unencryptedHTTP2Conn is the net.Conn handed to the HTTP/2 server for
+// an unencrypted HTTP/2 connection. Its reads start with the client
+// preface that was already consumed while detecting the protocol.
+type unencryptedHTTP2Conn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *unencryptedHTTP2Conn) Read(p []byte) (int, error) { return c.r.Read(p) }
+
 func (w *response) sendExpectationFailed() {
 	// TODO(bradfitz): let ServeHTTP handlers handle
 	// requests with non-standard expectation[s]? Seems
@@ -1950,7 +2089,6 @@ func (w *response) Hijack() (rwc net.Conn, buf *bufio.ReadWriter, err error) {
 
 	c := w.conn
 	c.mu.Lock()
-	defer c.mu.Unlock()
 
 	// Release the bufioWriter that writes to the chunk writer, it is not
 	// used after a connection has been hijacked.
@@ -1959,6 +2097,10 @@ func (w *response) Hijack() (rwc net.Conn, buf *bufio.ReadWriter, err error) {
 		putBufioWriter(w.w)
 		w.w = nil
 	}
+	c.mu.Unlock()
+	if err == nil && c.trace != nil && c.trace.Hijacked != nil {
+		c.trace.Hijacked()
+	}
 	return rwc, buf, err
 }
 
@@ -2545,6 +2687,22 @@ type Server struct {
 	// automatically.
 	TLSNextProto map[string]func(*Server, *tls.Conn, Handler)
 
+	// HTTP2 configures HTTP/2 connections.
+	// If nil, defaults are used.
+	HTTP2 *HTTP2Config
+
+	// Protocols is the set of protocols accepted by the server.
+	//
+	// If Protocols includes UnencryptedHTTP2, the server will accept
+	// unencrypted HTTP/2 connections with prior knowledge, using the
+	// bundled HTTP/2 implementation. The server can serve both HTTP/1
+	// and unencrypted HTTP/2 on the same address and port.
+	//
+	// If Protocols is nil, the default is HTTP/1 and HTTP/2.
+	// If TLSNextProto is non-nil, HTTP/2 over TLS is not enabled
+	// automatically.
+	Protocols *Protocols
+
 	// ConnState specifies an optional callback function that is
 	// called when a client connection changes state. See the
 	// ConnState type and associated constants for details.
@@ -2570,10 +2728,11 @@ type Server struct {
 	// value.
 	ConnContext func(ctx context.Context, c net.Conn) context.Context
 
-	disableKeepAlives int32     // accessed atomically.
-	inShutdown        int32     // accessed atomically (non-zero means we're in Shutdown)
-	nextProtoOnce     sync.Once // guards setupHTTP2_* init
-	nextProtoErr      error     // result of http2.ConfigureServer if used
+	disableKeepAlives int32        // accessed atomically.
+	inShutdown        int32        // accessed atomically (non-zero means we're in Shutdown)
+	nextProtoOnce     sync.Once    // guards setupHTTP2_* init
+	nextProtoErr      error        // result of http2.ConfigureServer if used
+	unencryptedHTTP2  *http2Server // serves unencrypted HTTP/2 if enabled
 
 	mu         sync.Mutex
 	listeners  map[*net.Listener]struct{}
@@ -3146,6 +3305,8 @@ func (srv *Server) setupHTTP2_Serve() error {
 func (srv *Server) onceSetNextProtoDefaults_Serve() {
 	if srv.shouldConfigureHTTP2ForServe() {
 		srv.onceSetNextProtoDefaults()
+	} else {
+		srv.setUnencryptedHTTP2Defaults(nil)
 	}
 }
 
@@ -3156,14 +3317,107 @@ func (srv *Server) onceSetNextProtoDefaults() {
 	if strings.Contains(os.Getenv("GODEBUG"), "http2server=0") {
 		return
 	}
+	var conf *http2Server
 	// Enable HTTP/2 by default if the user hasn't otherwise
 	// configured their TLSNextProto map.
-	if srv.TLSNextProto == nil {
-		conf := &http2Server{
-			NewWriteScheduler: func() http2WriteScheduler { return http2NewPriorityWriteScheduler(nil) },
-		}
+	if srv.TLSNextProto == nil && srv.protocols().HTTP2() {
+		conf = srv.newHTTP2Server()
 		srv.nextProtoErr = http2ConfigureServer(srv, conf)
 	}
+	srv.setUnencryptedHTTP2Defaults(conf)
+}
+
+// setUnencryptedHTTP2Defaults prepares srv to serve unencrypted HTTP/2
+// if its Protocols ask for it, reusing conf if it is non-nil.
+func (srv *Server) setUnencryptedHTTP2Defaults(conf *http2Server) {
+	if !srv.protocols().UnencryptedHTTP2() || strings.Contains(os.Getenv("GODEBUG"), "http2server=0") {
+		return
+	}
+	if conf == nil {
+		conf = srv.newHTTP2Server()
+		// ConfigureServer also sets up HTTP/2 over TLS, which srv does
+		// not want, so apply it to a scratch Server. Keep the shutdown
+		// hook it registers there, which lets Shutdown close the
+		// unencrypted HTTP/2 connections gracefully.
+		scratch := &Server{IdleTimeout: srv.IdleTimeout, ReadTimeout: srv.ReadTimeout}
+		if err := http2ConfigureServer(scratch, conf); err != nil {
+			srv.nextProtoErr = err
+			return
+		}
+		for _, f := range scratch.onShutdown {
+			srv.RegisterOnShutdown(f)
+		}
+	}
+	srv.unencryptedHTTP2 = conf
+}
+
+// newHTTP2Server returns the bundled HTTP/2 server configured from
+// srv.HTTP2.
+func (srv *Server) newHTTP2Server() *http2Server {
+	conf := &http2Server{
+		NewWriteScheduler: func() http2WriteScheduler { return http2NewPriorityWriteScheduler(nil) },
+	}
+	if c := srv.HTTP2; c != nil {
+		conf.MaxConcurrentStreams = uint32(h2ConfigValue(c.MaxConcurrentStreams, 1))
+		conf.MaxReadFrameSize = uint32(h2ConfigValue(c.MaxReadFrameSize, http2minMaxFrameSize))
+		conf.MaxUploadBufferPerConnection = h2ConfigValue(c.MaxReceiveBufferPerConnection, http2initialWindowSize)
+		conf.MaxUploadBufferPerStream = h2ConfigValue(c.MaxReceiveBufferPerStream, http2initialWindowSize)
+	}
+	return conf
+}
+
+func (srv *Server) protocols() Protocols {
+	if srv.Protocols != nil {
+		return *srv.Protocols
+	}
+	return defaultProtocols()
+}
+
+// MaxBytesHandler returns a Handler that runs h with its Request.Body
+// limited to n bytes by MaxBytesReader.
+//
+// A request whose Content-Length exceeds n is answered with a 413
+// Request Entity Too Large error without calling h. If h reads beyond
+// the limit and returns without writing a response, MaxBytesHandler
+// replies with the same error. Handlers can detect the limit with a
+// type assertion on the error from reading the body, which is a
+// *MaxBytesError.
+func MaxBytesHandler(h Handler, n int64) Handler {
+	return HandlerFunc(func(w ResponseWriter, r *Request) {
+		if r.ContentLength > n {
+			Error(w, StatusText(StatusRequestEntityTooLarge), StatusRequestEntityTooLarge)
+			return
+		}
+		if r.Body == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		r2 := *r
+		mbr := &maxBytesReader{w: w, r: r.Body, i: n, n: n}
+		r2.Body = mbr
+		h.ServeHTTP(w, &r2)
+		if _, ok := mbr.err.(*MaxBytesError); ok && !headerWritten(w) {
+			Error(w, StatusText(StatusRequestEntityTooLarge), StatusRequestEntityTooLarge)
+		}
+	})
+}
+
+// headerWritten reports whether a response header has been written
+// to w. It assumes that it has for ResponseWriters of other packages
+// that it can't inspect.
+func headerWritten(w ResponseWriter) bool {
+	for {
+		switch t := w.(type) {
+		case *response:
+			return t.wroteHeader
+		case *http2responseWriter:
+			return t.rws == nil || t.rws.wroteHeader
+		case rwUnwrapper:
+			w = t.Unwrap()
+		default:
+			return true
+		}
+	}
 }
 
 // TimeoutHandler returns a Handler that runs h with the given time limit.
@@ -3420,6 +3674,12 @@ func (w checkConnErrorWriter) Write(p []byte) (n int, err error) {
 		w.c.werr = err
 		w.c.cancelCtx()
 	}
+	if n > 0 && w.c.trace != nil && w.c.trace.WroteFirstResponseByte != nil {
+		if r, _ := w.c.curReq.Load().(*response); r != nil && !r.wroteFirstByte {
+			r.wroteFirstByte = true
+			w.c.trace.WroteFirstResponseByte()
+		}
+	}
 	return
 }
 
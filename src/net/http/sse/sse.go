@@ -0,0 +1,298 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sse implements Server-Sent Events, a protocol for pushing a
+// stream of events from an HTTP server to a client over a long-lived
+// response, as specified by the HTML Living Standard
+// (https://html.spec.whatwg.org/multipage/server-sent-events.html).
+//
+// A Handler sends events with a Writer:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		sw, err := sse.NewWriter(w)
+//		if err != nil {
+//			http.Error(w, err.Error(), http.StatusInternalServerError)
+//			return
+//		}
+//		for _, e := range eventsAfter(sse.LastEventID(r)) {
+//			if err := sw.Send(e); err != nil {
+//				return
+//			}
+//		}
+//	}
+//
+// A client reads them with a Reader.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An Event is a message of an event stream.
+type Event struct {
+	// ID is the event's ID. A client that reconnects sends the
+	// last ID it received in the Last-Event-ID header, so that the
+	// server can resume the stream after that event.
+	//
+	// When sending, an empty ID leaves the client's last event ID
+	// unchanged. When reading, ID is the last event ID in effect
+	// for the event, which may have been set by an earlier one.
+	ID string
+
+	// Type is the event's type. An empty Type is the default
+	// type, "message".
+	Type string
+
+	// Data is the event's payload. It may span several lines.
+	Data string
+
+	// Retry, if positive, is the time the client should wait
+	// before reconnecting if the stream is interrupted. It is
+	// sent in milliseconds.
+	Retry time.Duration
+}
+
+// A Writer writes an event stream to an http.ResponseWriter.
+//
+// A Writer is not safe for concurrent use.
+type Writer struct {
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	buf bytes.Buffer
+}
+
+// NewWriter starts an event stream on w. It sets the Content-Type and
+// Cache-Control headers of the response, writes the response header
+// with status 200 and flushes it to the client.
+//
+// NewWriter returns an error, before writing anything, if w does not
+// support flushing.
+func NewWriter(w http.ResponseWriter) (*Writer, error) {
+	sw := &Writer{
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Del("Content-Length")
+	if err := sw.rc.Flush(); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+var (
+	errInvalidID   = errors.New("sse: event ID contains a newline or NUL character")
+	errInvalidType = errors.New("sse: event type contains a newline character")
+)
+
+// Send writes e to the stream and flushes it to the client.
+func (w *Writer) Send(e Event) error {
+	if strings.ContainsAny(e.ID, "\r\n\x00") {
+		return errInvalidID
+	}
+	if strings.ContainsAny(e.Type, "\r\n") {
+		return errInvalidType
+	}
+	w.buf.Reset()
+	if e.ID != "" {
+		w.writeField("id", e.ID)
+	}
+	if e.Type != "" {
+		w.writeField("event", e.Type)
+	}
+	if e.Retry > 0 {
+		w.writeField("retry", strconv.FormatInt(int64(e.Retry/time.Millisecond), 10))
+	}
+	data := strings.Replace(e.Data, "\r\n", "\n", -1)
+	data = strings.Replace(data, "\r", "\n", -1)
+	for _, line := range strings.Split(data, "\n") {
+		w.writeField("data", line)
+	}
+	w.buf.WriteByte('\n')
+	return w.flush()
+}
+
+// Comment writes a comment line to the stream and flushes it to the
+// client. Clients ignore comments; they are useful to keep idle
+// connections open. Newlines in text are replaced by spaces.
+func (w *Writer) Comment(text string) error {
+	w.buf.Reset()
+	w.buf.WriteString(": ")
+	w.buf.WriteString(strings.Map(func(r rune) rune {
+		if r == '\r' || r == '\n' {
+			return ' '
+		}
+		return r
+	}, text))
+	w.buf.WriteByte('\n')
+	return w.flush()
+}
+
+func (w *Writer) writeField(name, value string) {
+	w.buf.WriteString(name)
+	w.buf.WriteString(": ")
+	w.buf.WriteString(value)
+	w.buf.WriteByte('\n')
+}
+
+func (w *Writer) flush() error {
+	if _, err := w.w.Write(w.buf.Bytes()); err != nil {
+		return err
+	}
+	return w.rc.Flush()
+}
+
+// LastEventID returns the ID of the last event that the client sending r
+// received before it reconnected, or "" if it is a new stream.
+func LastEventID(r *http.Request) string {
+	return r.Header.Get("Last-Event-ID")
+}
+
+// DefaultReadLimit is the default maximum size of an event read by a
+// Reader. See Reader.SetReadLimit.
+const DefaultReadLimit = 1 << 20
+
+// ErrEventTooLarge is returned by Reader.Next when an event exceeds the
+// Reader's read limit.
+var ErrEventTooLarge = errors.New("sse: event too large")
+
+// A Reader reads events from an event stream.
+type Reader struct {
+	br     *bufio.Reader
+	lastID string
+	skipLF bool  // the last line ended with CR, which may start a CRLF
+	limit  int64 // maximum size of a line or of an event's data
+	err    error // sticky error after ErrEventTooLarge
+}
+
+// NewReader returns a Reader reading the event stream from r, such as
+// the body of a response with Content-Type text/event-stream.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{br: bufio.NewReader(r), limit: DefaultReadLimit}
+}
+
+// SetReadLimit sets the maximum size in bytes of an event read from the
+// stream: both the size of its data and the length of any line of the
+// stream are limited to n. If an event exceeds the limit, Next returns
+// ErrEventTooLarge, and the Reader can't be used any further. The
+// default is DefaultReadLimit.
+func (r *Reader) SetReadLimit(n int64) {
+	r.limit = n
+}
+
+// LastEventID returns the ID of the last event read, which a client
+// reconnecting to the stream sends in the Last-Event-ID header.
+func (r *Reader) LastEventID() string {
+	return r.lastID
+}
+
+// Next reads the next event from the stream. It returns io.EOF at the
+// end of the stream; an event that is not complete when the stream ends
+// is discarded.
+func (r *Reader) Next() (Event, error) {
+	if r.err != nil {
+		return Event{}, r.err
+	}
+	var (
+		e       Event
+		data    strings.Builder
+		hasData bool
+	)
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			if err == ErrEventTooLarge {
+				r.err = err
+			}
+			return Event{}, err
+		}
+		if line == "" {
+			if !hasData {
+				// Nothing to dispatch; keep the retry hint
+				// for the next event.
+				e.Type = ""
+				continue
+			}
+			e.ID = r.lastID
+			e.Data = strings.TrimSuffix(data.String(), "\n")
+			return e, nil
+		}
+		if line[0] == ':' {
+			continue
+		}
+		name, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			name, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch name {
+		case "event":
+			e.Type = value
+		case "data":
+			if int64(data.Len()+len(value)) > r.limit {
+				r.err = ErrEventTooLarge
+				return Event{}, r.err
+			}
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				r.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				e.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// readLine reads a line ending with CRLF, LF or CR and returns it without
+// its ending. It returns io.ErrUnexpectedEOF for an unterminated line,
+// and ErrEventTooLarge for a line longer than the read limit.
+func (r *Reader) readLine() (string, error) {
+	var line []byte
+	for {
+		c, err := r.br.ReadByte()
+		if err == io.EOF {
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+			return "", io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return "", err
+		}
+		skipLF := r.skipLF
+		r.skipLF = false
+		switch c {
+		case '\n':
+			if skipLF && len(line) == 0 {
+				continue
+			}
+			return string(line), nil
+		case '\r':
+			// Don't wait for a possible LF, as the CR may end
+			// the last line sent for a while.
+			r.skipLF = true
+			return string(line), nil
+		}
+		if int64(len(line)) >= r.limit {
+			return "", ErrEventTooLarge
+		}
+		line = append(line, c)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w, err := NewWriter(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Send(Event{ID: "1", Type: "update", Data: "a\nb\r\nc", Retry: 1500 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Comment("keep\nalive"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Send(Event{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Send(Event{ID: "bad\nid"}); err != errInvalidID {
+		t.Errorf("Send with invalid ID: err = %v; want %v", err, errInvalidID)
+	}
+	if err := w.Send(Event{Type: "bad\rtype"}); err != errInvalidType {
+		t.Errorf("Send with invalid Type: err = %v; want %v", err, errInvalidType)
+	}
+
+	if got, want := rec.Header().Get("Content-Type"), "text/event-stream"; got != want {
+		t.Errorf("Content-Type = %q; want %q", got, want)
+	}
+	if !rec.Flushed {
+		t.Errorf("response not flushed")
+	}
+	want := "id: 1\nevent: update\nretry: 1500\ndata: a\ndata: b\ndata: c\n\n" +
+		": keep alive\n" +
+		"data: \n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("stream:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestReader(t *testing.T) {
+	const stream = ": comment\n" +
+		"data: first\n\n" +
+		"id: 7\r\nevent: add\r\ndata:two\r\ndata:  lines\r\n\r\n" +
+		"retry: 3000\rdata\r\r" +
+		"id\nevent: ignored\n\n" +
+		"id: x\x00\ndata: id kept\nretry: soon\nunknown: field\n\n" +
+		"data: incomplete"
+	want := []Event{
+		{Data: "first"},
+		{ID: "7", Type: "add", Data: "two\n lines"},
+		{ID: "7", Retry: 3 * time.Second},
+		{Data: "id kept"},
+	}
+	r := NewReader(strings.NewReader(stream))
+	var got []Event
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events:\n%+v\nwant:\n%+v", got, want)
+	}
+	if r.LastEventID() != "" {
+		t.Errorf("LastEventID = %q; want empty", r.LastEventID())
+	}
+}
+
+func TestReaderLimit(t *testing.T) {
+	tests := []struct {
+		name, stream string
+		want         []string
+	}{
+		{"fits", "data: 0123456789\n\n", []string{"0123456789"}},
+		{"long line", "data: 0123456789x\n\n", nil},
+		{"long event", strings.Repeat("data: 01234\n", 3) + "\n", nil},
+		{"endless line", "data: " + strings.Repeat("x", 100), nil},
+		{"comments", strings.Repeat(": 0123456789\n\n", 10) + "data: ok\n\n", []string{"ok"}},
+	}
+	for _, tt := range tests {
+		r := NewReader(strings.NewReader(tt.stream))
+		r.SetReadLimit(16)
+		var got []string
+		var err error
+		for {
+			var e Event
+			if e, err = r.Next(); err != nil {
+				break
+			}
+			got = append(got, e.Data)
+		}
+		wantErr := io.EOF
+		if tt.want == nil {
+			wantErr = ErrEventTooLarge
+		}
+		if !reflect.DeepEqual(got, tt.want) || err != wantErr {
+			t.Errorf("%s: got %q, %v; want %q, %v", tt.name, got, err, tt.want, wantErr)
+		}
+		if _, err := r.Next(); err != wantErr {
+			t.Errorf("%s: Next after error = %v; want %v", tt.name, err, wantErr)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w, err := NewWriter(rw)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		start := 1
+		if id := LastEventID(r); id != "" {
+			n, _ := strconv.Atoi(id)
+			start = n + 1
+		}
+		for i := start; i < start+2; i++ {
+			w.Send(Event{ID: strconv.Itoa(i), Data: "event " + strconv.Itoa(i)})
+		}
+	}))
+	defer ts.Close()
+
+	read := func(lastID string) []string {
+		req, _ := http.NewRequest("GET", ts.URL, nil)
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		res, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var data []string
+		r := NewReader(res.Body)
+		for {
+			e, err := r.Next()
+			if err != nil {
+				break
+			}
+			data = append(data, e.Data)
+			lastID = e.ID
+		}
+		return append(data, "last="+lastID)
+	}
+	if got, want := read(""), []string{"event 1", "event 2", "last=2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first stream = %q; want %q", got, want)
+	}
+	if got, want := read("2"), []string{"event 3", "event 4", "last=4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("resumed stream = %q; want %q", got, want)
+	}
+}
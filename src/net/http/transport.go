@@ -21,6 +21,7 @@ import (
 	"log"
 	"net"
 	"net/http/httptrace"
+	"net/http/internal"
 	"net/textproto"
 	"net/url"
 	"os"
@@ -186,7 +187,7 @@ type Transport struct {
 	// its own and gets a gzipped response, it's transparently
 	// decoded in the Response.Body. However, if the user
 	// explicitly requested gzip it is not automatically
-	// uncompressed.
+	// uncompressed. See also MaxDecompressedResponseBytes.
 	DisableCompression bool
 
 	// MaxIdleConns controls the maximum number of idle (keep-alive)
@@ -254,6 +255,13 @@ type Transport struct {
 	// Zero means to use a default limit.
 	MaxResponseHeaderBytes int64
 
+	// MaxDecompressedResponseBytes, if non-zero, limits the size
+	// of response bodies that the Transport transparently
+	// decompresses, as described for DisableCompression, to
+	// protect against decompression bombs. Reads beyond the limit
+	// return an error.
+	MaxDecompressedResponseBytes int64
+
 	// WriteBufferSize specifies the size of the write buffer used
 	// when writing to the transport.
 	// If zero, a default (currently 4KB) is used.
@@ -267,14 +275,36 @@ type Transport struct {
 	// nextProtoOnce guards initialization of TLSNextProto and
 	// h2transport (via onceSetNextProtoDefaults)
 	nextProtoOnce      sync.Once
-	h2transport        h2Transport // non-nil if http2 wired up
-	tlsNextProtoWasNil bool        // whether TLSNextProto was nil when the Once fired
+	h2transport        h2Transport     // non-nil if http2 wired up
+	h2cTransport       *http2Transport // non-nil if unencrypted HTTP/2 is used for http URLs
+	tlsNextProtoWasNil bool            // whether TLSNextProto was nil when the Once fired
 
 	// ForceAttemptHTTP2 controls whether HTTP/2 is enabled when a non-zero
 	// TLSClientConfig or Dial, DialTLS or DialContext func is provided. By default, use of any those fields conservatively
 	// disables HTTP/2. To use a customer dialer or TLS config and still attempt HTTP/2
 	// upgrades, set this to true.
 	ForceAttemptHTTP2 bool
+
+	// HTTP2 configures HTTP/2 connections.
+	// If nil, defaults are used.
+	HTTP2 *HTTP2Config
+
+	// Protocols is the set of protocols supported by the transport.
+	//
+	// If Protocols includes UnencryptedHTTP2 and does not include HTTP1,
+	// the transport uses unencrypted HTTP/2 with prior knowledge for
+	// requests with the "http" scheme. Such connections are dialed
+	// directly, ignoring Proxy.
+	//
+	// If Protocols includes HTTP2, HTTP/2 over TLS is attempted even when
+	// a custom TLS configuration or dialer is used, as with
+	// ForceAttemptHTTP2. If Protocols does not include HTTP2, HTTP/2 over
+	// TLS is not used. If Protocols does not include HTTP1, connections
+	// that do not negotiate HTTP/2 fail.
+	//
+	// If Protocols is nil, the default is HTTP/1, plus HTTP/2 when it is
+	// enabled as described for ForceAttemptHTTP2 and TLSNextProto.
+	Protocols *Protocols
 }
 
 func (t *Transport) writeBufferSize() int {
@@ -295,25 +325,34 @@ func (t *Transport) readBufferSize() int {
 func (t *Transport) Clone() *Transport {
 	t.nextProtoOnce.Do(t.onceSetNextProtoDefaults)
 	t2 := &Transport{
-		Proxy:                  t.Proxy,
-		DialContext:            t.DialContext,
-		Dial:                   t.Dial,
-		DialTLS:                t.DialTLS,
-		TLSClientConfig:        t.TLSClientConfig.Clone(),
-		TLSHandshakeTimeout:    t.TLSHandshakeTimeout,
-		DisableKeepAlives:      t.DisableKeepAlives,
-		DisableCompression:     t.DisableCompression,
-		MaxIdleConns:           t.MaxIdleConns,
-		MaxIdleConnsPerHost:    t.MaxIdleConnsPerHost,
-		MaxConnsPerHost:        t.MaxConnsPerHost,
-		IdleConnTimeout:        t.IdleConnTimeout,
-		ResponseHeaderTimeout:  t.ResponseHeaderTimeout,
-		ExpectContinueTimeout:  t.ExpectContinueTimeout,
-		ProxyConnectHeader:     t.ProxyConnectHeader.Clone(),
-		MaxResponseHeaderBytes: t.MaxResponseHeaderBytes,
-		ForceAttemptHTTP2:      t.ForceAttemptHTTP2,
-		WriteBufferSize:        t.WriteBufferSize,
-		ReadBufferSize:         t.ReadBufferSize,
+		Proxy:                        t.Proxy,
+		DialContext:                  t.DialContext,
+		Dial:                         t.Dial,
+		DialTLS:                      t.DialTLS,
+		TLSClientConfig:              t.TLSClientConfig.Clone(),
+		TLSHandshakeTimeout:          t.TLSHandshakeTimeout,
+		DisableKeepAlives:            t.DisableKeepAlives,
+		DisableCompression:           t.DisableCompression,
+		MaxIdleConns:                 t.MaxIdleConns,
+		MaxIdleConnsPerHost:          t.MaxIdleConnsPerHost,
+		MaxConnsPerHost:              t.MaxConnsPerHost,
+		IdleConnTimeout:              t.IdleConnTimeout,
+		ResponseHeaderTimeout:        t.ResponseHeaderTimeout,
+		ExpectContinueTimeout:        t.ExpectContinueTimeout,
+		ProxyConnectHeader:           t.ProxyConnectHeader.Clone(),
+		MaxResponseHeaderBytes:       t.MaxResponseHeaderBytes,
+		MaxDecompressedResponseBytes: t.MaxDecompressedResponseBytes,
+		ForceAttemptHTTP2:            t.ForceAttemptHTTP2,
+		WriteBufferSize:              t.WriteBufferSize,
+		ReadBufferSize:               t.ReadBufferSize,
+	}
+	if t.HTTP2 != nil {
+		c := *t.HTTP2
+		t2.HTTP2 = &c
+	}
+	if t.Protocols != nil {
+		p := *t.Protocols
+		t2.Protocols = &p
 	}
 	if !t.tlsNextProtoWasNil {
 		npm := map[string]func(authority string, c *tls.Conn) RoundTripper{}
@@ -343,6 +382,10 @@ func (t *Transport) onceSetNextProtoDefaults() {
 		return
 	}
 
+	if p := t.Protocols; p != nil && p.UnencryptedHTTP2() && !p.HTTP1() {
+		t.h2cTransport = t.newUnencryptedHTTP2Transport()
+	}
+
 	// If they've already configured http2 with
 	// golang.org/x/net/http2 instead of the bundled copy, try to
 	// get at its http2.Transport value (via the "https"
@@ -363,13 +406,17 @@ func (t *Transport) onceSetNextProtoDefaults() {
 		// Transport.
 		return
 	}
-	if !t.ForceAttemptHTTP2 && (t.TLSClientConfig != nil || t.Dial != nil || t.DialTLS != nil || t.DialContext != nil) {
+	if t.Protocols != nil && !t.Protocols.HTTP2() {
+		return
+	}
+	if !t.ForceAttemptHTTP2 && t.Protocols == nil && (t.TLSClientConfig != nil || t.Dial != nil || t.DialTLS != nil || t.DialContext != nil) {
 		// Be conservative and don't automatically enable
 		// http2 if they've specified a custom TLS config or
 		// custom dialers. Let them opt-in themselves via
 		// http2.ConfigureTransport so we don't surprise them
 		// by modifying their tls.Config. Issue 14275.
-		// However, if ForceAttemptHTTP2 is true, it overrides the above checks.
+		// However, if ForceAttemptHTTP2 is true or Protocols is set,
+		// it overrides the above checks.
 		return
 	}
 	t2, err := http2configureTransport(t)
@@ -378,6 +425,7 @@ func (t *Transport) onceSetNextProtoDefaults() {
 		return
 	}
 	t.h2transport = t2
+	t.configureHTTP2(t2)
 
 	// Auto-configure the http2.Transport's MaxHeaderListSize from
 	// the http.Transport's MaxResponseHeaderBytes. They don't
@@ -395,6 +443,100 @@ func (t *Transport) onceSetNextProtoDefaults() {
 	}
 }
 
+// configureHTTP2 applies t.HTTP2 to t2.
+func (t *Transport) configureHTTP2(t2 *http2Transport) {
+	c := t.HTTP2
+	if c == nil {
+		return
+	}
+	t2.MaxHeaderListSize = uint32(h2ConfigValue(c.MaxHeaderListSize, 1))
+}
+
+// newUnencryptedHTTP2Transport returns an HTTP/2 transport that sends
+// requests with the "http" scheme over plain TCP connections.
+func (t *Transport) newUnencryptedHTTP2Transport() *http2Transport {
+	t2 := &http2Transport{
+		AllowHTTP: true,
+		t1:        t,
+	}
+	t2.ConnPool = &unencryptedHTTP2ConnPool{
+		t:     t,
+		t2:    t2,
+		conns: make(map[string][]*http2ClientConn),
+	}
+	t.configureHTTP2(t2)
+	if limit1 := t.MaxResponseHeaderBytes; limit1 != 0 && t2.MaxHeaderListSize == 0 && limit1 < 1<<32-1 {
+		t2.MaxHeaderListSize = uint32(limit1)
+	}
+	return t2
+}
+
+// unencryptedHTTP2ConnPool is the connection pool of the transport
+// used for unencrypted HTTP/2. It dials new connections with the
+// context of the request that needs them, as dialConn does for HTTP/1,
+// so that canceling the request also cancels the dial.
+type unencryptedHTTP2ConnPool struct {
+	t  *Transport
+	t2 *http2Transport
+
+	mu    sync.Mutex
+	conns map[string][]*http2ClientConn // key is host:port
+}
+
+func (p *unencryptedHTTP2ConnPool) GetClientConn(req *Request, addr string) (*http2ClientConn, error) {
+	p.mu.Lock()
+	for _, cc := range p.conns[addr] {
+		if cc.CanTakeNewRequest() {
+			p.mu.Unlock()
+			return cc, nil
+		}
+	}
+	p.mu.Unlock()
+
+	conn, err := p.t.dial(req.Context(), "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := p.t2.NewClientConn(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	p.mu.Lock()
+	p.conns[addr] = append(p.conns[addr], cc)
+	p.mu.Unlock()
+	return cc, nil
+}
+
+func (p *unencryptedHTTP2ConnPool) MarkDead(cc *http2ClientConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for addr, conns := range p.conns {
+		for i, c := range conns {
+			if c != cc {
+				continue
+			}
+			conns = append(conns[:i], conns[i+1:]...)
+			if len(conns) == 0 {
+				delete(p.conns, addr)
+			} else {
+				p.conns[addr] = conns
+			}
+			return
+		}
+	}
+}
+
+func (p *unencryptedHTTP2ConnPool) closeIdleConnections() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conns := range p.conns {
+		for _, cc := range conns {
+			cc.closeIfIdle()
+		}
+	}
+}
+
 // ProxyFromEnvironment returns the URL of the proxy to use for a
 // given request, as indicated by the environment variables
 // HTTP_PROXY, HTTPS_PROXY and NO_PROXY (or the lowercase versions
@@ -496,6 +638,9 @@ func (t *Transport) roundTrip(req *Request) (*Response, error) {
 		altProto, _ := t.altProto.Load().(map[string]RoundTripper)
 		if altRT := altProto[scheme]; altRT != nil {
 			if resp, err := altRT.RoundTrip(req); err != ErrSkipAltProtocol {
+				if err == nil {
+					t.limitDecompressedBody(resp)
+				}
 				return resp, err
 			}
 		}
@@ -511,6 +656,13 @@ func (t *Transport) roundTrip(req *Request) (*Response, error) {
 		req.closeBody()
 		return nil, errors.New("http: no Host in request URL")
 	}
+	if scheme == "http" && t.h2cTransport != nil {
+		resp, err := t.h2cTransport.RoundTrip(req)
+		if err == nil {
+			t.limitDecompressedBody(resp)
+		}
+		return resp, err
+	}
 
 	for {
 		select {
@@ -549,6 +701,7 @@ func (t *Transport) roundTrip(req *Request) (*Response, error) {
 			resp, err = pconn.roundTrip(treq)
 		}
 		if err == nil {
+			t.limitDecompressedBody(resp)
 			return resp, nil
 		}
 		if http2isNoCachedConnError(err) {
@@ -676,6 +829,9 @@ func (t *Transport) CloseIdleConnections() {
 	if t2 := t.h2transport; t2 != nil {
 		t2.CloseIdleConnections()
 	}
+	if t2 := t.h2cTransport; t2 != nil {
+		t2.CloseIdleConnections()
+	}
 }
 
 // CancelRequest cancels an in-flight request by closing its connection.
@@ -869,11 +1025,11 @@ func (t *Transport) tryPutIdleConn(pconn *persistConn) error {
 		} else {
 			// idleTimer does not apply to HTTP/2
 			if pconn.alt == nil {
-				pconn.idleTimer = time.AfterFunc(t.IdleConnTimeout, pconn.closeConnIfStillIdle)
+				pconn.idleTimer = pconn.clock().AfterFunc(t.IdleConnTimeout, pconn.closeConnIfStillIdle)
 			}
 		}
 	}
-	pconn.idleAt = time.Now()
+	pconn.idleAt = pconn.clock().Now()
 	return nil
 }
 
@@ -1250,9 +1406,9 @@ func (pconn *persistConn) addTLS(name string, trace *httptrace.ClientTrace) erro
 	plainConn := pconn.conn
 	tlsConn := tls.Client(plainConn, cfg)
 	errc := make(chan error, 2)
-	var timer *time.Timer // for canceling TLS handshake
+	var timer internal.Timer // for canceling TLS handshake
 	if d := pconn.t.TLSHandshakeTimeout; d != 0 {
-		timer = time.AfterFunc(d, func() {
+		timer = pconn.clock().AfterFunc(d, func() {
 			errc <- tlsHandshakeTimeoutError{}
 		})
 	}
@@ -1309,6 +1465,7 @@ func (t *Transport) dialConn(ctx context.Context, cm connectMethod) (*persistCon
 		if pconn.conn == nil {
 			return nil, wrapErr(errors.New("net/http: Transport.DialTLS returned (nil, nil)"))
 		}
+		pconn.clk = connClock(pconn.conn)
 		if tc, ok := pconn.conn.(*tls.Conn); ok {
 			// Handshake here, in case DialTLS didn't. TLSNextProto below
 			// depends on it for knowing the connection state.
@@ -1334,6 +1491,7 @@ func (t *Transport) dialConn(ctx context.Context, cm connectMethod) (*persistCon
 			return nil, wrapErr(err)
 		}
 		pconn.conn = conn
+		pconn.clk = connClock(conn)
 		if cm.scheme() == "https" {
 			var firstTLSHost string
 			if firstTLSHost, _, err = net.SplitHostPort(cm.addr()); err != nil {
@@ -1421,6 +1579,10 @@ func (t *Transport) dialConn(ctx context.Context, cm connectMethod) (*persistCon
 			return &persistConn{t: t, cacheKey: pconn.cacheKey, alt: next(cm.targetAddr, pconn.conn.(*tls.Conn))}, nil
 		}
 	}
+	if t.Protocols != nil && !t.Protocols.HTTP1() {
+		pconn.conn.Close()
+		return nil, errors.New("net/http: HTTP/1 is disabled and the server did not negotiate HTTP/2")
+	}
 
 	if t.MaxConnsPerHost > 0 {
 		pconn.conn = &connCloseListener{Conn: pconn.conn, t: t, cmKey: pconn.cacheKey}
@@ -1576,9 +1738,13 @@ type persistConn struct {
 
 	writeLoopDone chan struct{} // closed when write loop ends
 
+	// clk measures time for the connection's timeouts, if it is not
+	// the system clock. See connClock.
+	clk internal.Clock
+
 	// Both guarded by Transport.idleMu:
-	idleAt    time.Time   // time it last become idle
-	idleTimer *time.Timer // holding an AfterFunc to close it
+	idleAt    time.Time      // time it last become idle
+	idleTimer internal.Timer // holding an AfterFunc to close it
 
 	mu                   sync.Mutex // guards following fields
 	numExpectedResponses int
@@ -1645,7 +1811,7 @@ func (pc *persistConn) gotIdleConnTrace(idleAt time.Time) (t httptrace.GotConnIn
 	t.Conn = pc.conn
 	t.WasIdle = true
 	if !idleAt.IsZero() {
-		t.IdleTime = time.Since(idleAt)
+		t.IdleTime = pc.clock().Now().Sub(idleAt)
 	}
 	return
 }
@@ -1978,6 +2144,21 @@ func (pc *persistConn) readResponse(rc requestAndChan, trace *httptrace.ClientTr
 	return
 }
 
+// clock returns the clock measuring time for pc's timeouts.
+func (pc *persistConn) clock() internal.Clock {
+	if pc.clk != nil {
+		return pc.clk
+	}
+	return internal.RealClock
+}
+
+// newTimer returns a timer on pc's clock, and a channel that is
+// closed when it fires.
+func (pc *persistConn) newTimer(d time.Duration) (internal.Timer, <-chan struct{}) {
+	c := make(chan struct{})
+	return pc.clock().AfterFunc(d, func() { close(c) }), c
+}
+
 // waitForContinue returns the function to block until
 // any response, timeout or connection close. After any of them,
 // the function returns a bool which indicates if the body should be sent.
@@ -1986,13 +2167,13 @@ func (pc *persistConn) waitForContinue(continueCh <-chan struct{}) func() bool {
 		return nil
 	}
 	return func() bool {
-		timer := time.NewTimer(pc.t.ExpectContinueTimeout)
+		timer, timerc := pc.newTimer(pc.t.ExpectContinueTimeout)
 		defer timer.Stop()
 
 		select {
 		case _, ok := <-continueCh:
 			return ok
-		case <-timer.C:
+		case <-timerc:
 			return true
 		case <-pc.closech:
 			return false
@@ -2264,7 +2445,7 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 		callerGone: gone,
 	}
 
-	var respHeaderTimer <-chan time.Time
+	var respHeaderTimer <-chan struct{}
 	cancelChan := req.Request.Cancel
 	ctxDoneChan := req.Context().Done()
 	for {
@@ -2282,9 +2463,9 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 				if debugRoundTrip {
 					req.logf("starting timer for %v", d)
 				}
-				timer := time.NewTimer(d)
+				timer, timerc := pc.newTimer(d)
 				defer timer.Stop() // prevent leaks
-				respHeaderTimer = timer.C
+				respHeaderTimer = timerc
 			}
 		case <-pc.closech:
 			if debugRoundTrip {
@@ -2456,6 +2637,16 @@ func (es *bodyEOFSignal) condfn(err error) error {
 	return err
 }
 
+var errDecompressedResponseTooLarge = errors.New("net/http: decompressed response body too large")
+
+// limitDecompressedBody applies t.MaxDecompressedResponseBytes to
+// resp, if its body was decompressed by the Transport.
+func (t *Transport) limitDecompressedBody(resp *Response) {
+	if n := t.MaxDecompressedResponseBytes; n > 0 && resp.Uncompressed {
+		resp.Body = &maxBytesReader{r: resp.Body, i: n, n: n, tooLargeErr: errDecompressedResponseTooLarge}
+	}
+}
+
 // gzipReader wraps a response body so it can lazily
 // call gzip.NewReader on the first call to Read
 type gzipReader struct {
@@ -1095,6 +1095,53 @@ func TestTransportGzip(t *testing.T) {
 	}
 }
 
+func TestTransportMaxDecompressedResponseBytes_h1(t *testing.T) {
+	testTransportMaxDecompressedResponseBytes(t, h1Mode)
+}
+func TestTransportMaxDecompressedResponseBytes_h2(t *testing.T) {
+	testTransportMaxDecompressedResponseBytes(t, h2Mode)
+}
+
+func testTransportMaxDecompressedResponseBytes(t *testing.T, h2 bool) {
+	setParallel(t)
+	defer afterTest(t)
+	const size = 1 << 20
+	cst := newClientServerTest(t, h2, HandlerFunc(func(rw ResponseWriter, req *Request) {
+		if req.Header.Get("Accept-Encoding") != "gzip" {
+			rw.Write(make([]byte, size))
+			return
+		}
+		rw.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(rw)
+		gz.Write(make([]byte, size))
+		gz.Close()
+	}))
+	defer cst.close()
+
+	cst.tr.MaxDecompressedResponseBytes = size - 1
+	for _, disable := range []bool{false, true} {
+		cst.tr.DisableCompression = disable
+		// Make several requests, so that the later ones
+		// reuse the connection of the first.
+		for i := 0; i < 2; i++ {
+			res, err := cst.c.Get(cst.ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, err := io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+			if disable {
+				// Uncompressed responses are not limited.
+				if err != nil || n != size {
+					t.Errorf("uncompressed, request %d: read %d bytes, %v; want %d bytes", i, n, err, size)
+				}
+			} else if err == nil || n != size-1 {
+				t.Errorf("decompressed, request %d: read %d bytes, %v; want %d bytes and an error", i, n, err, size-1)
+			}
+		}
+	}
+}
+
 // If a request has Expect:100-continue header, the request blocks sending body until the first response.
 // Premature consumption of the request body should not be occurred.
 func TestTransportExpect100Continue(t *testing.T) {
@@ -4451,6 +4498,74 @@ func testTransportIdleConnTimeout(t *testing.T, h2 bool) {
 	}
 }
 
+// Tests IdleConnTimeout and ResponseHeaderTimeout with a fake clock,
+// without waiting for real time to pass.
+func TestTransportTimeoutsFakeClock(t *testing.T) {
+	defer afterTest(t)
+	clock := httptest.NewFakeClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	network := &httptest.Network{Clock: clock}
+	unblock := make(chan bool)
+	ts := network.NewServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		if r.URL.Path == "/slow" {
+			<-unblock
+		}
+	}))
+	defer ts.Close()
+	defer close(unblock)
+	c := ts.Client()
+	tr := c.Transport.(*Transport)
+	tr.IdleConnTimeout = time.Minute
+	tr.ResponseHeaderTimeout = time.Hour
+
+	get := func() {
+		t.Helper()
+		res, err := c.Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+	waitIdle := func() {
+		t.Helper()
+		if !waitCondition(5*time.Second, 5*time.Millisecond, func() bool {
+			return tr.IdleConnCountForTesting("http", ts.Listener.Addr().String()) == 1
+		}) {
+			t.Fatal("connection did not become idle")
+		}
+	}
+	get()
+	waitIdle()
+	clock.Advance(59 * time.Second)
+	if n := tr.IdleConnCountForTesting("http", ts.Listener.Addr().String()); n != 1 {
+		t.Fatalf("before IdleConnTimeout: %d idle conns; want 1", n)
+	}
+	clock.Advance(time.Second)
+	if n := tr.IdleConnCountForTesting("http", ts.Listener.Addr().String()); n != 0 {
+		t.Errorf("after IdleConnTimeout: %d idle conns; want 0", n)
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		res, err := c.Get(ts.URL + "/slow")
+		if err == nil {
+			res.Body.Close()
+		}
+		errc <- err
+	}()
+	for {
+		select {
+		case err := <-errc:
+			if err == nil || !strings.Contains(err.Error(), "timeout awaiting response headers") {
+				t.Errorf("Get error = %v; want response header timeout", err)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+			// The timer starts once the request is written.
+			clock.Advance(time.Hour)
+		}
+	}
+}
+
 // Issue 16208: Go 1.7 crashed after Transport.IdleConnTimeout if an
 // HTTP/2 connection was established but its caller no longer
 // wanted it. (Assuming the connection cache was enabled, which it is
@@ -5324,28 +5439,31 @@ func TestTransportRequestWriteRoundTrip(t *testing.T) {
 
 func TestTransportClone(t *testing.T) {
 	tr := &Transport{
-		Proxy:                  func(*Request) (*url.URL, error) { panic("") },
-		DialContext:            func(ctx context.Context, network, addr string) (net.Conn, error) { panic("") },
-		Dial:                   func(network, addr string) (net.Conn, error) { panic("") },
-		DialTLS:                func(network, addr string) (net.Conn, error) { panic("") },
-		TLSClientConfig:        new(tls.Config),
-		TLSHandshakeTimeout:    time.Second,
-		DisableKeepAlives:      true,
-		DisableCompression:     true,
-		MaxIdleConns:           1,
-		MaxIdleConnsPerHost:    1,
-		MaxConnsPerHost:        1,
-		IdleConnTimeout:        time.Second,
-		ResponseHeaderTimeout:  time.Second,
-		ExpectContinueTimeout:  time.Second,
-		ProxyConnectHeader:     Header{},
-		MaxResponseHeaderBytes: 1,
-		ForceAttemptHTTP2:      true,
+		Proxy:                        func(*Request) (*url.URL, error) { panic("") },
+		DialContext:                  func(ctx context.Context, network, addr string) (net.Conn, error) { panic("") },
+		Dial:                         func(network, addr string) (net.Conn, error) { panic("") },
+		DialTLS:                      func(network, addr string) (net.Conn, error) { panic("") },
+		TLSClientConfig:              new(tls.Config),
+		TLSHandshakeTimeout:          time.Second,
+		DisableKeepAlives:            true,
+		DisableCompression:           true,
+		MaxIdleConns:                 1,
+		MaxIdleConnsPerHost:          1,
+		MaxConnsPerHost:              1,
+		IdleConnTimeout:              time.Second,
+		ResponseHeaderTimeout:        time.Second,
+		ExpectContinueTimeout:        time.Second,
+		ProxyConnectHeader:           Header{},
+		MaxResponseHeaderBytes:       1,
+		MaxDecompressedResponseBytes: 1,
+		ForceAttemptHTTP2:            true,
 		TLSNextProto: map[string]func(authority string, c *tls.Conn) RoundTripper{
 			"foo": func(authority string, c *tls.Conn) RoundTripper { panic("") },
 		},
 		ReadBufferSize:  1,
 		WriteBufferSize: 1,
+		HTTP2:           &HTTP2Config{MaxConcurrentStreams: 1},
+		Protocols:       &Protocols{},
 	}
 	tr2 := tr.Clone()
 	rv := reflect.ValueOf(tr2).Elem()
@@ -0,0 +1,159 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"strings"
+)
+
+// The permessage-deflate extension, RFC 7692.
+//
+// Messages are always compressed without context takeover, each with a
+// fresh compressor, which needs no agreement from the peer. Messages
+// from the peer are decompressed with the last window of the messages
+// before them as a dictionary, which supports peers that do use context
+// takeover.
+
+const deflateExtension = "permessage-deflate"
+
+// deflateTail is the end of an empty stored block that the sender
+// strips from each compressed message (RFC 7692 section 7.2.1).
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff}
+
+// finalBlock is an empty final stored block. Appending it after the
+// tail lets the flate reader end cleanly with io.EOF.
+var finalBlock = []byte{0x01, 0x00, 0x00, 0xff, 0xff}
+
+// windowSize is the size of the LZ77 window of compress/flate.
+const windowSize = 1 << 15
+
+// compressMessage returns the compressed payload of a message.
+func compressMessage(p []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write(p); err != nil {
+		return nil, err
+	}
+	if err := fw.Flush(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), deflateTail), nil
+}
+
+var errMessageTooBig = errors.New("websocket: message exceeds read limit")
+
+// decompressor decompresses the messages received on a connection.
+type decompressor struct {
+	window []byte // last windowSize bytes of decompressed data
+}
+
+// decompress returns the decompressed payload p, which must not exceed
+// limit bytes.
+func (d *decompressor) decompress(p []byte, limit int64) ([]byte, error) {
+	r := io.MultiReader(bytes.NewReader(p), bytes.NewReader(deflateTail), bytes.NewReader(finalBlock))
+	fr := flate.NewReaderDict(r, d.window)
+	defer fr.Close()
+	var out bytes.Buffer
+	n, err := out.ReadFrom(io.LimitReader(fr, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > limit {
+		return nil, errMessageTooBig
+	}
+	msg := out.Bytes()
+	if len(msg) >= windowSize {
+		d.window = append(d.window[:0], msg[len(msg)-windowSize:]...)
+	} else {
+		d.window = append(d.window, msg...)
+		if len(d.window) > windowSize {
+			d.window = append(d.window[:0], d.window[len(d.window)-windowSize:]...)
+		}
+	}
+	return msg, nil
+}
+
+// An extensionOffer is one element of a Sec-WebSocket-Extensions
+// header: an extension name and its parameters.
+type extensionOffer struct {
+	name   string
+	params map[string]string
+}
+
+// parseExtensions parses the Sec-WebSocket-Extensions header fields
+// values.
+func parseExtensions(values []string) []extensionOffer {
+	var offers []extensionOffer
+	for _, v := range values {
+		for _, elem := range strings.Split(v, ",") {
+			parts := strings.Split(elem, ";")
+			name := strings.TrimSpace(parts[0])
+			if name == "" {
+				continue
+			}
+			ext := extensionOffer{name: strings.ToLower(name), params: make(map[string]string)}
+			for _, p := range parts[1:] {
+				k, val := p, ""
+				if i := strings.IndexByte(p, '='); i >= 0 {
+					k, val = p[:i], strings.Trim(strings.TrimSpace(p[i+1:]), `"`)
+				}
+				ext.params[strings.ToLower(strings.TrimSpace(k))] = val
+			}
+			offers = append(offers, ext)
+		}
+	}
+	return offers
+}
+
+// acceptDeflateOffer reports whether a server can accept a
+// permessage-deflate offer from a client.
+func acceptDeflateOffer(ext extensionOffer) bool {
+	if ext.name != deflateExtension {
+		return false
+	}
+	for k, v := range ext.params {
+		switch k {
+		case "client_no_context_takeover", "server_no_context_takeover":
+			if v != "" {
+				return false
+			}
+		case "client_max_window_bits":
+			// A hint that the client can use a smaller window
+			// than the default; the server need not use it.
+		case "server_max_window_bits":
+			// compress/flate always uses the largest window.
+			if v != "15" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkDeflateResponse reports whether a client can use the
+// permessage-deflate parameters returned by a server.
+func checkDeflateResponse(ext extensionOffer) bool {
+	for k, v := range ext.params {
+		switch k {
+		case "client_no_context_takeover", "server_no_context_takeover", "server_max_window_bits":
+		case "client_max_window_bits":
+			if v != "" && v != "15" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
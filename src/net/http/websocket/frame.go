@@ -0,0 +1,128 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Frame opcodes, from RFC 6455 section 5.2.
+type opcode byte
+
+const (
+	opContinuation opcode = 0x0
+	opText         opcode = 0x1
+	opBinary       opcode = 0x2
+	opClose        opcode = 0x8
+	opPing         opcode = 0x9
+	opPong         opcode = 0xa
+)
+
+func (op opcode) isControl() bool { return op&0x8 != 0 }
+
+// maxControlPayload is the maximum payload length of a control frame.
+const maxControlPayload = 125
+
+// A frameHeader is the header of a frame, as described in RFC 6455
+// section 5.2.
+type frameHeader struct {
+	fin    bool
+	rsv1   bool // set on the first frame of a compressed message
+	rsv23  bool // the reserved bits without a negotiated meaning
+	op     opcode
+	masked bool
+	mask   [4]byte
+	length int64
+}
+
+var errFrameTooLong = errors.New("websocket: frame payload length is invalid")
+
+// readFrameHeader reads a frame header from br.
+func readFrameHeader(br *bufio.Reader) (frameHeader, error) {
+	var h frameHeader
+	var b [8]byte
+	if _, err := io.ReadFull(br, b[:2]); err != nil {
+		return h, err
+	}
+	h.fin = b[0]&0x80 != 0
+	h.rsv1 = b[0]&0x40 != 0
+	h.rsv23 = b[0]&0x30 != 0
+	h.op = opcode(b[0] & 0xf)
+	h.masked = b[1]&0x80 != 0
+	switch n := b[1] & 0x7f; n {
+	case 126:
+		if _, err := io.ReadFull(br, b[:2]); err != nil {
+			return h, unexpectedEOF(err)
+		}
+		h.length = int64(binary.BigEndian.Uint16(b[:2]))
+	case 127:
+		if _, err := io.ReadFull(br, b[:8]); err != nil {
+			return h, unexpectedEOF(err)
+		}
+		v := binary.BigEndian.Uint64(b[:8])
+		if v>>63 != 0 {
+			return h, errFrameTooLong
+		}
+		h.length = int64(v)
+	default:
+		h.length = int64(n)
+	}
+	if h.masked {
+		if _, err := io.ReadFull(br, h.mask[:]); err != nil {
+			return h, unexpectedEOF(err)
+		}
+	}
+	return h, nil
+}
+
+// appendFrameHeader appends the encoding of h to b.
+func appendFrameHeader(b []byte, h frameHeader) []byte {
+	b0 := byte(h.op)
+	if h.fin {
+		b0 |= 0x80
+	}
+	if h.rsv1 {
+		b0 |= 0x40
+	}
+	var b1 byte
+	if h.masked {
+		b1 = 0x80
+	}
+	switch {
+	case h.length <= 125:
+		b = append(b, b0, b1|byte(h.length))
+	case h.length <= 0xffff:
+		b = append(b, b0, b1|126, byte(h.length>>8), byte(h.length))
+	default:
+		b = append(b, b0, b1|127)
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(h.length))
+		b = append(b, n[:]...)
+	}
+	if h.masked {
+		b = append(b, h.mask[:]...)
+	}
+	return b
+}
+
+// maskBytes applies the masking key to b in place, as if b started at
+// offset pos of the payload, and returns the offset following b.
+func maskBytes(key [4]byte, pos int, b []byte) int {
+	for i := range b {
+		b[i] ^= key[pos&3]
+		pos++
+	}
+	return pos & 3
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
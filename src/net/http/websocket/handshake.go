@@ -0,0 +1,302 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// keyGUID is the GUID appended to the Sec-WebSocket-Key header to
+// compute the Sec-WebSocket-Accept header (RFC 6455 section 1.3).
+const keyGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	io.WriteString(h, key)
+	io.WriteString(h, keyGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// hasToken reports whether one of the comma-separated lists in values
+// contains token, ignoring case.
+func hasToken(values []string, token string) bool {
+	for _, v := range values {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tokens returns the elements of the comma-separated lists in values.
+func tokens(values []string) []string {
+	var list []string
+	for _, v := range values {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				list = append(list, t)
+			}
+		}
+	}
+	return list
+}
+
+// AcceptOptions are the options for Accept. A nil *AcceptOptions is
+// equivalent to a zero AcceptOptions.
+type AcceptOptions struct {
+	// Subprotocols lists the subprotocols supported by the server,
+	// in order of preference. The first one that the client also
+	// supports is selected.
+	Subprotocols []string
+
+	// CheckOrigin reports whether to accept a request, given its
+	// Origin header. If nil, requests with an Origin header whose
+	// host differs from the request's Host are rejected, to protect
+	// against cross-site WebSocket hijacking by browsers.
+	CheckOrigin func(r *http.Request) bool
+
+	// EnableCompression enables the permessage-deflate extension,
+	// if the client offers it.
+	EnableCompression bool
+}
+
+// A HandshakeError is returned by Accept and Dial when the opening
+// handshake fails.
+type HandshakeError struct {
+	msg string
+}
+
+func (e *HandshakeError) Error() string { return "websocket: " + e.msg }
+
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// Accept upgrades the request r to a WebSocket connection by performing
+// the server side of the opening handshake. The connection is taken over
+// from the HTTP server with http.ResponseController.Hijack.
+//
+// If the request is not a valid WebSocket handshake, Accept replies with
+// an HTTP error and returns a *HandshakeError.
+func Accept(w http.ResponseWriter, r *http.Request, opts *AcceptOptions) (*Conn, error) {
+	if opts == nil {
+		opts = &AcceptOptions{}
+	}
+	fail := func(code int, msg string) (*Conn, error) {
+		if code == http.StatusUpgradeRequired {
+			w.Header().Set("Sec-WebSocket-Version", "13")
+		}
+		http.Error(w, http.StatusText(code)+": "+msg, code)
+		return nil, &HandshakeError{msg}
+	}
+	switch {
+	case r.Method != "GET":
+		return fail(http.StatusMethodNotAllowed, "handshake request method is not GET")
+	case !hasToken(r.Header["Connection"], "upgrade"):
+		return fail(http.StatusBadRequest, "Connection header does not contain \"upgrade\"")
+	case !hasToken(r.Header["Upgrade"], "websocket"):
+		return fail(http.StatusBadRequest, "Upgrade header does not contain \"websocket\"")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		return fail(http.StatusUpgradeRequired, "unsupported Sec-WebSocket-Version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if b, err := base64.StdEncoding.DecodeString(key); err != nil || len(b) != 16 {
+		return fail(http.StatusBadRequest, "invalid Sec-WebSocket-Key")
+	}
+	checkOrigin := opts.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(r) {
+		return fail(http.StatusForbidden, "request origin not allowed")
+	}
+
+	var subprotocol string
+	offered := tokens(r.Header["Sec-Websocket-Protocol"])
+Select:
+	for _, p := range opts.Subprotocols {
+		for _, q := range offered {
+			if p == q {
+				subprotocol = p
+				break Select
+			}
+		}
+	}
+	compress := false
+	if opts.EnableCompression {
+		for _, ext := range parseExtensions(r.Header["Sec-Websocket-Extensions"]) {
+			if acceptDeflateOffer(ext) {
+				compress = true
+				break
+			}
+		}
+	}
+
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return fail(http.StatusInternalServerError, "connection does not support hijacking")
+	}
+	if brw.Reader.Buffered() > 0 {
+		conn.Close()
+		return nil, &HandshakeError{"client sent data before the handshake completed"}
+	}
+
+	h := make(http.Header)
+	h.Set("Upgrade", "websocket")
+	h.Set("Connection", "Upgrade")
+	h.Set("Sec-WebSocket-Accept", acceptKey(key))
+	if subprotocol != "" {
+		h.Set("Sec-WebSocket-Protocol", subprotocol)
+	}
+	if compress {
+		h.Set("Sec-WebSocket-Extensions", deflateExtension+"; server_no_context_takeover")
+	}
+	bw := brw.Writer
+	bw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	h.Write(bw)
+	bw.WriteString("\r\n")
+	if err := bw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return newConn(conn, brw.Reader, false, subprotocol, compress), nil
+}
+
+// DialOptions are the options for Dial. A nil *DialOptions is
+// equivalent to a zero DialOptions.
+type DialOptions struct {
+	// HTTPClient is the client used to send the handshake request.
+	// If nil, http.DefaultClient is used. Its Transport must
+	// return writable bodies for 101 Switching Protocols responses,
+	// as http.Transport does, and it must not have a Timeout.
+	//
+	// To connect to an httptest.Server using TLS, use the
+	// server's Client method.
+	HTTPClient *http.Client
+
+	// Header holds additional header fields for the handshake
+	// request, such as Origin or Cookie.
+	Header http.Header
+
+	// Subprotocols lists the subprotocols offered to the server.
+	Subprotocols []string
+
+	// EnableCompression offers the permessage-deflate extension to
+	// the server.
+	EnableCompression bool
+}
+
+// Dial opens a WebSocket connection to the server at urlStr, which has
+// the scheme ws, wss, http or https. ctx only applies to the opening
+// handshake.
+//
+// Dial returns the server's handshake response. Its body must not be
+// used. If the handshake fails, Dial returns a *HandshakeError, and
+// the response if one was received, with its body unread and closed.
+func Dial(ctx context.Context, urlStr string, opts *DialOptions) (*Conn, *http.Response, error) {
+	if opts == nil {
+		opts = &DialOptions{}
+	}
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, nil, fmt.Errorf("websocket: unsupported URL scheme %q", u.Scheme)
+	}
+	var k [16]byte
+	if _, err := io.ReadFull(rand.Reader, k[:]); err != nil {
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(k[:])
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	for k, vv := range opts.Header {
+		req.Header[k] = append([]string(nil), vv...)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	if len(opts.Subprotocols) > 0 {
+		req.Header.Set("Sec-WebSocket-Protocol", strings.Join(opts.Subprotocols, ", "))
+	}
+	if opts.EnableCompression {
+		req.Header.Set("Sec-WebSocket-Extensions", deflateExtension+"; client_no_context_takeover")
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	fail := func(msg string) (*Conn, *http.Response, error) {
+		resp.Body.Close()
+		return nil, resp, &HandshakeError{msg}
+	}
+	switch {
+	case resp.StatusCode != http.StatusSwitchingProtocols:
+		return fail("unexpected handshake response status " + resp.Status)
+	case !hasToken(resp.Header["Connection"], "upgrade"):
+		return fail("Connection header of handshake response does not contain \"upgrade\"")
+	case !hasToken(resp.Header["Upgrade"], "websocket"):
+		return fail("Upgrade header of handshake response does not contain \"websocket\"")
+	case resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key):
+		return fail("invalid Sec-WebSocket-Accept in handshake response")
+	}
+	subprotocol := resp.Header.Get("Sec-WebSocket-Protocol")
+	if subprotocol != "" {
+		found := false
+		for _, p := range opts.Subprotocols {
+			found = found || p == subprotocol
+		}
+		if !found {
+			return fail("server selected a subprotocol that was not offered")
+		}
+	}
+	compress := false
+	for _, ext := range parseExtensions(resp.Header["Sec-Websocket-Extensions"]) {
+		if ext.name != deflateExtension || !opts.EnableCompression || compress || !checkDeflateResponse(ext) {
+			return fail("server selected an unsupported extension")
+		}
+		compress = true
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		return fail(fmt.Sprintf("response body of type %T is not writable", resp.Body))
+	}
+	return newConn(rwc, nil, true, subprotocol, compress), resp, nil
+}
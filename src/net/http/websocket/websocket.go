@@ -0,0 +1,755 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package websocket implements the WebSocket protocol, as specified in
+// RFC 6455, for clients and servers.
+//
+// A server upgrades an HTTP request to a WebSocket connection with
+// Accept, and a client opens one with Dial:
+//
+//	func echo(w http.ResponseWriter, r *http.Request) {
+//		c, err := websocket.Accept(w, r, nil)
+//		if err != nil {
+//			return
+//		}
+//		defer c.Close(websocket.StatusInternalError, "")
+//		for {
+//			typ, msg, err := c.Read(r.Context())
+//			if err != nil {
+//				return
+//			}
+//			if err := c.Write(r.Context(), typ, msg); err != nil {
+//				return
+//			}
+//		}
+//	}
+//
+// The permessage-deflate extension of RFC 7692 is supported when
+// enabled with AcceptOptions.EnableCompression or
+// DialOptions.EnableCompression.
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// A MessageType is the type of a WebSocket data message.
+type MessageType int
+
+const (
+	// MessageText is a message holding UTF-8 encoded text.
+	MessageText MessageType = MessageType(opText)
+
+	// MessageBinary is a message holding binary data.
+	MessageBinary MessageType = MessageType(opBinary)
+)
+
+func (t MessageType) String() string {
+	switch t {
+	case MessageText:
+		return "MessageText"
+	case MessageBinary:
+		return "MessageBinary"
+	}
+	return "MessageType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// A StatusCode is the status code of a close frame, as defined in
+// RFC 6455 section 7.4.
+type StatusCode int
+
+// Status codes registered with IANA.
+const (
+	StatusNormalClosure           StatusCode = 1000
+	StatusGoingAway               StatusCode = 1001
+	StatusProtocolError           StatusCode = 1002
+	StatusUnsupportedData         StatusCode = 1003
+	StatusNoStatusReceived        StatusCode = 1005 // never sent in a close frame
+	StatusAbnormalClosure         StatusCode = 1006 // never sent in a close frame
+	StatusInvalidFramePayloadData StatusCode = 1007
+	StatusPolicyViolation         StatusCode = 1008
+	StatusMessageTooBig           StatusCode = 1009
+	StatusMandatoryExtension      StatusCode = 1010
+	StatusInternalError           StatusCode = 1011
+)
+
+// validToSend reports whether code may be sent in a close frame.
+func (code StatusCode) validToSend() bool {
+	switch {
+	case code >= 1000 && code <= 1003, code >= 1007 && code <= 1011:
+		return true
+	case code >= 3000 && code <= 4999:
+		// Reserved for libraries, frameworks and applications.
+		return true
+	}
+	return false
+}
+
+// A CloseError is returned by the methods of a Conn once the peer has
+// closed the connection with a close frame.
+type CloseError struct {
+	// Code is the status code sent by the peer, or
+	// StatusNoStatusReceived if it sent none.
+	Code StatusCode
+
+	// Reason is the reason sent by the peer, if any.
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: connection closed with status %d", int(e.Code))
+	}
+	return fmt.Sprintf("websocket: connection closed with status %d: %s", int(e.Code), e.Reason)
+}
+
+var (
+	errClosed        = errors.New("websocket: use of closed connection")
+	errCloseSent     = errors.New("websocket: close frame already sent")
+	errWriterClosed  = errors.New("websocket: write to closed message writer")
+	errInvalidType   = errors.New("websocket: invalid message type")
+	errInvalidStatus = errors.New("websocket: invalid close status code")
+)
+
+// DefaultReadLimit is the default maximum size of a message read by a
+// Conn. See Conn.SetReadLimit.
+const DefaultReadLimit = 1 << 20
+
+// closeTimeout is how long Close waits for the peer's close frame.
+const closeTimeout = 5 * time.Second
+
+// A Conn is a WebSocket connection.
+//
+// A Conn supports one concurrent reader and any number of concurrent
+// writers: messages from concurrent calls to Write are not interleaved.
+// Ping and Close may be called concurrently with the other methods.
+//
+// The methods of a Conn take a context. If the context is done before
+// a Read, Write or message Writer completes, the connection is closed,
+// as the protocol cannot resume after a partial frame.
+type Conn struct {
+	rwc         io.ReadWriteCloser
+	br          *bufio.Reader
+	bw          *bufio.Writer
+	client      bool // whether this is the client side, which masks frames
+	subprotocol string
+	compress    bool // whether permessage-deflate was negotiated
+
+	readLimit int64 // accessed atomically
+
+	readMu  chan struct{} // held by the current reader
+	msgMu   chan struct{} // held by the current writer of a data message
+	frameMu chan struct{} // held while writing a frame
+
+	decomp decompressor // used by the reader
+
+	closed    chan struct{} // closed by closeWithErr
+	closeOnce sync.Once
+	err       error // why the connection was closed; valid once closed is
+
+	closeSent     bool          // guarded by frameMu
+	closeReceived chan struct{} // closed when the peer's close frame is read
+
+	pingMu sync.Mutex
+	pings  map[string]chan struct{}
+}
+
+func newConn(rwc io.ReadWriteCloser, br *bufio.Reader, client bool, subprotocol string, compress bool) *Conn {
+	if br == nil {
+		br = bufio.NewReader(rwc)
+	}
+	return &Conn{
+		rwc:           rwc,
+		br:            br,
+		bw:            bufio.NewWriter(rwc),
+		client:        client,
+		subprotocol:   subprotocol,
+		compress:      compress,
+		readLimit:     DefaultReadLimit,
+		readMu:        make(chan struct{}, 1),
+		msgMu:         make(chan struct{}, 1),
+		frameMu:       make(chan struct{}, 1),
+		closed:        make(chan struct{}),
+		closeReceived: make(chan struct{}),
+	}
+}
+
+// Subprotocol returns the subprotocol negotiated during the opening
+// handshake, or "" if none was.
+func (c *Conn) Subprotocol() string {
+	return c.subprotocol
+}
+
+// SetReadLimit sets the maximum size in bytes of a message read from
+// the peer, after decompression. If a message exceeds the limit, the
+// connection is closed with StatusMessageTooBig. The default is
+// DefaultReadLimit.
+func (c *Conn) SetReadLimit(n int64) {
+	atomic.StoreInt64(&c.readLimit, n)
+}
+
+// lock acquires mu, unless ctx is done or the connection is closed
+// first.
+func (c *Conn) lock(ctx context.Context, mu chan struct{}) error {
+	select {
+	case mu <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closed:
+		return c.err
+	}
+}
+
+func unlock(mu chan struct{}) { <-mu }
+
+// watch closes the connection if ctx is done before the returned
+// function is called.
+func (c *Conn) watch(ctx context.Context) (stop func()) {
+	done := ctx.Done()
+	if done == nil {
+		return func() {}
+	}
+	stopc := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+			c.closeWithErr(ctx.Err())
+		case <-stopc:
+		}
+	}()
+	return func() { close(stopc) }
+}
+
+// closeWithErr closes the underlying connection, recording err as the
+// reason if it is the first call.
+func (c *Conn) closeWithErr(err error) {
+	c.closeOnce.Do(func() {
+		c.err = err
+		close(c.closed)
+		c.rwc.Close()
+	})
+}
+
+// opErr returns the error to report for a failed operation: the
+// context's error if it is done, or the reason the connection was
+// closed if it is, or err.
+func (c *Conn) opErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	select {
+	case <-c.closed:
+		if c.err != nil {
+			return c.err
+		}
+	default:
+	}
+	return err
+}
+
+// Read reads the next data message from the connection. Ping frames are
+// answered and close frames are handled while waiting for it.
+//
+// After the peer closes the connection, Read returns a *CloseError.
+func (c *Conn) Read(ctx context.Context) (MessageType, []byte, error) {
+	if err := c.lock(ctx, c.readMu); err != nil {
+		return 0, nil, err
+	}
+	defer unlock(c.readMu)
+	stop := c.watch(ctx)
+	defer stop()
+	typ, p, err := c.readMessage()
+	if err != nil {
+		return 0, nil, c.opErr(ctx, err)
+	}
+	return typ, p, nil
+}
+
+// A protocolError is a violation of the protocol by the peer, which
+// closes the connection with code.
+type protocolError struct {
+	code StatusCode
+	msg  string
+}
+
+func (e *protocolError) Error() string { return "websocket: " + e.msg }
+
+// fail sends a close frame reporting err to the peer, if err is a
+// protocolError, and closes the connection.
+func (c *Conn) fail(err error) error {
+	if pe, ok := err.(*protocolError); ok {
+		c.writeClose(pe.code, "")
+	}
+	c.closeWithErr(err)
+	return err
+}
+
+// readMessage reads the frames of the next data message.
+func (c *Conn) readMessage() (MessageType, []byte, error) {
+	var (
+		typ        MessageType
+		msg        []byte
+		started    bool
+		compressed bool
+	)
+	limit := atomic.LoadInt64(&c.readLimit)
+	for {
+		h, err := readFrameHeader(c.br)
+		if err == errFrameTooLong {
+			return 0, nil, c.fail(&protocolError{StatusProtocolError, "invalid frame length"})
+		}
+		if err != nil {
+			c.closeWithErr(err)
+			return 0, nil, err
+		}
+		if err := c.checkFrame(h, started); err != nil {
+			return 0, nil, c.fail(err)
+		}
+
+		if h.op.isControl() {
+			payload, err := c.readPayload(h, nil)
+			if err != nil {
+				c.closeWithErr(err)
+				return 0, nil, err
+			}
+			switch h.op {
+			case opPing:
+				if err := c.writeFrame(opPong, payload, true, false); err != nil && err != errCloseSent {
+					return 0, nil, err
+				}
+			case opPong:
+				c.gotPong(payload)
+			case opClose:
+				return 0, nil, c.handleClose(payload)
+			}
+			continue
+		}
+
+		if !started {
+			typ = MessageType(h.op)
+			started = true
+			compressed = h.rsv1
+		}
+		max := limit
+		if compressed {
+			// Allow for the overhead of incompressible data;
+			// the limit is checked again after decompression.
+			max += max/1024 + 64
+		}
+		if int64(len(msg))+h.length > max {
+			return 0, nil, c.fail(&protocolError{StatusMessageTooBig, "message exceeds read limit"})
+		}
+		msg, err = c.readPayload(h, msg)
+		if err != nil {
+			c.closeWithErr(err)
+			return 0, nil, err
+		}
+		if !h.fin {
+			continue
+		}
+		if compressed {
+			msg, err = c.decomp.decompress(msg, limit)
+			if err == errMessageTooBig {
+				return 0, nil, c.fail(&protocolError{StatusMessageTooBig, "message exceeds read limit"})
+			}
+			if err != nil {
+				return 0, nil, c.fail(&protocolError{StatusInvalidFramePayloadData, "invalid compressed data"})
+			}
+		}
+		if typ == MessageText && !utf8.Valid(msg) {
+			return 0, nil, c.fail(&protocolError{StatusInvalidFramePayloadData, "invalid UTF-8 in text message"})
+		}
+		return typ, msg, nil
+	}
+}
+
+// checkFrame checks that a frame with header h is valid, given whether
+// a fragmented message has started.
+func (c *Conn) checkFrame(h frameHeader, started bool) error {
+	switch {
+	case h.rsv23:
+		return &protocolError{StatusProtocolError, "reserved bits set in frame header"}
+	case h.rsv1 && (!c.compress || (h.op != opText && h.op != opBinary)):
+		return &protocolError{StatusProtocolError, "unexpected RSV1 bit in frame header"}
+	case h.masked == c.client:
+		if c.client {
+			return &protocolError{StatusProtocolError, "masked frame from server"}
+		}
+		return &protocolError{StatusProtocolError, "unmasked frame from client"}
+	}
+	switch h.op {
+	case opPing, opPong, opClose:
+		if !h.fin || h.length > maxControlPayload {
+			return &protocolError{StatusProtocolError, "invalid control frame"}
+		}
+	case opContinuation:
+		if !started {
+			return &protocolError{StatusProtocolError, "unexpected continuation frame"}
+		}
+	case opText, opBinary:
+		if started {
+			return &protocolError{StatusProtocolError, "expected continuation frame"}
+		}
+	default:
+		return &protocolError{StatusProtocolError, fmt.Sprintf("unknown opcode %d", h.op)}
+	}
+	return nil
+}
+
+// readPayload reads the payload of a frame with header h, appending it
+// to buf.
+func (c *Conn) readPayload(h frameHeader, buf []byte) ([]byte, error) {
+	start := len(buf)
+	for int64(len(buf)-start) < h.length {
+		n := h.length - int64(len(buf)-start)
+		if n > 32<<10 {
+			n = 32 << 10
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(c.br, b); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		buf = append(buf, b...)
+	}
+	if h.masked {
+		maskBytes(h.mask, 0, buf[start:])
+	}
+	return buf, nil
+}
+
+// handleClose handles a close frame from the peer with the given
+// payload, and returns the error for Read to report.
+func (c *Conn) handleClose(payload []byte) error {
+	ce := &CloseError{Code: StatusNoStatusReceived}
+	switch {
+	case len(payload) == 1:
+		return c.fail(&protocolError{StatusProtocolError, "invalid close frame payload"})
+	case len(payload) >= 2:
+		ce.Code = StatusCode(binary.BigEndian.Uint16(payload))
+		ce.Reason = string(payload[2:])
+		if !ce.Code.validToSend() {
+			return c.fail(&protocolError{StatusProtocolError, "invalid close status code"})
+		}
+		if !utf8.ValidString(ce.Reason) {
+			return c.fail(&protocolError{StatusInvalidFramePayloadData, "invalid UTF-8 in close reason"})
+		}
+	}
+	select {
+	case <-c.closeReceived:
+	default:
+		close(c.closeReceived)
+	}
+	// Echo the status code, as RFC 6455 section 5.5.1 suggests, unless
+	// Close has sent a close frame already.
+	code := ce.Code
+	if code == StatusNoStatusReceived {
+		code = 0
+	}
+	c.writeClose(code, "")
+	c.closeWithErr(ce)
+	return ce
+}
+
+// Write writes a data message of type typ with payload p to the
+// connection.
+func (c *Conn) Write(ctx context.Context, typ MessageType, p []byte) error {
+	if typ != MessageText && typ != MessageBinary {
+		return errInvalidType
+	}
+	if err := c.lock(ctx, c.msgMu); err != nil {
+		return err
+	}
+	defer unlock(c.msgMu)
+	stop := c.watch(ctx)
+	defer stop()
+	compressed := false
+	if c.compress {
+		cp, err := compressMessage(p)
+		if err != nil {
+			return err
+		}
+		p, compressed = cp, true
+	}
+	if err := c.writeFrame(opcode(typ), p, true, compressed); err != nil {
+		return c.opErr(ctx, err)
+	}
+	return nil
+}
+
+// Writer returns a writer for a data message of type typ, which is sent
+// in fragments: one frame for each call to Write, and a final frame
+// when the writer is closed. Other data messages can't be written until
+// the writer is closed.
+//
+// If ctx is done before the writer is closed, the connection is closed.
+func (c *Conn) Writer(ctx context.Context, typ MessageType) (io.WriteCloser, error) {
+	if typ != MessageText && typ != MessageBinary {
+		return nil, errInvalidType
+	}
+	if err := c.lock(ctx, c.msgMu); err != nil {
+		return nil, err
+	}
+	w := &messageWriter{
+		c:    c,
+		ctx:  ctx,
+		op:   opcode(typ),
+		stop: c.watch(ctx),
+	}
+	if c.compress {
+		fw, err := flate.NewWriter(&w.buf, flate.BestSpeed)
+		if err != nil {
+			w.stop()
+			unlock(c.msgMu)
+			return nil, err
+		}
+		w.fw = fw
+	}
+	return w, nil
+}
+
+// A messageWriter writes a fragmented data message.
+type messageWriter struct {
+	c      *Conn
+	ctx    context.Context
+	op     opcode // opcode of the next frame
+	stop   func()
+	closed bool
+
+	started bool // whether a frame has been sent
+
+	// For compressed messages, fw writes to buf, which starts with
+	// the tail of the previous flush that has not been sent yet.
+	fw  *flate.Writer
+	buf bytes.Buffer
+}
+
+func (w *messageWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errWriterClosed
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	payload := p
+	if w.fw != nil {
+		if _, err := w.fw.Write(p); err != nil {
+			return 0, err
+		}
+		if err := w.fw.Flush(); err != nil {
+			return 0, err
+		}
+		// Hold back the tail of the flush, which is dropped if
+		// it ends the message.
+		b := w.buf.Bytes()
+		payload = b[:len(b)-len(deflateTail)]
+	}
+	if err := w.writeFrame(payload, false); err != nil {
+		return 0, err
+	}
+	if w.fw != nil {
+		w.buf.Reset()
+		w.buf.Write(deflateTail)
+	}
+	return len(p), nil
+}
+
+func (w *messageWriter) writeFrame(payload []byte, fin bool) error {
+	rsv1 := w.fw != nil && !w.started
+	w.started = true
+	err := w.c.writeFrame(w.op, payload, fin, rsv1)
+	w.op = opContinuation
+	if err != nil {
+		return w.c.opErr(w.ctx, err)
+	}
+	return nil
+}
+
+// Close sends the final frame of the message.
+func (w *messageWriter) Close() error {
+	if w.closed {
+		return errWriterClosed
+	}
+	w.closed = true
+	defer unlock(w.c.msgMu)
+	defer w.stop()
+	var payload []byte
+	if w.fw != nil && !w.started {
+		// Nothing was written; send an empty compressed
+		// message, which is a single empty block.
+		w.fw.Flush()
+		b := w.buf.Bytes()
+		payload = b[:len(b)-len(deflateTail)]
+	}
+	return w.writeFrame(payload, true)
+}
+
+// writeFrame writes a single frame with the given payload. It does not
+// modify payload.
+func (c *Conn) writeFrame(op opcode, payload []byte, fin, rsv1 bool) error {
+	select {
+	case c.frameMu <- struct{}{}:
+	case <-c.closed:
+		return c.err
+	}
+	defer unlock(c.frameMu)
+	if c.closeSent {
+		return errCloseSent
+	}
+	if op == opClose {
+		c.closeSent = true
+	}
+	h := frameHeader{
+		fin:    fin,
+		rsv1:   rsv1,
+		op:     op,
+		masked: c.client,
+		length: int64(len(payload)),
+	}
+	if h.masked {
+		if _, err := io.ReadFull(rand.Reader, h.mask[:]); err != nil {
+			return err
+		}
+	}
+	var hdr [14]byte
+	if _, err := c.bw.Write(appendFrameHeader(hdr[:0], h)); err != nil {
+		return c.writeFailed(err)
+	}
+	if h.masked {
+		var chunk [4096]byte
+		pos := 0
+		for len(payload) > 0 {
+			n := copy(chunk[:], payload)
+			pos = maskBytes(h.mask, pos, chunk[:n])
+			if _, err := c.bw.Write(chunk[:n]); err != nil {
+				return c.writeFailed(err)
+			}
+			payload = payload[n:]
+		}
+	} else if _, err := c.bw.Write(payload); err != nil {
+		return c.writeFailed(err)
+	}
+	if err := c.bw.Flush(); err != nil {
+		return c.writeFailed(err)
+	}
+	return nil
+}
+
+func (c *Conn) writeFailed(err error) error {
+	c.closeWithErr(err)
+	return err
+}
+
+// writeClose writes a close frame with the given code and reason. A
+// code of zero sends a close frame without a payload.
+func (c *Conn) writeClose(code StatusCode, reason string) error {
+	var payload []byte
+	if code != 0 {
+		payload = make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+	}
+	return c.writeFrame(opClose, payload, true, false)
+}
+
+// Ping sends a ping frame to the peer and waits for the matching pong
+// frame. Pong frames are received by Read, so Ping only returns once
+// another goroutine reads from the connection.
+func (c *Conn) Ping(ctx context.Context) error {
+	var b [8]byte
+	if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+		return err
+	}
+	key := string(b[:])
+	pong := make(chan struct{})
+	c.pingMu.Lock()
+	if c.pings == nil {
+		c.pings = make(map[string]chan struct{})
+	}
+	c.pings[key] = pong
+	c.pingMu.Unlock()
+	defer func() {
+		c.pingMu.Lock()
+		delete(c.pings, key)
+		c.pingMu.Unlock()
+	}()
+
+	if err := c.writeFrame(opPing, b[:], true, false); err != nil {
+		return err
+	}
+	select {
+	case <-pong:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closed:
+		return c.err
+	}
+}
+
+// gotPong wakes up the Ping call waiting for payload, if any.
+func (c *Conn) gotPong(payload []byte) {
+	c.pingMu.Lock()
+	defer c.pingMu.Unlock()
+	if pong, ok := c.pings[string(payload)]; ok {
+		close(pong)
+		delete(c.pings, string(payload))
+	}
+}
+
+// Close performs the closing handshake: it sends a close frame with the
+// given status code and reason, waits a few seconds at most for the
+// peer's close frame, and closes the connection. If no Read is in
+// progress, Close reads and discards messages until the close frame
+// arrives. The reason must be at most 123 bytes long.
+func (c *Conn) Close(code StatusCode, reason string) error {
+	if !code.validToSend() {
+		return errInvalidStatus
+	}
+	if len(reason) > maxControlPayload-2 {
+		return errors.New("websocket: close reason too long")
+	}
+	if err := c.writeClose(code, reason); err != nil {
+		if err == errCloseSent {
+			return errClosed
+		}
+		return err
+	}
+	t := time.AfterFunc(closeTimeout, func() {
+		c.closeWithErr(errors.New("websocket: timed out waiting for close frame"))
+	})
+	defer t.Stop()
+	select {
+	case c.readMu <- struct{}{}:
+		for {
+			if _, _, err := c.readMessage(); err != nil {
+				break
+			}
+		}
+		unlock(c.readMu)
+	case <-c.closeReceived:
+	case <-c.closed:
+	}
+	c.closeWithErr(errClosed)
+	return nil
+}
+
+// CloseNow closes the connection without a closing handshake.
+func (c *Conn) CloseNow() error {
+	c.closeWithErr(errClosed)
+	return nil
+}
@@ -0,0 +1,323 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newEchoServer returns a server that echoes the messages it reads,
+// fragmenting those larger than 1000 bytes.
+func newEchoServer(t *testing.T, opts *AcceptOptions) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Accept(w, r, opts)
+		if err != nil {
+			return
+		}
+		defer c.CloseNow()
+		ctx := context.Background()
+		for {
+			typ, msg, err := c.Read(ctx)
+			if err != nil {
+				return
+			}
+			if len(msg) <= 1000 {
+				err = c.Write(ctx, typ, msg)
+			} else {
+				var mw io.WriteCloser
+				mw, err = c.Writer(ctx, typ)
+				if err == nil {
+					for i := 0; i < len(msg); i += 1000 {
+						end := i + 1000
+						if end > len(msg) {
+							end = len(msg)
+						}
+						mw.Write(msg[i:end])
+					}
+					err = mw.Close()
+				}
+			}
+			if err != nil {
+				t.Errorf("echo: %v", err)
+				return
+			}
+		}
+	}))
+}
+
+func wsURL(ts *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(ts.URL, "http")
+}
+
+func TestEcho(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		ts := newEchoServer(t, &AcceptOptions{EnableCompression: true, Subprotocols: []string{"chat", "echo"}})
+		ctx := context.Background()
+		c, resp, err := Dial(ctx, wsURL(ts), &DialOptions{
+			Subprotocols:      []string{"echo", "chat"},
+			EnableCompression: compress,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Subprotocol(); got != "chat" {
+			t.Errorf("Subprotocol = %q; want chat", got)
+		}
+		if got := resp.Header.Get("Sec-WebSocket-Extensions") != ""; got != compress {
+			t.Errorf("compress=%v: extension negotiated = %v", compress, got)
+		}
+
+		big := bytes.Repeat([]byte("websocket "), 1000)
+		msgs := []struct {
+			typ MessageType
+			p   []byte
+		}{
+			{MessageText, []byte("hello")},
+			{MessageBinary, []byte{0, 1, 2, 0xff}},
+			{MessageText, []byte{}},
+			{MessageText, big},
+			{MessageBinary, big[:70000%len(big)]},
+		}
+		for _, m := range msgs {
+			if err := c.Write(ctx, m.typ, m.p); err != nil {
+				t.Fatal(err)
+			}
+			typ, p, err := c.Read(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if typ != m.typ || !bytes.Equal(p, m.p) {
+				t.Errorf("compress=%v: echo of %v message of %d bytes = %v message of %d bytes", compress, m.typ, len(m.p), typ, len(p))
+			}
+		}
+
+		// A fragmented message written by the client.
+		w, err := c.Writer(ctx, MessageText)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, "frag")
+		io.WriteString(w, "mented")
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, p, err := c.Read(ctx); err != nil || string(p) != "fragmented" {
+			t.Errorf("compress=%v: fragmented echo = %q, %v", compress, p, err)
+		}
+
+		if err := c.Close(StatusNormalClosure, "bye"); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+		ts.Close()
+	}
+}
+
+func TestPingAndClose(t *testing.T) {
+	serverErr := make(chan error, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Accept(w, r, nil)
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		ctx := context.Background()
+		go c.Read(ctx) // receive pongs
+		if err := c.Ping(ctx); err != nil {
+			serverErr <- err
+			return
+		}
+		serverErr <- c.Close(StatusGoingAway, "shutting down")
+	}))
+	defer ts.Close()
+
+	c, _, err := Dial(context.Background(), ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = c.Read(context.Background())
+	ce, ok := err.(*CloseError)
+	if !ok || ce.Code != StatusGoingAway || ce.Reason != "shutting down" {
+		t.Errorf("Read error = %v; want CloseError with status %d", err, StatusGoingAway)
+	}
+	if err := <-serverErr; err != nil {
+		t.Errorf("server: %v", err)
+	}
+	if err := c.Write(context.Background(), MessageText, []byte("late")); err == nil {
+		t.Errorf("Write after close succeeded")
+	}
+}
+
+func TestReadLimit(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		serverErr := make(chan error, 1)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c, err := Accept(w, r, &AcceptOptions{EnableCompression: true})
+			if err != nil {
+				serverErr <- err
+				return
+			}
+			c.SetReadLimit(100)
+			_, _, err = c.Read(context.Background())
+			serverErr <- err
+		}))
+		c, _, err := Dial(context.Background(), ts.URL, &DialOptions{EnableCompression: compress})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Compresses to well under the limit.
+		c.Write(context.Background(), MessageBinary, make([]byte, 1000))
+		if err := <-serverErr; err == nil || !strings.Contains(err.Error(), "read limit") {
+			t.Errorf("compress=%v: server Read error = %v; want read limit error", compress, err)
+		}
+		_, _, err = c.Read(context.Background())
+		if ce, ok := err.(*CloseError); !ok || ce.Code != StatusMessageTooBig {
+			t.Errorf("compress=%v: client Read error = %v; want CloseError with status %d", compress, err, StatusMessageTooBig)
+		}
+		c.CloseNow()
+		ts.Close()
+	}
+}
+
+func TestReadContext(t *testing.T) {
+	ts := newEchoServer(t, nil)
+	defer ts.Close()
+	c, _, err := Dial(context.Background(), ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.Read(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Read error = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if err := c.Write(context.Background(), MessageText, []byte("x")); err == nil {
+		t.Errorf("Write after canceled Read succeeded")
+	}
+}
+
+func TestProtocolError(t *testing.T) {
+	serverErr := make(chan error, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Accept(w, r, nil)
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		_, _, err = c.Read(context.Background())
+		serverErr <- err
+	}))
+	defer ts.Close()
+	c, _, err := Dial(context.Background(), ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.CloseNow()
+	// A text message that is not valid UTF-8.
+	c.Write(context.Background(), MessageText, []byte{0xff, 0xfe})
+	if err := <-serverErr; err == nil || !strings.Contains(err.Error(), "UTF-8") {
+		t.Errorf("server Read error = %v; want invalid UTF-8 error", err)
+	}
+	_, _, err = c.Read(context.Background())
+	if ce, ok := err.(*CloseError); !ok || ce.Code != StatusInvalidFramePayloadData {
+		t.Errorf("client Read error = %v; want CloseError with status %d", err, StatusInvalidFramePayloadData)
+	}
+}
+
+func TestBadHandshake(t *testing.T) {
+	ts := newEchoServer(t, nil)
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		status int
+	}{
+		{"no upgrade", map[string]string{"Upgrade": ""}, http.StatusBadRequest},
+		{"bad version", map[string]string{"Sec-WebSocket-Version": "8"}, http.StatusUpgradeRequired},
+		{"bad key", map[string]string{"Sec-WebSocket-Key": "short"}, http.StatusBadRequest},
+		{"cross origin", map[string]string{"Origin": "http://evil.example"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", ts.URL, nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != tt.status {
+			t.Errorf("%s: status = %d; want %d", tt.name, res.StatusCode, tt.status)
+		}
+	}
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+	_, resp, err := Dial(context.Background(), plain.URL, nil)
+	if _, ok := err.(*HandshakeError); !ok || resp == nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("Dial to non-WebSocket server: resp = %v, err = %v; want HandshakeError", resp, err)
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// Example from RFC 6455 section 1.3.
+	if got, want := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("acceptKey = %q; want %q", got, want)
+	}
+}
+
+func TestFrameHeader(t *testing.T) {
+	for _, h := range []frameHeader{
+		{fin: true, op: opText, length: 5},
+		{op: opBinary, rsv1: true, length: 126},
+		{fin: true, op: opContinuation, length: 70000, masked: true, mask: [4]byte{1, 2, 3, 4}},
+		{fin: true, op: opPing},
+	} {
+		b := appendFrameHeader(nil, h)
+		got, err := readFrameHeader(bufio.NewReader(bytes.NewReader(b)))
+		if err != nil {
+			t.Errorf("readFrameHeader(%x): %v", b, err)
+			continue
+		}
+		if got != h {
+			t.Errorf("readFrameHeader(%x) = %+v; want %+v", b, got, h)
+		}
+	}
+	_, err := readFrameHeader(bufio.NewReader(bytes.NewReader([]byte{0x82, 127, 0x80, 0, 0, 0, 0, 0, 0, 0})))
+	if err != errFrameTooLong {
+		t.Errorf("readFrameHeader with invalid length: err = %v; want %v", err, errFrameTooLong)
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	exts := parseExtensions([]string{`permessage-deflate; client_max_window_bits, permessage-deflate; server_max_window_bits="10"`, "x-foo"})
+	if len(exts) != 3 {
+		t.Fatalf("parseExtensions returned %d extensions; want 3", len(exts))
+	}
+	if !acceptDeflateOffer(exts[0]) {
+		t.Errorf("offer %v not accepted", exts[0])
+	}
+	if acceptDeflateOffer(exts[1]) {
+		t.Errorf("offer %v accepted", exts[1])
+	}
+	if acceptDeflateOffer(exts[2]) {
+		t.Errorf("offer %v accepted", exts[2])
+	}
+}
@@ -27,6 +27,9 @@ import (
 
 var fset = token.NewFileSet()
 
+// funcs is the set of names of the funcs being rewritten.
+var funcs = make(map[string]bool)
+
 func main() {
 	af, err := parser.ParseFile(fset, "sort.go", nil, 0)
 	if err != nil {
@@ -56,6 +59,7 @@ func main() {
 			continue
 		}
 		arg0Type.Name = "lessSwap"
+		funcs[fd.Name.Name] = true
 
 		newDecl = append(newDecl, fd)
 	}
@@ -115,11 +119,8 @@ func rewriteCall(ce *ast.CallExpr) {
 		// e.g. skip SelectorExpr (data.Less(..) calls)
 		return
 	}
-	// skip casts
-	if ident.Name == "int" || ident.Name == "uint" {
-		return
-	}
-	if len(ce.Args) < 1 {
+	// skip conversions and calls of funcs not taking data
+	if !funcs[ident.Name] {
 		return
 	}
 	ident.Name += "_func"
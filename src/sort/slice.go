@@ -14,7 +14,7 @@ func Slice(slice interface{}, less func(i, j int) bool) {
 	rv := reflectValueOf(slice)
 	swap := reflectSwapper(slice)
 	length := rv.Len()
-	quickSort_func(lessSwap{less, swap}, 0, length, maxDepth(length))
+	pdqsort_func(lessSwap{less, swap}, 0, length, bitLen(length))
 }
 
 // SliceStable sorts the provided slice given the provided less
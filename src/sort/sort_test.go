@@ -263,6 +263,45 @@ func BenchmarkSortInt64K(b *testing.B) {
 	}
 }
 
+func BenchmarkSortInt64K_Sorted(b *testing.B) {
+	b.StopTimer()
+	for i := 0; i < b.N; i++ {
+		data := make([]int, 1<<16)
+		for i := 0; i < len(data); i++ {
+			data[i] = i
+		}
+		b.StartTimer()
+		Ints(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSortInt64K_Reversed(b *testing.B) {
+	b.StopTimer()
+	for i := 0; i < b.N; i++ {
+		data := make([]int, 1<<16)
+		for i := 0; i < len(data); i++ {
+			data[i] = len(data) - i
+		}
+		b.StartTimer()
+		Ints(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSortInt64K_Mod8(b *testing.B) {
+	b.StopTimer()
+	for i := 0; i < b.N; i++ {
+		data := make([]int, 1<<16)
+		for i := 0; i < len(data); i++ {
+			data[i] = i % 8
+		}
+		b.StartTimer()
+		Ints(data)
+		b.StopTimer()
+	}
+}
+
 func BenchmarkSortInt64K_Slice(b *testing.B) {
 	b.StopTimer()
 	for i := 0; i < b.N; i++ {
@@ -632,6 +671,38 @@ func countOps(t *testing.T, algo func(Interface), name string) {
 	}
 }
 
+// TestSortPatterns checks that Sort sorts sorted, reverse-sorted and
+// constant input with a linear number of comparisons.
+func TestSortPatterns(t *testing.T) {
+	const n = 10000
+	patterns := []struct {
+		name string
+		f    func(i int) int
+	}{
+		{"sorted", func(i int) int { return i }},
+		{"reversed", func(i int) int { return n - i }},
+		{"equal", func(i int) int { return 42 }},
+	}
+	for _, p := range patterns {
+		td := testingData{
+			desc:    p.name,
+			t:       t,
+			data:    make([]int, n),
+			maxswap: n,
+		}
+		for i := range td.data {
+			td.data[i] = p.f(i)
+		}
+		Sort(&td)
+		if !IsSorted(&td) {
+			t.Errorf("%s: not sorted", p.name)
+		}
+		if td.ncmp > 3*n {
+			t.Errorf("%s: used %d comparisons sorting %d ints, want at most %d", p.name, td.ncmp, n, 3*n)
+		}
+	}
+}
+
 func TestCountStableOps(t *testing.T) { countOps(t, Stable, "Stable") }
 func TestCountSortOps(t *testing.T)   { countOps(t, Sort, "Sort  ") }
 
@@ -231,7 +231,7 @@ func (b *B) run1() bool {
 	}()
 	<-b.signal
 	if b.failed {
-		fmt.Fprintf(b.w, "--- FAIL: %s\n%s", b.name, b.output)
+		b.report("FAIL", b.name)
 		return false
 	}
 	// Only print the output if we know we are not going to proceed.
@@ -243,13 +243,27 @@ func (b *B) run1() bool {
 		}
 		if b.chatty && (len(b.output) > 0 || b.finished) {
 			b.trimOutput()
-			fmt.Fprintf(b.w, "--- %s: %s\n%s", tag, b.name, b.output)
+			b.report(tag, b.name)
 		}
 		return false
 	}
 	return true
 }
 
+// report writes the output of b to b.w after the line "--- tag: name",
+// or as events for test2json if b.json is set.
+func (b *B) report(tag, name string) {
+	if !b.json {
+		fmt.Fprintf(b.w, "--- %s: %s\n%s", tag, name, b.output)
+		return
+	}
+	e := event{action: strings.ToLower(tag), test: name, elapsed: b.duration}
+	if tag == "SKIP" {
+		e.reason = b.reason
+	}
+	writeReport(b.w, e, fmt.Sprintf("--- %s: %s\n", tag, name), "", string(b.output))
+}
+
 var labelsOnce sync.Once
 
 // run executes the benchmark in a separate goroutine, including all of its
@@ -467,6 +481,34 @@ func prettyPrint(w io.Writer, x float64, unit string) {
 	fmt.Fprintf(w, format, x, unit)
 }
 
+// metrics returns the metrics of the benchmark result line printed by
+// String, and by MemString if mem is set, keyed by unit.
+func (r BenchmarkResult) metrics(mem bool) map[string]float64 {
+	m := make(map[string]float64)
+	for unit, x := range r.Extra {
+		m[unit] = x
+	}
+	if _, ok := m["ns/op"]; !ok {
+		m["ns/op"] = float64(r.T.Nanoseconds()) / float64(r.N)
+	}
+	if m["ns/op"] == 0 {
+		delete(m, "ns/op")
+	}
+	if mbs := r.mbPerSec(); mbs != 0 {
+		m["MB/s"] = mbs
+	} else {
+		delete(m, "MB/s")
+	}
+	if mem {
+		m["B/op"] = float64(r.AllocedBytesPerOp())
+		m["allocs/op"] = float64(r.AllocsPerOp())
+	} else {
+		delete(m, "B/op")
+		delete(m, "allocs/op")
+	}
+	return m
+}
+
 // MemString returns r.AllocedBytesPerOp and r.AllocsPerOp in the same format as 'go test'.
 func (r BenchmarkResult) MemString() string {
 	return fmt.Sprintf("%8d B/op\t%8d allocs/op",
@@ -524,7 +566,8 @@ func runBenchmarks(importPath string, matchString func(pat, str string) (bool, e
 		common: common{
 			name:   "Main",
 			w:      os.Stdout,
-			chatty: *chatty,
+			chatty: chatty.on,
+			json:   chatty.json,
 		},
 		importPath: importPath,
 		benchFunc: func(b *B) {
@@ -545,7 +588,9 @@ func (ctx *benchContext) processBench(b *B) {
 		for j := uint(0); j < *count; j++ {
 			runtime.GOMAXPROCS(procs)
 			benchName := benchmarkName(b.name, procs)
-			fmt.Fprintf(b.w, "%-*s\t", ctx.maxLen, benchName)
+			if !b.json {
+				fmt.Fprintf(b.w, "%-*s\t", ctx.maxLen, benchName)
+			}
 			// Recompute the running time for all but the first iteration.
 			if i > 0 || j > 0 {
 				b = &B{
@@ -554,6 +599,7 @@ func (ctx *benchContext) processBench(b *B) {
 						name:   b.name,
 						w:      b.w,
 						chatty: b.chatty,
+						json:   b.json,
 					},
 					benchFunc: b.benchFunc,
 					benchTime: b.benchTime,
@@ -565,19 +611,29 @@ func (ctx *benchContext) processBench(b *B) {
 				// The output could be very long here, but probably isn't.
 				// We print it all, regardless, because we don't want to trim the reason
 				// the benchmark failed.
-				fmt.Fprintf(b.w, "--- FAIL: %s\n%s", benchName, b.output)
+				b.report("FAIL", benchName)
 				continue
 			}
+			mem := *benchmarkMemory || b.showAllocResult
 			results := r.String()
-			if *benchmarkMemory || b.showAllocResult {
+			if mem {
 				results += "\t" + r.MemString()
 			}
-			fmt.Fprintln(b.w, results)
+			if b.json {
+				writeEvent(b.w, event{
+					action:  "output",
+					test:    benchName,
+					output:  fmt.Sprintf("%-*s\t%s\n", ctx.maxLen, benchName, results),
+					metrics: r.metrics(mem),
+				})
+			} else {
+				fmt.Fprintln(b.w, results)
+			}
 			// Unlike with tests, we ignore the -chatty flag and always print output for
 			// benchmarks since the output generation time will skew the results.
 			if len(b.output) > 0 {
 				b.trimOutput()
-				fmt.Fprintf(b.w, "--- BENCH: %s\n%s", benchName, b.output)
+				b.report("BENCH", benchName)
 			}
 			if p := runtime.GOMAXPROCS(-1); p != procs {
 				fmt.Fprintf(os.Stderr, "testing: %s left GOMAXPROCS set to %d\n", benchName, p)
@@ -616,6 +672,7 @@ func (b *B) Run(name string, f func(b *B)) bool {
 			creator: pc[:n],
 			w:       b.w,
 			chatty:  b.chatty,
+			json:    b.json,
 		},
 		importPath: b.importPath,
 		benchFunc:  f,
@@ -0,0 +1,226 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testing
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// When the -test.v flag is set to "test2json", as 'go test -json' does,
+// the test binary reports the progress of tests as a sequence of events
+// in the form read by cmd/test2json, instead of the text that it would
+// otherwise print and that test2json would have to parse. Each event is
+// written on a line of its own, framed by a leading frameMarker byte and
+// encoded as a JSON object, as in
+//
+//	^V{"Action":"run","Test":"TestFoo"}
+//
+// The events name the test they belong to, so that the output of tests
+// and parallel subtests is attributed to them exactly, and carry the
+// elapsed time of each test, the metrics of each benchmark result and
+// the reason given for a skip.
+
+// frameMarker is the byte that starts each framed event.
+const frameMarker = 0x16 // ^V
+
+// maxEventOutput is the maximum number of bytes of output in one event,
+// which keeps events small enough for test2json to read them whole even
+// when the output is escaped in JSON.
+const maxEventOutput = 512
+
+// chattyFlag is the value of the -test.v flag: a boolean,
+// or "test2json" to report verbose output as framed events.
+type chattyFlag struct {
+	on   bool // -test.v or -test.v=test2json
+	json bool // -test.v=test2json
+}
+
+func (*chattyFlag) IsBoolFlag() bool { return true }
+
+func (f *chattyFlag) Set(arg string) error {
+	if arg == "test2json" {
+		f.on, f.json = true, true
+		return nil
+	}
+	on, err := strconv.ParseBool(arg)
+	if err != nil {
+		return fmt.Errorf("invalid flag -test.v=%s", arg)
+	}
+	f.on, f.json = on, false
+	return nil
+}
+
+func (f *chattyFlag) String() string {
+	if f.json {
+		return "test2json"
+	}
+	return strconv.FormatBool(f.on)
+}
+
+func (f *chattyFlag) Get() interface{} {
+	if f.json {
+		return "test2json"
+	}
+	return f.on
+}
+
+// An event is a test event as reported to test2json.
+type event struct {
+	action  string
+	test    string
+	elapsed time.Duration // for pass, fail and skip
+	output  string
+	metrics map[string]float64 // for benchmark results
+	reason  string             // for skip
+}
+
+// writeEvent writes e to w in framed form. An output event whose output
+// is too long is split into several events.
+func writeEvent(w io.Writer, e event) {
+	if e.action == "output" && len(e.output) > maxEventOutput {
+		out := e.output
+		for len(out) > maxEventOutput {
+			n := maxEventOutput
+			for n > 0 && !utf8.RuneStart(out[n]) {
+				n--
+			}
+			if n == 0 {
+				n = maxEventOutput
+			}
+			part := e
+			part.output, part.metrics = out[:n], nil
+			writeEvent(w, part)
+			out = out[n:]
+		}
+		e.output = out
+	}
+
+	b := make([]byte, 0, 64+len(e.test)+len(e.output))
+	b = append(b, frameMarker)
+	b = append(b, `{"Action":`...)
+	b = appendJSONString(b, e.action)
+	if e.test != "" {
+		b = append(b, `,"Test":`...)
+		b = appendJSONString(b, e.test)
+	}
+	if e.action == "pass" || e.action == "fail" || e.action == "skip" {
+		b = append(b, `,"Elapsed":`...)
+		b = strconv.AppendFloat(b, e.elapsed.Seconds(), 'f', -1, 64)
+	}
+	if e.output != "" {
+		b = append(b, `,"Output":`...)
+		b = appendJSONString(b, e.output)
+	}
+	if len(e.metrics) > 0 {
+		units := make([]string, 0, len(e.metrics))
+		for unit, x := range e.metrics {
+			if !math.IsNaN(x) && !math.IsInf(x, 0) {
+				units = append(units, unit)
+			}
+		}
+		sort.Strings(units)
+		b = append(b, `,"Metrics":{`...)
+		for i, unit := range units {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, unit)
+			b = append(b, ':')
+			b = strconv.AppendFloat(b, e.metrics[unit], 'g', -1, 64)
+		}
+		b = append(b, '}')
+	}
+	if e.reason != "" {
+		b = append(b, `,"Reason":`...)
+		b = appendJSONString(b, e.reason)
+	}
+	b = append(b, "}\n"...)
+	w.Write(b)
+}
+
+// writeOutput writes out as output events of the named test, one for each
+// line, with each line indented by indent.
+func writeOutput(w io.Writer, test, indent, out string) {
+	for out != "" {
+		line := out
+		if i := strings.IndexByte(out, '\n'); i >= 0 {
+			line = out[:i+1]
+		}
+		out = out[len(line):]
+		writeEvent(w, event{action: "output", test: test, output: indent + line})
+	}
+}
+
+// writeUpdate writes the line marking that the named test started,
+// paused or continued running: "=== RUN   name" for the "run" action.
+// If json is set, it writes it as an output event, after an event for
+// the action itself or, for a pause, before it.
+func writeUpdate(w io.Writer, json bool, action, test string) {
+	line := fmt.Sprintf("=== %-5s %s\n", strings.ToUpper(action), test)
+	if !json {
+		io.WriteString(w, line)
+		return
+	}
+	if action == "pause" {
+		writeOutput(w, test, "", line)
+	}
+	writeEvent(w, event{action: action, test: test})
+	if action != "pause" {
+		writeOutput(w, test, "", line)
+	}
+}
+
+// writeReport writes the result of the named test as events: the report
+// line "--- PASS: name (0.00s)" or similar, given as header, and the
+// output of the test, both indented by indent, then the result itself.
+func writeReport(w io.Writer, e event, header, indent, out string) {
+	writeOutput(w, e.test, indent, header)
+	writeOutput(w, e.test, indent, out)
+	e.output = ""
+	writeEvent(w, e)
+}
+
+// appendJSONString appends s to b as a JSON string. Invalid UTF-8 is
+// replaced by the Unicode replacement character, as encoding/json does.
+func appendJSONString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c < 0x20:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			default:
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, `\ufffd`...)
+		} else {
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(b, '"')
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testing
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+func TestWriteEvent(t *T) {
+	testCases := []struct {
+		desc string
+		e    event
+		want string
+	}{{
+		desc: "run",
+		e:    event{action: "run", test: "TestX"},
+		want: `{"Action":"run","Test":"TestX"}`,
+	}, {
+		desc: "elapsed",
+		e:    event{action: "pass", test: "TestX", elapsed: 1500 * time.Millisecond},
+		want: `{"Action":"pass","Test":"TestX","Elapsed":1.5}`,
+	}, {
+		desc: "escapes",
+		e:    event{action: "output", test: "TestX", output: "\"a\\b\"\t\x01\xff\u00e9\n"},
+		want: `{"Action":"output","Test":"TestX","Output":"\"a\\b\"\t\u0001\ufffd` + "\u00e9" + `\n"}`,
+	}, {
+		desc: "metrics",
+		e: event{action: "output", test: "BenchmarkX", output: "BenchmarkX\n", metrics: map[string]float64{
+			"ns/op":      12.5,
+			"widgets/op": 3,
+			"bad/op":     math.NaN(),
+		}},
+		want: `{"Action":"output","Test":"BenchmarkX","Output":"BenchmarkX\n","Metrics":{"ns/op":12.5,"widgets/op":3}}`,
+	}, {
+		desc: "skip",
+		e:    event{action: "skip", test: "TestX", reason: "not today"},
+		want: `{"Action":"skip","Test":"TestX","Elapsed":0,"Reason":"not today"}`,
+	}, {
+		desc: "split",
+		e:    event{action: "output", test: "TestX", output: strings.Repeat("x", maxEventOutput) + "y\n"},
+		want: `{"Action":"output","Test":"TestX","Output":"` + strings.Repeat("x", maxEventOutput) + `"}` + "\n\x16" +
+			`{"Action":"output","Test":"TestX","Output":"y\n"}`,
+	}}
+	for _, tc := range testCases {
+		var b strings.Builder
+		writeEvent(&b, tc.e)
+		if got, want := b.String(), "\x16"+tc.want+"\n"; got != want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tc.desc, got, want)
+		}
+	}
+}
@@ -79,9 +79,18 @@ func (eg *InternalExample) processRunResult(stdout string, timeSpent time.Durati
 		}
 	}
 	if fail != "" || recovered != nil {
-		fmt.Printf("--- FAIL: %s (%s)\n%s", eg.Name, dstr, fail)
 		passed = false
-	} else if *chatty {
+	}
+	if chatty.json {
+		e := event{action: "pass", test: eg.Name, elapsed: timeSpent}
+		if !passed {
+			e.action = "fail"
+		}
+		header := fmt.Sprintf("--- %s: %s (%s)\n", strings.ToUpper(e.action), eg.Name, dstr)
+		writeReport(os.Stdout, e, header, "", fail)
+	} else if !passed {
+		fmt.Printf("--- FAIL: %s (%s)\n%s", eg.Name, dstr, fail)
+	} else if chatty.on {
 		fmt.Printf("--- PASS: %s (%s)\n", eg.Name, dstr)
 	}
 	if recovered != nil {
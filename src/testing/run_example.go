@@ -19,8 +19,8 @@ import (
 )
 
 func runExample(eg InternalExample) (ok bool) {
-	if *chatty {
-		fmt.Printf("=== RUN   %s\n", eg.Name)
+	if chatty.on {
+		writeUpdate(os.Stdout, chatty.json, "run", eg.Name)
 	}
 
 	// Capture stdout.
@@ -123,6 +123,7 @@ func TestTRun(t *T) {
 		ok     bool
 		maxPar int
 		chatty bool
+		json   bool
 		output string
 		f      func(*T)
 	}{{
@@ -202,6 +203,28 @@ func TestTRun(t *T) {
 				t.Run("", func(t *T) {})
 			})
 		},
+	}, {
+		desc:   "json",
+		ok:     true,
+		chatty: true,
+		json:   true,
+		output: `
+^V{"Action":"run","Test":"json"}
+^V{"Action":"output","Test":"json","Output":"=== RUN   json\n"}
+^V{"Action":"run","Test":"json/sub"}
+^V{"Action":"output","Test":"json/sub","Output":"=== RUN   json/sub\n"}
+^V{"Action":"output","Test":"json/sub","Output":"    --- SKIP: json/sub (N.NNs)\n"}
+^V{"Action":"output","Test":"json/sub","Output":"        sub_test.go:NNN: message\n"}
+^V{"Action":"output","Test":"json/sub","Output":"        sub_test.go:NNN: not \"today\"\n"}
+^V{"Action":"skip","Test":"json/sub","Elapsed":N.NN,"Reason":"not \"today\""}
+^V{"Action":"output","Test":"json","Output":"--- PASS: json (N.NNs)\n"}
+^V{"Action":"pass","Test":"json","Elapsed":N.NN}`,
+		f: func(t *T) {
+			t.Run("sub", func(t *T) {
+				t.Log("message")
+				t.Skip(`not "today"`)
+			})
+		},
 	}, {
 		desc: "skipping without message, not chatty",
 		ok:   true,
@@ -444,6 +467,7 @@ func TestTRun(t *T) {
 				name:   "Test",
 				w:      buf,
 				chatty: tc.chatty,
+				json:   tc.json,
 			},
 			context: ctx,
 		}
@@ -619,6 +643,8 @@ func makeRegexp(s string) string {
 	s = regexp.QuoteMeta(s)
 	s = strings.ReplaceAll(s, ":NNN:", `:\d\d\d:`)
 	s = strings.ReplaceAll(s, "N\\.NNs", `\d*\.\d*s`)
+	s = strings.ReplaceAll(s, "N\\.NN", `[\d.]+`)
+	s = strings.ReplaceAll(s, "\\^V", "\x16")
 	return s
 }
 
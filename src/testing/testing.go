@@ -277,7 +277,8 @@ func Init() {
 	// the "go test" command is run.
 	outputDir = flag.String("test.outputdir", "", "write profiles to `dir`")
 	// Report as tests are run; default is silent for success.
-	chatty = flag.Bool("test.v", false, "verbose: print additional output")
+	chatty = new(chattyFlag)
+	flag.Var(chatty, "test.v", "verbose: print additional output")
 	count = flag.Uint("test.count", 1, "run tests and benchmarks `n` times")
 	coverProfile = flag.String("test.coverprofile", "", "write a coverage profile to `file`")
 	matchList = flag.String("test.list", "", "list tests, examples, and benchmarks matching `regexp` then exit")
@@ -294,6 +295,7 @@ func Init() {
 	cpuListStr = flag.String("test.cpu", "", "comma-separated `list` of cpu counts to run each test with")
 	parallel = flag.Int("test.parallel", runtime.GOMAXPROCS(0), "run at most `n` tests in parallel")
 	testlog = flag.String("test.testlogfile", "", "write test action log to `file` (for use only by cmd/go)")
+	shuffle = flag.String("test.shuffle", "off", "randomize the execution order of tests and benchmarks")
 
 	initBenchmarkFlags()
 }
@@ -303,7 +305,7 @@ var (
 	short                *bool
 	failFast             *bool
 	outputDir            *string
-	chatty               *bool
+	chatty               *chattyFlag
 	count                *uint
 	coverProfile         *string
 	matchList            *string
@@ -318,6 +320,7 @@ var (
 	traceFile            *string
 	timeout              *time.Duration
 	cpuListStr           *string
+	shuffle              *string
 	parallel             *int
 	testlog              *string
 
@@ -342,10 +345,12 @@ type common struct {
 	ran     bool                // Test or benchmark (or one of its subtests) was executed.
 	failed  bool                // Test or benchmark has failed.
 	skipped bool                // Test of benchmark has been skipped.
+	reason  string              // Message passed to Skip or Skipf.
 	done    bool                // Test is finished and all subtests have completed.
 	helpers map[string]struct{} // functions to be skipped when writing file/line info
 
 	chatty     bool   // A copy of the chatty flag.
+	json       bool   // Report as framed events for test2json; see event.go.
 	finished   bool   // Test function has completed.
 	hasSub     int32  // written atomically
 	raceErrors int    // number of races detected during test
@@ -394,7 +399,7 @@ func Verbose() bool {
 		fmt.Fprintf(os.Stderr, "testing: Verbose called before flag.Parse\n")
 		os.Exit(2)
 	}
-	return *chatty
+	return chatty.on
 }
 
 // frameSkip searches, starting after skip frames, for the first caller frame
@@ -721,13 +726,17 @@ func (c *common) Fatalf(format string, args ...interface{}) {
 
 // Skip is equivalent to Log followed by SkipNow.
 func (c *common) Skip(args ...interface{}) {
-	c.log(fmt.Sprintln(args...))
+	s := fmt.Sprintln(args...)
+	c.log(s)
+	c.reason = strings.TrimSuffix(s, "\n")
 	c.SkipNow()
 }
 
 // Skipf is equivalent to Logf followed by SkipNow.
 func (c *common) Skipf(format string, args ...interface{}) {
-	c.log(fmt.Sprintf(format, args...))
+	s := fmt.Sprintf(format, args...)
+	c.log(s)
+	c.reason = s
 	c.SkipNow()
 }
 
@@ -809,7 +818,7 @@ func (t *T) Parallel() {
 		for ; root.parent != nil; root = root.parent {
 		}
 		root.mu.Lock()
-		fmt.Fprintf(root.w, "=== PAUSE %s\n", t.name)
+		writeUpdate(root.w, t.json, "pause", t.name)
 		root.mu.Unlock()
 	}
 
@@ -823,7 +832,7 @@ func (t *T) Parallel() {
 		for ; root.parent != nil; root = root.parent {
 		}
 		root.mu.Lock()
-		fmt.Fprintf(root.w, "=== CONT  %s\n", t.name)
+		writeUpdate(root.w, t.json, "cont", t.name)
 		root.mu.Unlock()
 	}
 
@@ -941,6 +950,7 @@ func (t *T) Run(name string, f func(t *T)) bool {
 			level:   t.level + 1,
 			creator: pc[:n],
 			chatty:  t.chatty,
+			json:    t.json,
 		},
 		context: t.context,
 	}
@@ -952,7 +962,7 @@ func (t *T) Run(name string, f func(t *T)) bool {
 		for ; root.parent != nil; root = root.parent {
 		}
 		root.mu.Lock()
-		fmt.Fprintf(root.w, "=== RUN   %s\n", t.name)
+		writeUpdate(root.w, t.json, "run", t.name)
 		root.mu.Unlock()
 	}
 	// Instead of reducing the running count of this test before calling the
@@ -1111,6 +1121,26 @@ func (m *M) Run() int {
 		return 0
 	}
 
+	if *shuffle != "off" {
+		var n int64
+		var err error
+		if *shuffle == "on" {
+			n = time.Now().UnixNano()
+		} else {
+			n, err = strconv.ParseInt(*shuffle, 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, `testing: -shuffle should be "off", "on", or a valid integer:`, err)
+				return 2
+			}
+		}
+		// Print the seed so that the order can be reproduced
+		// with -shuffle=n.
+		fmt.Println("-test.shuffle", n)
+		rng := shuffler(n)
+		rng.shuffle(len(m.tests), func(i, j int) { m.tests[i], m.tests[j] = m.tests[j], m.tests[i] })
+		rng.shuffle(len(m.benchmarks), func(i, j int) { m.benchmarks[i], m.benchmarks[j] = m.benchmarks[j], m.benchmarks[i] })
+	}
+
 	parseCpuList()
 
 	m.before()
@@ -1138,6 +1168,10 @@ func (t *T) report() {
 	}
 	dstr := fmtDuration(t.duration)
 	format := "--- %s: %s (%s)\n"
+	if t.json {
+		t.reportEvents(format, dstr)
+		return
+	}
 	if t.Failed() {
 		t.flushToParent(format, "FAIL", t.name, dstr)
 	} else if t.chatty {
@@ -1149,6 +1183,52 @@ func (t *T) report() {
 	}
 }
 
+// A shuffler is the pseudo-random number generator used by -test.shuffle,
+// a SplitMix64 generator seeded by its initial value. The testing package
+// can't use math/rand, whose own tests import testing.
+type shuffler uint64
+
+func (s *shuffler) next() uint64 {
+	*s += 0x9e3779b97f4a7c15
+	z := uint64(*s)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// shuffle permutes n elements by calling swap, as rand.Shuffle does.
+func (s *shuffler) shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, int(s.next()%uint64(i+1)))
+	}
+}
+
+// reportEvents reports the result of t in the framed form read by
+// test2json. Unlike report, it writes directly to the root's io.Writer,
+// since the output of subtests has already been reported in the same way.
+func (t *T) reportEvents(format, dstr string) {
+	e := event{action: "pass", test: t.name, elapsed: t.duration}
+	switch {
+	case t.Failed():
+		e.action = "fail"
+	case t.Skipped():
+		e.action = "skip"
+		e.reason = t.reason
+	}
+	header := fmt.Sprintf(format, strings.ToUpper(e.action), t.name, dstr)
+	indent := strings.Repeat("    ", t.level-1)
+
+	root := t.parent
+	for ; root.parent != nil; root = root.parent {
+	}
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	writeReport(root.w, e, header, indent, string(t.output))
+	t.output = t.output[:0]
+}
+
 func listTests(matchString func(pat, str string) (bool, error), tests []InternalTest, benchmarks []InternalBenchmark, examples []InternalExample) {
 	if _, err := matchString(*matchList, "non-empty"); err != nil {
 		fmt.Fprintf(os.Stderr, "testing: invalid regexp in -test.list (%q): %s\n", *matchList, err)
@@ -1196,7 +1276,8 @@ func runTests(matchString func(pat, str string) (bool, error), tests []InternalT
 					signal:  make(chan bool),
 					barrier: make(chan bool),
 					w:       os.Stdout,
-					chatty:  *chatty,
+					chatty:  chatty.on,
+					json:    chatty.json,
 				},
 				context: ctx,
 			}
@@ -0,0 +1,72 @@
+// run -gcflags=-d=loopvar=1
+
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -d=loopvar makes loop variables per-iteration.
+
+package main
+
+import "fmt"
+
+func main() {
+	var fs []func() int
+	for i := 0; i < 3; i++ {
+		fs = append(fs, func() int { return i })
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		fs = append(fs, func() int { return len(s) + int(s[0]) })
+	}
+	check("closures", fs, 0, 1, 2, 1+'a', 1+'b', 1+'c')
+
+	var ps []*int
+	for i := 0; i < 10; i += 3 {
+		if i == 3 {
+			continue
+		}
+		ps = append(ps, &i)
+	}
+	for k, v := range map[int]int{7: 8} {
+		ps = append(ps, &k, &v)
+	}
+	check("pointers", deref(ps), 0, 6, 9, 7, 8)
+
+	// The post statement updates the variable of the next iteration,
+	// so changes made by the body are seen by the condition.
+	fs = nil
+	for i := 0; i < 6; i++ {
+		i++
+		fs = append(fs, func() int { return i })
+	}
+	check("post", fs, 1, 3, 5)
+
+	// Loops whose variables cannot outlive an iteration are unchanged.
+	sum := 0
+	for i, j := 0, 10; i < j; i, j = i+1, j-1 {
+		sum += j - i
+	}
+	if sum != 30 {
+		panic(fmt.Sprintf("sum = %d, want 30", sum))
+	}
+}
+
+func deref(ps []*int) []func() int {
+	var fs []func() int
+	for _, p := range ps {
+		p := p
+		fs = append(fs, func() int { return *p })
+	}
+	return fs
+}
+
+func check(name string, fs []func() int, want ...int) {
+	if len(fs) != len(want) {
+		panic(fmt.Sprintf("%s: got %d values, want %d", name, len(fs), len(want)))
+	}
+	for i, f := range fs {
+		if got := f(); got != want[i] {
+			panic(fmt.Sprintf("%s: value %d = %d, want %d", name, i, got, want[i]))
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// run -gcflags=-lang=go1.14
+
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that loop variables are per-iteration in go1.14,
+// without -d=loopvar.
+
+package main
+
+import "fmt"
+
+func main() {
+	var fs []func() int
+	for i := 0; i < 3; i++ {
+		fs = append(fs, func() int { return i })
+	}
+	var ps []*string
+	for _, s := range []string{"a", "b", "c"} {
+		ps = append(ps, &s)
+	}
+	for i, f := range fs {
+		if got := f(); got != i {
+			panic(fmt.Sprintf("closure %d returned %d", i, got))
+		}
+	}
+	if got := *ps[0] + *ps[1] + *ps[2]; got != "abc" {
+		panic(fmt.Sprintf("pointers refer to %q, want %q", got, "abc"))
+	}
+}
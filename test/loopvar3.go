@@ -0,0 +1,31 @@
+// run -gcflags=-lang=go1.13
+
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that loop variables are still shared by all iterations
+// before go1.14.
+
+package main
+
+import "fmt"
+
+func main() {
+	var fs []func() int
+	for i := 0; i < 3; i++ {
+		fs = append(fs, func() int { return i })
+	}
+	var ps []*string
+	for _, s := range []string{"a", "b", "c"} {
+		ps = append(ps, &s)
+	}
+	for i, f := range fs {
+		if got := f(); got != 3 {
+			panic(fmt.Sprintf("closure %d returned %d, want 3", i, got))
+		}
+	}
+	if got := *ps[0] + *ps[1] + *ps[2]; got != "ccc" {
+		panic(fmt.Sprintf("pointers refer to %q, want %q", got, "ccc"))
+	}
+}
@@ -0,0 +1,25 @@
+// errorcheck -0 -d=loopvar=2
+
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test the report of per-iteration loop variables.
+
+package p
+
+func f(s []int) (fs []func() int, ps []*int) {
+	for i := range s { // ERROR "loop variable i now per-iteration, heap-allocated"
+		fs = append(fs, func() int { i++; return i })
+	}
+	for i := 0; i < len(s); i++ { // ERROR "loop variable i now per-iteration, heap-allocated"
+		ps = append(ps, &i)
+	}
+	for _, v := range s { // ERROR "loop variable v now per-iteration, stack-allocated"
+		func() { println(v) }()
+	}
+	for i, v := range s {
+		println(i, v)
+	}
+	return
+}